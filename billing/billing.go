@@ -0,0 +1,320 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package billing estimates the monthly cost of Workflows from the plans, the subscription and
+// the StepCount of the executions.
+//
+// Estimate totals the steps consumed since the beginning of the billing month over all the
+// workflows, projects them linearly to the end of the month, prices them with the plan applied
+// this month including the overage and the tax, and finds the cheapest plan for that usage.
+//
+// The steps are counted from the executions the API still lists, as the subscription does not
+// expose the billed steps. Executions purged and workflows deleted during the month are not
+// counted although they were billed, so the estimate can be lower than the invoice.
+//
+//	report, err := billing.Estimate(ctx, client, time.Now())
+//	fmt.Println(report.Projected.Total, report.Recommended.Name)
+//
+// Guard wraps ExecutionAPI and refuses to create executions with ErrBudgetExceeded once the
+// projection exceeds a monthly budget of steps or yen.
+package billing
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// Location 請求月の区切りに使うタイムゾーン(日本時間)
+var Location = time.FixedZone("JST", 9*60*60)
+
+// pageLimit ワークフローと実行を列挙する際の1ページあたりの件数
+const pageLimit = 100
+
+// Plan 料金プラン
+type Plan struct {
+	ID                  int    `json:"id"`
+	Name                string `json:"name"`
+	Grade               int    `json:"grade"`
+	BasePrice           int    `json:"basePrice"`
+	IncludedSteps       int    `json:"includedSteps"`
+	OverageStepUnit     int    `json:"overageStepUnit"`
+	OveragePricePerUnit int    `json:"overagePricePerUnit"`
+}
+
+// PlanOf converts a plan of SubscriptionAPI.ListPlans
+func PlanOf(p v1.ListPlansOKPlansItem) Plan {
+	return Plan{
+		ID:                  p.ID,
+		Name:                p.Name,
+		Grade:               p.Grade,
+		BasePrice:           p.BasePrice,
+		IncludedSteps:       p.IncludedSteps,
+		OverageStepUnit:     p.OverageStepUnit,
+		OveragePricePerUnit: p.OveragePricePerUnit,
+	}
+}
+
+// Cost ある利用量に対する1か月の料金。金額は円
+type Cost struct {
+	Steps int `json:"steps"`
+	// OverageSteps 含まれるステップ数を超えた分
+	OverageSteps int `json:"overageSteps"`
+	// OverageUnits 超過料金の課金単位の数。OverageStepUnit未満の端数は切り上げる
+	OverageUnits int `json:"overageUnits"`
+	Base         int `json:"base"`
+	Overage      int `json:"overage"`
+	Subtotal     int `json:"subtotal"`
+	// Tax 消費税。1円未満は切り捨てる
+	Tax   int `json:"tax"`
+	Total int `json:"total"`
+}
+
+// Cost returns the monthly cost of the plan for the steps, with the tax rate in percent
+func (p Plan) Cost(steps, taxRate int) Cost {
+	c := Cost{Steps: steps, Base: p.BasePrice}
+	c.OverageSteps = max(0, steps-p.IncludedSteps)
+	if c.OverageSteps > 0 && p.OverageStepUnit > 0 {
+		c.OverageUnits = (c.OverageSteps + p.OverageStepUnit - 1) / p.OverageStepUnit
+		c.Overage = c.OverageUnits * p.OveragePricePerUnit
+	}
+	c.Subtotal = c.Base + c.Overage
+	c.Tax = c.Subtotal * taxRate / 100
+	c.Total = c.Subtotal + c.Tax
+	return c
+}
+
+// WorkflowUsage 1つのワークフローの今月の利用量
+type WorkflowUsage struct {
+	WorkflowID string   `json:"workflowId"`
+	Name       string   `json:"name"`
+	Tags       []string `json:"tags"`
+	Executions int      `json:"executions"`
+	Steps      int      `json:"steps"`
+	// Cost 月末の見込み料金(税込)のうち、ステップ数の比で按分した分
+	Cost int `json:"cost"`
+}
+
+// TagUsage 1つのタグが付いたワークフローの利用量の合計。複数のタグが付いたワークフローはそれぞれに数える
+type TagUsage struct {
+	// Tag タグの名前。タグのないワークフローは空文字列にまとめる
+	Tag       string `json:"tag"`
+	Workflows int    `json:"workflows"`
+	Steps     int    `json:"steps"`
+	Cost      int    `json:"cost"`
+}
+
+// PlanCost 見込みの利用量に対するプランごとの料金
+type PlanCost struct {
+	Plan Plan `json:"plan"`
+	Cost Cost `json:"cost"`
+}
+
+// Report 今月の利用量と料金の見込み
+type Report struct {
+	// Month 請求月の初め
+	Month time.Time `json:"month"`
+	AsOf  time.Time `json:"asOf"`
+	// Plan 今月適用されるプラン
+	Plan    Plan `json:"plan"`
+	TaxRate int  `json:"taxRate"`
+	// Current これまでに消費したステップ数に対する料金
+	Current Cost `json:"current"`
+	// Projected これまでの利用ペースが月末まで続いた場合の料金
+	Projected Cost `json:"projected"`
+	// Workflows ステップ数の多い順に並ぶ
+	Workflows []WorkflowUsage `json:"workflows"`
+	// Tags ステップ数の多い順に並ぶ
+	Tags []TagUsage `json:"tags"`
+	// Plans 全てのプランの見込み料金。安い順に並ぶ
+	Plans []PlanCost `json:"plans"`
+	// Recommended 見込みの利用量に対して最も安いプラン
+	Recommended Plan `json:"recommended"`
+}
+
+// MonthOf returns the beginning of the billing month containing t
+func MonthOf(t time.Time) time.Time {
+	t = t.In(Location)
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, Location)
+}
+
+// Estimate reads the plans, the subscription and the executions of every workflow created in
+// the billing month of now, and estimates the cost of the month.
+func Estimate(ctx context.Context, client *workflows.Client, now time.Time) (*Report, error) {
+	plans, err := client.Subscription.ListPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := client.Subscription.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := AppliedPlan(sub, plans)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := CollectUsage(ctx, client, MonthOf(now))
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]Plan, 0, len(plans.Plans))
+	for _, p := range plans.Plans {
+		all = append(all, PlanOf(p))
+	}
+	return Compute(applied, all, plans.TaxRate, usage, now), nil
+}
+
+// AppliedPlan returns the plan applied this month, falling back to the current plan
+func AppliedPlan(sub *v1.GetSubscriptionOK, plans *v1.ListPlansOK) (Plan, error) {
+	if p, ok := sub.MonthAppliedPlan.Get(); ok {
+		return Plan{
+			ID:                  p.PlanId,
+			Name:                p.PlanName,
+			Grade:               p.PlanGrade,
+			BasePrice:           p.BasePrice,
+			IncludedSteps:       p.IncludedSteps,
+			OverageStepUnit:     p.OverageStepUnit,
+			OveragePricePerUnit: p.OveragePricePerUnit,
+		}, nil
+	}
+	if cur, ok := sub.CurrentPlan.Get(); ok {
+		for _, p := range plans.Plans {
+			if p.ID == cur.PlanId {
+				return PlanOf(p), nil
+			}
+		}
+		return Plan{}, fmt.Errorf("billing: current plan %d is not in the plan list", cur.PlanId)
+	}
+	return Plan{}, fmt.Errorf("billing: no plan is subscribed")
+}
+
+// CollectUsage totals the steps of the executions created since month, per workflow.
+// Purged executions and deleted workflows are not counted.
+func CollectUsage(ctx context.Context, client *workflows.Client, month time.Time) ([]WorkflowUsage, error) {
+	var ret []WorkflowUsage
+	for page, seen := 1, 0; ; page++ {
+		res, err := client.Workflows.List(ctx, v1.ListWorkflowParams{Page: v1.NewOptInt(page), PageLimit: v1.NewOptInt(pageLimit)})
+		if err != nil {
+			return nil, err
+		}
+		for _, wf := range res.Workflows {
+			u := WorkflowUsage{WorkflowID: wf.ID, Name: wf.Name}
+			for _, tag := range wf.Tags {
+				u.Tags = append(u.Tags, tag.Name)
+			}
+			if err := countSteps(ctx, client, &u, month); err != nil {
+				return nil, err
+			}
+			ret = append(ret, u)
+		}
+		seen += len(res.Workflows)
+		if len(res.Workflows) < pageLimit || seen >= res.Total {
+			return ret, nil
+		}
+	}
+}
+
+// countSteps adds up the executions newest first until one created before month
+func countSteps(ctx context.Context, client *workflows.Client, u *WorkflowUsage, month time.Time) error {
+	for page, seen := 1, 0; ; page++ {
+		res, err := client.Executions.List(ctx, v1.ListExecutionParams{
+			ID:        u.WorkflowID,
+			Page:      v1.NewOptInt(page),
+			PageLimit: v1.NewOptInt(pageLimit),
+			Order:     v1.NewOptListExecutionOrder(v1.ListExecutionOrderDesc),
+		})
+		if err != nil {
+			return err
+		}
+		for _, e := range res.Executions {
+			if e.CreatedAt.Before(month) {
+				return nil
+			}
+			u.Executions++
+			u.Steps += e.StepCount
+		}
+		seen += len(res.Executions)
+		if len(res.Executions) < pageLimit || seen >= res.Total {
+			return nil
+		}
+	}
+}
+
+// Compute prices the usage observed until now with the applied plan, projects it to the end of
+// the month and compares every plan. It makes no API calls.
+func Compute(applied Plan, plans []Plan, taxRate int, usage []WorkflowUsage, now time.Time) *Report {
+	month := MonthOf(now)
+	r := &Report{Month: month, AsOf: now, Plan: applied, TaxRate: taxRate}
+
+	var steps int
+	for _, u := range usage {
+		steps += u.Steps
+	}
+	projected := steps
+	if elapsed := now.Sub(month); elapsed > 0 {
+		total := month.AddDate(0, 1, 0).Sub(month)
+		projected = int(float64(steps) * float64(total) / float64(elapsed))
+	}
+	r.Current = applied.Cost(steps, taxRate)
+	r.Projected = applied.Cost(projected, taxRate)
+
+	r.Workflows = slices.Clone(usage)
+	tags := map[string]*TagUsage{}
+	for i := range r.Workflows {
+		u := &r.Workflows[i]
+		if steps > 0 {
+			u.Cost = r.Projected.Total * u.Steps / steps
+		}
+		names := u.Tags
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		for _, name := range names {
+			t, ok := tags[name]
+			if !ok {
+				t = &TagUsage{Tag: name}
+				tags[name] = t
+			}
+			t.Workflows++
+			t.Steps += u.Steps
+			t.Cost += u.Cost
+		}
+	}
+	slices.SortStableFunc(r.Workflows, func(a, b WorkflowUsage) int { return cmp.Compare(b.Steps, a.Steps) })
+	for _, t := range tags {
+		r.Tags = append(r.Tags, *t)
+	}
+	slices.SortFunc(r.Tags, func(a, b TagUsage) int {
+		return cmp.Or(cmp.Compare(b.Steps, a.Steps), cmp.Compare(a.Tag, b.Tag))
+	})
+
+	for _, p := range plans {
+		r.Plans = append(r.Plans, PlanCost{Plan: p, Cost: p.Cost(projected, taxRate)})
+	}
+	slices.SortStableFunc(r.Plans, func(a, b PlanCost) int {
+		return cmp.Or(cmp.Compare(a.Cost.Total, b.Cost.Total), cmp.Compare(a.Plan.Grade, b.Plan.Grade))
+	})
+	if len(r.Plans) > 0 {
+		r.Recommended = r.Plans[0].Plan
+	} else {
+		r.Recommended = applied
+	}
+	return r
+}
@@ -0,0 +1,121 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package billing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/billing"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	basic    = v1.ListPlansOKPlansItem{ID: 1, Name: "basic", Grade: 1, BasePrice: 0, IncludedSteps: 1000, OverageStepUnit: 100, OveragePricePerUnit: 50}
+	standard = v1.ListPlansOKPlansItem{ID: 2, Name: "standard", Grade: 2, BasePrice: 3000, IncludedSteps: 20000, OverageStepUnit: 1000, OveragePricePerUnit: 100}
+)
+
+func TestPlan_Cost(t *testing.T) {
+	p := billing.PlanOf(basic)
+	assert.Equal(t, billing.Cost{Steps: 900}, p.Cost(900, 10))
+	assert.Equal(t, billing.Cost{
+		Steps: 1150, OverageSteps: 150, OverageUnits: 2,
+		Overage: 100, Subtotal: 100, Tax: 10, Total: 110,
+	}, p.Cost(1150, 10))
+
+	p = billing.PlanOf(standard)
+	c := p.Cost(21001, 10)
+	assert.Equal(t, 2, c.OverageUnits)
+	assert.Equal(t, 3200, c.Subtotal)
+	assert.Equal(t, 3520, c.Total)
+}
+
+func TestEstimate(t *testing.T) {
+	// 10 of the 31 days of January have passed
+	now := time.Date(2025, 1, 11, 0, 0, 0, 0, billing.Location)
+	month := billing.MonthOf(now)
+
+	subscription := workflowsmock.NewSubscriptionAPI(t)
+	subscription.OnListPlans(mock.Anything).Return(workflowsmock.Plans(10, basic, standard), nil)
+	subscription.OnRead(mock.Anything).Return(&v1.GetSubscriptionOK{
+		IsOk: true,
+		MonthAppliedPlan: v1.NewOptGetSubscriptionOKMonthAppliedPlan(v1.GetSubscriptionOKMonthAppliedPlan{
+			PlanId: 1, PlanName: "basic", PlanGrade: 1,
+			IncludedSteps: 1000, OverageStepUnit: 100, OveragePricePerUnit: 50,
+		}),
+	}, nil)
+
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnList(mock.Anything, mock.Anything).Return(workflowsmock.WorkflowList(
+		workflowsmock.Workflow{ID: "wf1", Name: "etl", Tags: []string{"team-a", "batch"}},
+		workflowsmock.Workflow{ID: "wf2", Name: "report", Tags: []string{"team-a"}},
+		workflowsmock.Workflow{ID: "wf3", Name: "misc"},
+	), nil)
+
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnList(mock.Anything, mock.MatchedBy(func(p v1.ListExecutionParams) bool { return p.ID == "wf1" })).Return(workflowsmock.ExecutionList(
+		workflowsmock.Execution{ID: "e3", StepCount: 3000, CreatedAt: month.Add(48 * time.Hour)},
+		workflowsmock.Execution{ID: "e2", StepCount: 600, CreatedAt: month.Add(time.Hour)},
+		workflowsmock.Execution{ID: "e1", StepCount: 9999, CreatedAt: month.Add(-time.Hour)},
+	), nil)
+	executions.OnList(mock.Anything, mock.MatchedBy(func(p v1.ListExecutionParams) bool { return p.ID == "wf2" })).Return(workflowsmock.ExecutionList(
+		workflowsmock.Execution{ID: "e4", StepCount: 400, CreatedAt: month.Add(72 * time.Hour)},
+	), nil)
+	executions.OnList(mock.Anything, mock.MatchedBy(func(p v1.ListExecutionParams) bool { return p.ID == "wf3" })).Return(workflowsmock.ExecutionList(), nil)
+
+	client := &workflows.Client{Workflows: wfs, Executions: executions, Subscription: subscription}
+	r, err := billing.Estimate(t.Context(), client, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "basic", r.Plan.Name)
+	assert.Equal(t, 4000, r.Current.Steps)
+	assert.Equal(t, 1650, r.Current.Total)
+	assert.Equal(t, 12400, r.Projected.Steps)
+	assert.Equal(t, 6270, r.Projected.Total)
+
+	require.Len(t, r.Workflows, 3)
+	assert.Equal(t, "wf1", r.Workflows[0].WorkflowID)
+	assert.Equal(t, 2, r.Workflows[0].Executions)
+	assert.Equal(t, 3600, r.Workflows[0].Steps)
+	assert.Equal(t, r.Projected.Total*9/10, r.Workflows[0].Cost)
+	assert.Equal(t, 0, r.Workflows[2].Cost)
+
+	require.Len(t, r.Tags, 3)
+	assert.Equal(t, billing.TagUsage{Tag: "team-a", Workflows: 2, Steps: 4000, Cost: r.Workflows[0].Cost + r.Workflows[1].Cost}, r.Tags[0])
+	assert.Equal(t, "batch", r.Tags[1].Tag)
+	assert.Equal(t, "", r.Tags[2].Tag)
+
+	require.Len(t, r.Plans, 2)
+	assert.Equal(t, "standard", r.Recommended.Name)
+	assert.Equal(t, 3300, r.Plans[0].Cost.Total)
+}
+
+func TestAppliedPlan(t *testing.T) {
+	plans := workflowsmock.Plans(10, basic, standard)
+
+	p, err := billing.AppliedPlan(&v1.GetSubscriptionOK{
+		CurrentPlan: v1.NewOptNilGetSubscriptionOKCurrentPlan(v1.GetSubscriptionOKCurrentPlan{PlanId: 2}),
+	}, plans)
+	require.NoError(t, err)
+	assert.Equal(t, billing.PlanOf(standard), p)
+
+	_, err = billing.AppliedPlan(&v1.GetSubscriptionOK{}, plans)
+	assert.EqualError(t, err, "billing: no plan is subscribed")
+}
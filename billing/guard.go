@@ -0,0 +1,239 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package billing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// DefaultThresholds 既定で警告する予算の消化率
+var DefaultThresholds = []float64{0.5, 0.8, 0.9}
+
+// DefaultRefreshInterval 見込みを計算し直す既定の間隔
+const DefaultRefreshInterval = 5 * time.Minute
+
+// Budget 1か月の予算。0の項目は制限しない
+type Budget struct {
+	// Steps 月末の見込みステップ数の上限
+	Steps int `json:"steps"`
+	// Yen 月末の見込み料金(税込)の上限
+	Yen int `json:"yen"`
+}
+
+// usage returns the larger of the ratios of the projection to the budget
+func (b Budget) usage(r *Report) float64 {
+	var ratio float64
+	if b.Steps > 0 {
+		ratio = max(ratio, float64(r.Projected.Steps)/float64(b.Steps))
+	}
+	if b.Yen > 0 {
+		ratio = max(ratio, float64(r.Projected.Total)/float64(b.Yen))
+	}
+	return ratio
+}
+
+// ErrBudgetExceeded 見込みが予算を超えたためにGuardが実行の作成を拒否したことを表すエラー
+//
+//	var e billing.ErrBudgetExceeded
+//	if errors.As(err, &e) { ... }
+type ErrBudgetExceeded struct {
+	WorkflowID string
+	Budget     Budget
+	// Usage 予算に対する見込みの比率。1を超える
+	Usage  float64
+	Report *Report
+}
+
+func (e ErrBudgetExceeded) Error() string {
+	return fmt.Sprintf("billing: monthly budget exceeded: projected %d steps / %d yen is %.0f%% of the budget",
+		e.Report.Projected.Steps, e.Report.Projected.Total, e.Usage*100)
+}
+
+// Warning 見込みが警告の閾値を超えたことの通知
+type Warning struct {
+	Threshold float64
+	Usage     float64
+	Budget    Budget
+	Report    *Report
+}
+
+// Option NewGuardに渡す設定
+type Option func(*Guard)
+
+// WithThresholds sets the ratios of the budget at which a warning is issued. Defaults to DefaultThresholds.
+func WithThresholds(thresholds ...float64) Option {
+	return func(g *Guard) { g.thresholds = slices.Sorted(slices.Values(thresholds)) }
+}
+
+// WithWarningHandler calls f once per threshold and month when the projection crosses it.
+// Warnings are logged to the logger when no handler is set.
+func WithWarningHandler(f func(ctx context.Context, w Warning)) Option {
+	return func(g *Guard) { g.onWarning = f }
+}
+
+// WithLogger sets the logger of the warnings. Defaults to slog.Default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(g *Guard) { g.logger = logger }
+}
+
+// WithAllowOverride lets the executions created with a context from Override through even if the
+// budget is exceeded. Without it the guard refuses them as well.
+func WithAllowOverride(allow bool) Option {
+	return func(g *Guard) { g.allowOverride = allow }
+}
+
+// WithRefreshInterval sets how long an estimate is reused. Defaults to DefaultRefreshInterval.
+func WithRefreshInterval(d time.Duration) Option {
+	return func(g *Guard) { g.refresh = d }
+}
+
+// WithClock sets the function returning the current time. Defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(g *Guard) { g.now = now }
+}
+
+type overrideKey struct{}
+
+// Override returns a context with which the Guard created with WithAllowOverride(true) lets an
+// execution through regardless of the budget
+func Override(ctx context.Context) context.Context {
+	return context.WithValue(ctx, overrideKey{}, true)
+}
+
+func overridden(ctx context.Context) bool {
+	v, _ := ctx.Value(overrideKey{}).(bool)
+	return v
+}
+
+// Guard 月の予算を超える見込みの場合に実行の作成を拒否するExecutionAPI
+//
+// Create以外のメソッドは元のExecutionAPIをそのまま呼び出す。
+type Guard struct {
+	workflows.ExecutionAPI
+
+	client        *workflows.Client
+	budget        Budget
+	thresholds    []float64
+	onWarning     func(context.Context, Warning)
+	logger        *slog.Logger
+	allowOverride bool
+	refresh       time.Duration
+	now           func() time.Time
+
+	mu        sync.Mutex
+	report    *Report
+	updatedAt time.Time
+	warned    map[float64]time.Time
+}
+
+var _ workflows.ExecutionAPI = (*Guard)(nil)
+
+// NewGuard wraps client.Executions with a guard for the budget. Install it with
+//
+//	client.Executions = billing.NewGuard(client, billing.Budget{Yen: 10000})
+func NewGuard(client *workflows.Client, budget Budget, opts ...Option) *Guard {
+	inner := *client
+	g := &Guard{
+		ExecutionAPI: client.Executions,
+		client:       &inner,
+		budget:       budget,
+		thresholds:   DefaultThresholds,
+		refresh:      DefaultRefreshInterval,
+		now:          time.Now,
+		warned:       map[float64]time.Time{},
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.logger == nil {
+		g.logger = slog.Default()
+	}
+	return g
+}
+
+// Create checks the projected cost of the month before creating the execution.
+// It returns ErrBudgetExceeded without creating the execution when the projection exceeds the budget.
+func (g *Guard) Create(ctx context.Context, workflowID string, req v1.OptCreateExecutionReq) (*v1.CreateExecutionCreatedExecution, error) {
+	if err := g.Check(ctx, workflowID); err != nil {
+		return nil, err
+	}
+	return g.ExecutionAPI.Create(ctx, workflowID, req)
+}
+
+// Check estimates the cost of the month if the last estimate is stale, issues the warnings and
+// reports whether an execution of the workflow may be created
+func (g *Guard) Check(ctx context.Context, workflowID string) error {
+	r, err := g.Report(ctx)
+	if err != nil {
+		return err
+	}
+	usage := g.budget.usage(r)
+	g.warn(ctx, r, usage)
+	if usage <= 1 || (g.allowOverride && overridden(ctx)) {
+		return nil
+	}
+	return ErrBudgetExceeded{WorkflowID: workflowID, Budget: g.budget, Usage: usage, Report: r}
+}
+
+// Report returns the estimate of the month, computing it again after the refresh interval
+func (g *Guard) Report(ctx context.Context) (*Report, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	if g.report != nil && now.Sub(g.updatedAt) < g.refresh && MonthOf(now).Equal(g.report.Month) {
+		return g.report, nil
+	}
+	r, err := Estimate(ctx, g.client, now)
+	if err != nil {
+		return nil, err
+	}
+	g.report, g.updatedAt = r, now
+	return r, nil
+}
+
+// warn issues the warning of the highest threshold crossed, once a month
+func (g *Guard) warn(ctx context.Context, r *Report, usage float64) {
+	g.mu.Lock()
+	var crossed float64
+	for _, t := range g.thresholds {
+		if usage >= t && !g.warned[t].Equal(r.Month) {
+			crossed = t
+			g.warned[t] = r.Month
+		}
+	}
+	g.mu.Unlock()
+	if crossed == 0 {
+		return
+	}
+
+	w := Warning{Threshold: crossed, Usage: usage, Budget: g.budget, Report: r}
+	if g.onWarning != nil {
+		g.onWarning(ctx, w)
+		return
+	}
+	g.logger.WarnContext(ctx, "monthly budget threshold reached",
+		"threshold", crossed, "usage", usage,
+		"projectedSteps", r.Projected.Steps, "projectedYen", r.Projected.Total,
+		"budgetSteps", g.budget.Steps, "budgetYen", g.budget.Yen)
+}
@@ -0,0 +1,109 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package billing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/billing"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// guardClient 1つのワークフローが月の半ばまでにstepsを消費したClient
+func guardClient(t *testing.T, now time.Time, steps int) (*workflows.Client, *workflowsmock.ExecutionAPI) {
+	subscription := workflowsmock.NewSubscriptionAPI(t)
+	subscription.OnListPlans(mock.Anything).Return(workflowsmock.Plans(10, basic), nil).Maybe()
+	subscription.OnRead(mock.Anything).Return(&v1.GetSubscriptionOK{
+		CurrentPlan: v1.NewOptNilGetSubscriptionOKCurrentPlan(v1.GetSubscriptionOKCurrentPlan{PlanId: 1}),
+	}, nil).Maybe()
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnList(mock.Anything, mock.Anything).Return(workflowsmock.WorkflowList(workflowsmock.Workflow{ID: "wf", Name: "wf"}), nil).Maybe()
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.ExecutionList(
+		workflowsmock.Execution{ID: "e1", StepCount: steps, CreatedAt: billing.MonthOf(now).Add(time.Hour)},
+	), nil).Maybe()
+	return &workflows.Client{Workflows: wfs, Executions: executions, Subscription: subscription}, executions
+}
+
+func TestGuard_Create(t *testing.T) {
+	// half of April has passed, so the projection is twice the steps
+	now := time.Date(2025, 4, 16, 0, 0, 0, 0, billing.Location)
+
+	t.Run("within budget", func(t *testing.T) {
+		client, executions := guardClient(t, now, 400)
+		executions.OnCreate(mock.Anything, "wf", mock.Anything).Return(workflowsmock.Execution{ID: "e2"}.Created(), nil).Once()
+
+		var warnings []billing.Warning
+		client.Executions = billing.NewGuard(client, billing.Budget{Steps: 1000}, billing.WithClock(func() time.Time { return now }),
+			billing.WithWarningHandler(func(_ context.Context, w billing.Warning) { warnings = append(warnings, w) }))
+		_, err := client.Executions.Create(t.Context(), "wf", v1.OptCreateExecutionReq{})
+		require.NoError(t, err)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, 0.8, warnings[0].Threshold, "only the highest threshold crossed")
+		assert.InDelta(t, 0.8, warnings[0].Usage, 0.01)
+	})
+
+	t.Run("exceeded", func(t *testing.T) {
+		client, _ := guardClient(t, now, 3000)
+		guard := billing.NewGuard(client, billing.Budget{Yen: 1000}, billing.WithClock(func() time.Time { return now }),
+			billing.WithWarningHandler(func(context.Context, billing.Warning) {}))
+		_, err := guard.Create(t.Context(), "wf", v1.OptCreateExecutionReq{})
+		var e billing.ErrBudgetExceeded
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, "wf", e.WorkflowID)
+		assert.Equal(t, 2750, e.Report.Projected.Total)
+		assert.EqualError(t, err, "billing: monthly budget exceeded: projected 6000 steps / 2750 yen is 275% of the budget")
+
+		// the override is ignored unless allowed
+		_, err = guard.Create(billing.Override(t.Context()), "wf", v1.OptCreateExecutionReq{})
+		assert.ErrorAs(t, err, &e)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		client, executions := guardClient(t, now, 3000)
+		executions.OnCreate(mock.Anything, "wf", mock.Anything).Return(workflowsmock.Execution{ID: "e2"}.Created(), nil).Once()
+		guard := billing.NewGuard(client, billing.Budget{Steps: 1000}, billing.WithAllowOverride(true),
+			billing.WithClock(func() time.Time { return now }), billing.WithWarningHandler(func(context.Context, billing.Warning) {}))
+		_, err := guard.Create(t.Context(), "wf", v1.OptCreateExecutionReq{})
+		require.Error(t, err)
+		_, err = guard.Create(billing.Override(t.Context()), "wf", v1.OptCreateExecutionReq{})
+		require.NoError(t, err)
+	})
+}
+
+func TestGuard_Report_refresh(t *testing.T) {
+	now := time.Date(2025, 4, 16, 0, 0, 0, 0, billing.Location)
+	client, _ := guardClient(t, now, 100)
+	guard := billing.NewGuard(client, billing.Budget{Steps: 1000}, billing.WithClock(func() time.Time { return now }))
+
+	r1, err := guard.Report(t.Context())
+	require.NoError(t, err)
+	r2, err := guard.Report(t.Context())
+	require.NoError(t, err)
+	assert.Same(t, r1, r2)
+
+	now = now.Add(billing.DefaultRefreshInterval)
+	r3, err := guard.Report(t.Context())
+	require.NoError(t, err)
+	assert.NotSame(t, r1, r3)
+}
@@ -0,0 +1,237 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"errors"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sacloud/saclient-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// DefaultBulkConcurrency CancelAll/PurgeExecutionsが同時に送るリクエスト数の既定値
+const DefaultBulkConcurrency = 4
+
+// bulkPageLimit 対象の実行を列挙する際の1ページあたりの件数
+const bulkPageLimit = 100
+
+// ExecutionFilter CancelAll/PurgeExecutionsの対象とする実行の条件。ゼロ値の条件は無視される
+type ExecutionFilter struct {
+	// Statuses 対象とする状態。空の場合は操作ごとの既定値を使う
+	Statuses []v1.ListExecutionOKExecutionsItemStatus
+	// OlderThan 作成からこの時間以上経過した実行のみを対象とする
+	OlderThan time.Duration
+	// Revision このリビジョンで実行されたもののみを対象とする
+	Revision int
+	// Name 実行名にマッチするpath.Match形式のパターン
+	Name string
+}
+
+// BulkOptions CancelAll/PurgeExecutionsの設定
+type BulkOptions struct {
+	// Concurrency 同時に送るリクエスト数。0の場合はDefaultBulkConcurrency
+	Concurrency int
+	// DryRun trueの場合、対象を列挙するだけで操作しない
+	DryRun bool
+}
+
+// BulkOutcome 1件の実行に対する操作の結果
+type BulkOutcome string
+
+const (
+	// BulkDone 操作に成功した
+	BulkDone BulkOutcome = "done"
+	// BulkDryRun DryRunのため操作しなかった
+	BulkDryRun BulkOutcome = "dry-run"
+	// BulkSkipped 列挙後に実行が終了・削除されるなどして、APIが操作を受け付けなかった(409, 404)
+	BulkSkipped BulkOutcome = "skipped"
+	// BulkFailed 操作に失敗した
+	BulkFailed BulkOutcome = "failed"
+)
+
+// BulkResult 1件の実行に対する操作の結果
+type BulkResult struct {
+	Execution v1.ListExecutionOKExecutionsItem
+	Outcome   BulkOutcome
+	// Err BulkSkipped, BulkFailedの場合の理由
+	Err error
+}
+
+// BulkReport CancelAll/PurgeExecutionsの結果。Resultsは列挙した順に並ぶ
+type BulkReport struct {
+	WorkflowID string
+	DryRun     bool
+	Results    []BulkResult
+}
+
+// Count returns the number of results with the outcome
+func (r *BulkReport) Count(outcome BulkOutcome) int {
+	var n int
+	for _, res := range r.Results {
+		if res.Outcome == outcome {
+			n++
+		}
+	}
+	return n
+}
+
+// Err returns the errors of the failed results joined, or nil if nothing failed.
+// Skipped results are not errors.
+func (r *BulkReport) Err() error {
+	var errs []error
+	for _, res := range r.Results {
+		if res.Outcome == BulkFailed {
+			errs = append(errs, res.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CancelAll cancels every execution of the workflow that matches the filter.
+// Without Statuses in the filter, Queued and Running executions are targeted.
+//
+// Executions which finished in the meantime are rejected by the API with C-0050 and reported as
+// BulkSkipped. The returned error is only for failures of listing the executions; check
+// BulkReport.Err for failures of each cancellation.
+func (c *Client) CancelAll(ctx context.Context, workflowID string, filter ExecutionFilter, opts BulkOptions) (*BulkReport, error) {
+	if len(filter.Statuses) == 0 {
+		filter.Statuses = []v1.ListExecutionOKExecutionsItemStatus{
+			v1.ListExecutionOKExecutionsItemStatusQueued,
+			v1.ListExecutionOKExecutionsItemStatusRunning,
+		}
+	}
+	return c.bulk(ctx, workflowID, filter, opts, func(ctx context.Context, id string) error {
+		_, err := c.Executions.Cancel(ctx, workflowID, id)
+		return err
+	})
+}
+
+// PurgeExecutions deletes every execution of the workflow that matches the filter, e.g. those
+// older than 30 days for housekeeping. Without Statuses in the filter, finished executions
+// (Succeeded, Failed and Canceled) are targeted.
+//
+// Executions which cannot be deleted because they are still running are rejected by the API
+// with C-0060 and reported as BulkSkipped. The returned error is only for failures of listing
+// the executions; check BulkReport.Err for failures of each deletion.
+func (c *Client) PurgeExecutions(ctx context.Context, workflowID string, filter ExecutionFilter, opts BulkOptions) (*BulkReport, error) {
+	if len(filter.Statuses) == 0 {
+		filter.Statuses = []v1.ListExecutionOKExecutionsItemStatus{
+			v1.ListExecutionOKExecutionsItemStatusSucceeded,
+			v1.ListExecutionOKExecutionsItemStatusFailed,
+			v1.ListExecutionOKExecutionsItemStatusCanceled,
+		}
+	}
+	return c.bulk(ctx, workflowID, filter, opts, func(ctx context.Context, id string) error {
+		return c.Executions.Delete(ctx, workflowID, id)
+	})
+}
+
+func (c *Client) bulk(ctx context.Context, workflowID string, filter ExecutionFilter, opts BulkOptions, action func(context.Context, string) error) (*BulkReport, error) {
+	// list everything before acting, as deleting shifts the pages
+	targets, err := c.matchingExecutions(ctx, workflowID, filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BulkReport{WorkflowID: workflowID, DryRun: opts.DryRun, Results: make([]BulkResult, len(targets))}
+	if opts.DryRun {
+		for i, e := range targets {
+			report.Results[i] = BulkResult{Execution: e, Outcome: BulkDryRun}
+		}
+		return report, nil
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, e := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			report.Results[i] = BulkResult{Execution: e, Outcome: BulkDone}
+			if err := ctx.Err(); err != nil {
+				report.Results[i].Outcome, report.Results[i].Err = BulkFailed, err
+				return
+			}
+			if err := action(ctx, e.ExecutionId); err != nil {
+				report.Results[i].Err = err
+				if IsConflictError(err) || saclient.IsNotFoundError(err) {
+					report.Results[i].Outcome = BulkSkipped
+				} else {
+					report.Results[i].Outcome = BulkFailed
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return report, nil
+}
+
+func (c *Client) matchingExecutions(ctx context.Context, workflowID string, filter ExecutionFilter, now time.Time) ([]v1.ListExecutionOKExecutionsItem, error) {
+	if filter.Name != "" {
+		if _, err := path.Match(filter.Name, ""); err != nil {
+			return nil, NewError("invalid name pattern", err)
+		}
+	}
+
+	var ret []v1.ListExecutionOKExecutionsItem
+	for page, seen := 1, 0; ; page++ {
+		res, err := c.Executions.List(ctx, v1.ListExecutionParams{
+			ID:        workflowID,
+			Page:      v1.NewOptInt(page),
+			PageLimit: v1.NewOptInt(bulkPageLimit),
+			Order:     v1.NewOptListExecutionOrder(v1.ListExecutionOrderAsc),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range res.Executions {
+			if filter.match(e, now) {
+				ret = append(ret, e)
+			}
+		}
+		seen += len(res.Executions)
+		if len(res.Executions) < bulkPageLimit || seen >= res.Total {
+			return ret, nil
+		}
+	}
+}
+
+func (f ExecutionFilter) match(e v1.ListExecutionOKExecutionsItem, now time.Time) bool {
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, e.Status) {
+		return false
+	}
+	if f.OlderThan > 0 && e.CreatedAt.After(now.Add(-f.OlderThan)) {
+		return false
+	}
+	if f.Revision > 0 && e.Revision != f.Revision {
+		return false
+	}
+	if f.Name != "" {
+		if ok, _ := path.Match(f.Name, e.Name); !ok {
+			return false
+		}
+	}
+	return true
+}
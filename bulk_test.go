@@ -0,0 +1,83 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CancelAll(t *testing.T) {
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.ExecutionList(
+		workflowsmock.Execution{ID: "e1", Name: "nightly-1", Status: "Running", Revision: 1},
+		workflowsmock.Execution{ID: "e2", Name: "nightly-2", Status: "Queued", Revision: 1},
+		workflowsmock.Execution{ID: "e3", Name: "nightly-3", Status: "Succeeded", Revision: 1},
+		workflowsmock.Execution{ID: "e4", Name: "manual", Status: "Running", Revision: 1},
+		workflowsmock.Execution{ID: "e5", Name: "nightly-5", Status: "Running", Revision: 2},
+	), nil).Once()
+	executions.OnCancel(mock.Anything, "wf", "e1").Return(workflowsmock.Execution{ID: "e1", Status: "Canceling"}.Canceled(), nil).Once()
+	executions.OnCancel(mock.Anything, "wf", "e2").Return(nil, workflows.NewAPIError("Execution.Cancel", http.StatusConflict, errors.New("C-0050 This execution cannot be canceled."))).Once()
+
+	client := &workflows.Client{Executions: executions}
+	report, err := client.CancelAll(t.Context(), "wf", workflows.ExecutionFilter{Name: "nightly-*", Revision: 1}, workflows.BulkOptions{Concurrency: 2})
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, "e1", report.Results[0].Execution.ExecutionId)
+	assert.Equal(t, workflows.BulkDone, report.Results[0].Outcome)
+	assert.Equal(t, workflows.BulkSkipped, report.Results[1].Outcome)
+	assert.Equal(t, "C-0050", workflows.ErrorCode(report.Results[1].Err))
+	assert.NoError(t, report.Err())
+}
+
+func TestClient_PurgeExecutions(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -40)
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.ExecutionList(
+		workflowsmock.Execution{ID: "e1", Status: "Succeeded", CreatedAt: old},
+		workflowsmock.Execution{ID: "e2", Status: "Failed", CreatedAt: old},
+		workflowsmock.Execution{ID: "e3", Status: "Running", CreatedAt: old},
+		workflowsmock.Execution{ID: "e4", Status: "Succeeded", CreatedAt: time.Now()},
+	), nil)
+	filter := workflows.ExecutionFilter{OlderThan: 30 * 24 * time.Hour}
+
+	t.Run("dry run", func(t *testing.T) {
+		client := &workflows.Client{Executions: executions}
+		report, err := client.PurgeExecutions(t.Context(), "wf", filter, workflows.BulkOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.Count(workflows.BulkDryRun))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		executions.OnDelete(mock.Anything, "wf", "e1").Return(nil).Once()
+		executions.OnDelete(mock.Anything, "wf", "e2").Return(workflows.NewAPIError("Execution.Delete", http.StatusInternalServerError, errors.New("internal error"))).Once()
+
+		client := &workflows.Client{Executions: executions}
+		report, err := client.PurgeExecutions(t.Context(), "wf", filter, workflows.BulkOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Count(workflows.BulkDone))
+		assert.Equal(t, 1, report.Count(workflows.BulkFailed))
+		assert.ErrorContains(t, report.Err(), "internal error")
+	})
+}
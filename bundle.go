@@ -0,0 +1,362 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+const (
+	// BundleFormat Bundleのformatフィールドの値
+	BundleFormat = "sacloud-workflows-bundle"
+	// BundleVersion このパッケージが読み書きするBundleの形式のバージョン
+	BundleVersion = 1
+)
+
+// Bundle アカウントやゾーンをまたいでワークフローを移すための、またはバックアップのためのJSONアーカイブ
+type Bundle struct {
+	Format     string           `json:"format"`
+	Version    int              `json:"version"`
+	ExportedAt time.Time        `json:"exportedAt"`
+	Workflows  []BundleWorkflow `json:"workflows"`
+}
+
+// BundleWorkflow Bundleに含まれる1つのワークフロー
+type BundleWorkflow struct {
+	Workflow v1.GetWorkflowOKWorkflow `json:"workflow"`
+	// Revisions リビジョン番号の昇順に並ぶ
+	Revisions []BundleRevision `json:"revisions"`
+	// Executions 直近の実行の記録。Importでは復元されない
+	Executions []v1.ListExecutionOKExecutionsItem `json:"executions,omitempty"`
+}
+
+// BundleRevision Bundleに含まれる1つのリビジョン
+type BundleRevision struct {
+	Number    int       `json:"number"`
+	Alias     string    `json:"alias,omitempty"`
+	Runbook   string    `json:"runbook"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ExportOptions ExportWithOptionsの設定
+type ExportOptions struct {
+	// Executions 含める直近の実行の件数。0の場合は含めない
+	Executions int
+}
+
+// WriteTo writes the bundle as indented JSON
+func (b *Bundle) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return 0, NewError("unable to encode bundle", err)
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// ReadBundle reads a bundle written by Bundle.WriteTo
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	var b Bundle
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, NewError("unable to decode bundle", err)
+	}
+	if b.Format != BundleFormat {
+		return nil, NewError(fmt.Sprintf("not a workflows bundle: format %q", b.Format), nil)
+	}
+	if b.Version != BundleVersion {
+		return nil, NewError(fmt.Sprintf("unsupported bundle version %d", b.Version), nil)
+	}
+	return &b, nil
+}
+
+// Export reads the workflows and all their revisions into a bundle
+func (c *Client) Export(ctx context.Context, ids ...string) (*Bundle, error) {
+	return c.ExportWithOptions(ctx, ExportOptions{}, ids...)
+}
+
+// ExportWithOptions is Export with options, e.g. to include recent executions for a backup
+func (c *Client) ExportWithOptions(ctx context.Context, opts ExportOptions, ids ...string) (*Bundle, error) {
+	b := &Bundle{Format: BundleFormat, Version: BundleVersion, ExportedAt: time.Now().UTC()}
+	for _, id := range ids {
+		wf, err := c.Workflows.Read(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		bw := BundleWorkflow{Workflow: *wf}
+
+		revisions, err := c.listRevisions(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range revisions {
+			bw.Revisions = append(bw.Revisions, BundleRevision{Number: r.RevisionId, Alias: r.RevisionAlias.Value, Runbook: r.Runbook, CreatedAt: r.CreatedAt})
+		}
+		slices.SortFunc(bw.Revisions, func(a, b BundleRevision) int { return cmp.Compare(a.Number, b.Number) })
+
+		if opts.Executions > 0 {
+			res, err := c.Executions.List(ctx, v1.ListExecutionParams{
+				ID:        id,
+				Page:      v1.NewOptInt(1),
+				PageLimit: v1.NewOptInt(opts.Executions),
+				Order:     v1.NewOptListExecutionOrder(v1.ListExecutionOrderDesc),
+			})
+			if err != nil {
+				return nil, err
+			}
+			bw.Executions = res.Executions
+		}
+		b.Workflows = append(b.Workflows, bw)
+	}
+	return b, nil
+}
+
+// listRevisions reads every revision of the workflow
+func (c *Client) listRevisions(ctx context.Context, id string) ([]v1.ListWorkflowRevisionsOKRevisionsItem, error) {
+	var ret []v1.ListWorkflowRevisionsOKRevisionsItem
+	for page := 1; ; page++ {
+		res, err := c.Revisions.List(ctx, v1.ListWorkflowRevisionsParams{
+			ID:        id,
+			Page:      v1.NewOptInt(page),
+			PageLimit: v1.NewOptInt(bulkPageLimit),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, res.Revisions...)
+		if len(res.Revisions) < bulkPageLimit || len(ret) >= res.Total {
+			return ret, nil
+		}
+	}
+}
+
+// ConflictPolicy 同じ名前のワークフローが既にある場合のImportの振る舞い
+type ConflictPolicy string
+
+const (
+	// ConflictFail エラーにする。既定値
+	ConflictFail ConflictPolicy = ""
+	// ConflictSkip 取り込まない
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite 仮の名前で作り直してから既存のワークフローを削除し、元の名前に変更する。
+	// 作成に失敗した場合は既存のワークフローを残す。実行中の実行があると削除に失敗する
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictRename "name-2"のように重ならない名前で作る
+	ConflictRename ConflictPolicy = "rename"
+)
+
+// ImportOptions Importの設定
+type ImportOptions struct {
+	Conflict ConflictPolicy
+	// ServicePrincipalID 取り込んだワークフローに設定するサービスプリンシパル。
+	// ゼロ値の場合は設定しない。Bundle内のIDは元のアカウントのものなので使わない
+	ServicePrincipalID ServicePrincipalID
+}
+
+// ImportAction Importが1つのワークフローに対して行ったこと
+type ImportAction string
+
+const (
+	ImportCreated     ImportAction = "created"
+	ImportSkipped     ImportAction = "skipped"
+	ImportOverwritten ImportAction = "overwritten"
+	ImportRenamed     ImportAction = "renamed"
+)
+
+// ImportedWorkflow Importした1つのワークフロー
+type ImportedWorkflow struct {
+	SourceID string
+	// ID 作成したワークフローのID。ImportSkippedの場合は既存のワークフローのID
+	ID     string
+	Name   string
+	Action ImportAction
+	// Revisions 元のリビジョン番号から作成したリビジョン番号への対応
+	Revisions map[int]int
+}
+
+// ImportResult Importの結果
+type ImportResult struct {
+	Workflows []ImportedWorkflow
+}
+
+// IDMap returns the mapping from the workflow IDs in the bundle to those in this account
+func (r *ImportResult) IDMap() map[string]string {
+	ret := make(map[string]string, len(r.Workflows))
+	for _, w := range r.Workflows {
+		ret[w.SourceID] = w.ID
+	}
+	return ret
+}
+
+// Import creates the workflows of the bundle. The revisions are created in their original order,
+// each with its alias, so the aliases point to the same Runbooks as in the source.
+// Execution records in the bundle are not imported.
+//
+// The result is returned even on error, listing the workflows imported so far.
+func (c *Client) Import(ctx context.Context, bundle *Bundle, opts ImportOptions) (*ImportResult, error) {
+	result := &ImportResult{}
+	for _, bw := range bundle.Workflows {
+		if len(bw.Revisions) == 0 {
+			return result, NewError(fmt.Sprintf("workflow %s in the bundle has no revision", bw.Workflow.ID), nil)
+		}
+		imported, err := c.importWorkflow(ctx, bw, opts)
+		if err != nil {
+			return result, err
+		}
+		result.Workflows = append(result.Workflows, *imported)
+	}
+	return result, nil
+}
+
+func (c *Client) importWorkflow(ctx context.Context, bw BundleWorkflow, opts ImportOptions) (*ImportedWorkflow, error) {
+	src := bw.Workflow
+	imported := &ImportedWorkflow{SourceID: src.ID, Name: src.Name, Action: ImportCreated}
+
+	existing, err := c.findWorkflowByName(ctx, src.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != "" {
+		switch opts.Conflict {
+		case ConflictSkip:
+			imported.ID, imported.Action = existing, ImportSkipped
+			return imported, nil
+		case ConflictOverwrite:
+			// the existing workflow is deleted only once its replacement has been created
+			imported.Action = ImportOverwritten
+		case ConflictRename:
+			for n := 2; existing != ""; n++ {
+				imported.Name = fmt.Sprintf("%s-%d", src.Name, n)
+				if existing, err = c.findWorkflowByName(ctx, imported.Name); err != nil {
+					return nil, err
+				}
+			}
+			imported.Action = ImportRenamed
+		default:
+			return nil, NewError(fmt.Sprintf("workflow %q already exists as %s", src.Name, existing), nil)
+		}
+	}
+
+	if imported.Action != ImportOverwritten {
+		imported.ID, imported.Revisions, err = c.createImportedWorkflow(ctx, bw, imported.Name, opts)
+		if err != nil {
+			return nil, err
+		}
+		return imported, nil
+	}
+
+	// overwrite: create the replacement under a temporary name, then delete the existing one and take its name,
+	// so that the existing workflow is kept if the replacement cannot be created
+	temporary := importingName(src.Name)
+	imported.ID, imported.Revisions, err = c.createImportedWorkflow(ctx, bw, temporary, opts)
+	if err != nil {
+		if imported.ID != "" {
+			err = errors.Join(err, c.Workflows.Delete(ctx, imported.ID))
+		}
+		return nil, err
+	}
+	if err := c.Workflows.Delete(ctx, existing); err != nil {
+		return nil, errors.Join(err, c.Workflows.Delete(ctx, imported.ID))
+	}
+	if _, err := c.Workflows.Update(ctx, imported.ID, v1.UpdateWorkflowReq{Name: v1.NewOptString(imported.Name)}); err != nil {
+		return nil, NewError(fmt.Sprintf("workflow %q was replaced by %s but it is still named %q", src.Name, imported.ID, temporary), err)
+	}
+	return imported, nil
+}
+
+// createImportedWorkflow creates the workflow of the bundle named name with all its revisions.
+// The ID is returned even if a revision could not be created.
+func (c *Client) createImportedWorkflow(ctx context.Context, bw BundleWorkflow, name string, opts ImportOptions) (string, map[int]int, error) {
+	src := bw.Workflow
+	first := bw.Revisions[0]
+	req := v1.CreateWorkflowReq{
+		Name:        name,
+		Description: src.Description,
+		Runbook:     first.Runbook,
+		Publish:     src.Publish,
+		Logging:     src.Logging,
+		Tags:        []v1.CreateWorkflowReqTagsItem{},
+	}
+	for _, tag := range src.Tags {
+		req.Tags = append(req.Tags, v1.CreateWorkflowReqTagsItem{Name: tag.Name})
+	}
+	if first.Alias != "" {
+		req.RevisionAlias = v1.NewOptString(first.Alias)
+	}
+	if mode, ok := src.ConcurrencyMode.Get(); ok {
+		req.ConcurrencyMode = v1.NewOptCreateWorkflowReqConcurrencyMode(v1.CreateWorkflowReqConcurrencyMode(mode))
+	}
+	req.ServicePrincipalId = opts.ServicePrincipalID.CreateWorkflowReq()
+	created, err := c.Workflows.Create(ctx, req)
+	if err != nil {
+		return "", nil, err
+	}
+	// the workflow is created with its first revision
+	revisions := map[int]int{first.Number: 1}
+
+	for _, r := range bw.Revisions[1:] {
+		rreq := v1.CreateWorkflowRevisionReq{Runbook: r.Runbook}
+		if r.Alias != "" {
+			rreq.RevisionAlias = v1.NewOptString(r.Alias)
+		}
+		rev, err := c.Revisions.Create(ctx, created.ID, rreq)
+		if err != nil {
+			return created.ID, nil, err
+		}
+		revisions[r.Number] = rev.RevisionId
+	}
+	return created.ID, revisions, nil
+}
+
+// importingName returns the temporary name of the workflow replacing the one named name, within the 64 characters allowed
+func importingName(name string) string {
+	const suffix = "-importing"
+	if r := []rune(name); len(r)+len(suffix) > 64 {
+		name = string(r[:64-len(suffix)])
+	}
+	return name + suffix
+}
+
+// findWorkflowByName returns the ID of the workflow named exactly name, or "" if there is none
+func (c *Client) findWorkflowByName(ctx context.Context, name string) (string, error) {
+	for page, seen := 1, 0; ; page++ {
+		res, err := c.Workflows.List(ctx, v1.ListWorkflowParams{
+			Page:          v1.NewOptInt(page),
+			PageLimit:     v1.NewOptInt(bulkPageLimit),
+			Name:          v1.NewOptString(name),
+			NameMatchType: v1.NewOptListWorkflowNameMatchType(v1.ListWorkflowNameMatchTypePrefix),
+		})
+		if err != nil {
+			return "", err
+		}
+		for _, wf := range res.Workflows {
+			if wf.Name == name {
+				return wf.ID, nil
+			}
+		}
+		seen += len(res.Workflows)
+		if len(res.Workflows) < bulkPageLimit || seen >= res.Total {
+			return "", nil
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ExportImport(t *testing.T) {
+	src := workflowsmock.NewWorkflowAPI(t)
+	src.OnRead(mock.Anything, "src").Return(workflowsmock.Workflow{ID: "src", Name: "nightly", Tags: []string{"batch"}, ConcurrencyMode: "queue"}.Get(), nil)
+	srcRevisions := workflowsmock.NewRevisionAPI(t)
+	srcRevisions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.RevisionList(
+		workflowsmock.Revision{ID: 3, WorkflowID: "src", Runbook: "v3", Alias: "latest"},
+		workflowsmock.Revision{ID: 2, WorkflowID: "src", Runbook: "v2", Alias: "stable"},
+		workflowsmock.Revision{ID: 1, WorkflowID: "src", Runbook: "v1"},
+	), nil)
+
+	exporter := &workflows.Client{Workflows: src, Revisions: srcRevisions}
+	bundle, err := exporter.Export(t.Context(), "src")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = bundle.WriteTo(&buf)
+	require.NoError(t, err)
+	bundle, err = workflows.ReadBundle(&buf)
+	require.NoError(t, err)
+	require.Len(t, bundle.Workflows, 1)
+	assert.Equal(t, []workflows.BundleRevision{
+		{Number: 1, Runbook: "v1"},
+		{Number: 2, Runbook: "v2", Alias: "stable"},
+		{Number: 3, Runbook: "v3", Alias: "latest"},
+	}, bundle.Workflows[0].Revisions)
+
+	dst := workflowsmock.NewWorkflowAPI(t)
+	dst.OnList(mock.Anything, mock.MatchedBy(func(p v1.ListWorkflowParams) bool { return p.Name.Value == "nightly" })).
+		Return(workflowsmock.WorkflowList(workflowsmock.Workflow{ID: "other", Name: "nightly"}), nil)
+	dst.OnList(mock.Anything, mock.MatchedBy(func(p v1.ListWorkflowParams) bool { return p.Name.Value == "nightly-2" })).
+		Return(workflowsmock.WorkflowList(), nil)
+	dst.OnCreate(mock.Anything, mock.MatchedBy(func(req v1.CreateWorkflowReq) bool {
+		return req.Name == "nightly-2" && req.Runbook == "v1" && !req.RevisionAlias.Set &&
+			req.ConcurrencyMode.Value == v1.CreateWorkflowReqConcurrencyModeQueue && len(req.Tags) == 1
+	})).Return(workflowsmock.Workflow{ID: "new", Name: "nightly-2"}.Created(), nil).Once()
+	dstRevisions := workflowsmock.NewRevisionAPI(t)
+	dstRevisions.OnCreate(mock.Anything, "new", v1.CreateWorkflowRevisionReq{Runbook: "v2", RevisionAlias: v1.NewOptString("stable")}).
+		Return(workflowsmock.Revision{ID: 2, WorkflowID: "new"}.Created(), nil).Once()
+	dstRevisions.OnCreate(mock.Anything, "new", v1.CreateWorkflowRevisionReq{Runbook: "v3", RevisionAlias: v1.NewOptString("latest")}).
+		Return(workflowsmock.Revision{ID: 3, WorkflowID: "new"}.Created(), nil).Once()
+
+	importer := &workflows.Client{Workflows: dst, Revisions: dstRevisions}
+	result, err := importer.Import(t.Context(), bundle, workflows.ImportOptions{Conflict: workflows.ConflictRename})
+	require.NoError(t, err)
+	require.Len(t, result.Workflows, 1)
+	assert.Equal(t, workflows.ImportRenamed, result.Workflows[0].Action)
+	assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 3}, result.Workflows[0].Revisions)
+	assert.Equal(t, map[string]string{"src": "new"}, result.IDMap())
+}
+
+func TestClient_Import_conflict(t *testing.T) {
+	bundle := &workflows.Bundle{Workflows: []workflows.BundleWorkflow{{
+		Workflow:  *workflowsmock.Workflow{ID: "src", Name: "nightly"}.Get(),
+		Revisions: []workflows.BundleRevision{{Number: 1, Runbook: "v1"}},
+	}}}
+	dst := workflowsmock.NewWorkflowAPI(t)
+	dst.OnList(mock.Anything, mock.Anything).Return(workflowsmock.WorkflowList(workflowsmock.Workflow{ID: "other", Name: "nightly"}), nil)
+	client := &workflows.Client{Workflows: dst}
+
+	_, err := client.Import(t.Context(), bundle, workflows.ImportOptions{})
+	require.ErrorContains(t, err, `workflow "nightly" already exists`)
+
+	result, err := client.Import(t.Context(), bundle, workflows.ImportOptions{Conflict: workflows.ConflictSkip})
+	require.NoError(t, err)
+	assert.Equal(t, workflows.ImportSkipped, result.Workflows[0].Action)
+	assert.Equal(t, "other", result.Workflows[0].ID)
+}
+
+func TestClient_Import_overwrite(t *testing.T) {
+	bundle := &workflows.Bundle{Workflows: []workflows.BundleWorkflow{{
+		Workflow:  *workflowsmock.Workflow{ID: "src", Name: "nightly"}.Get(),
+		Revisions: []workflows.BundleRevision{{Number: 1, Runbook: "v1"}},
+	}}}
+	overwrite := workflows.ImportOptions{Conflict: workflows.ConflictOverwrite}
+	isTemporary := mock.MatchedBy(func(req v1.CreateWorkflowReq) bool { return req.Name == "nightly-importing" })
+
+	t.Run("replaced", func(t *testing.T) {
+		dst := workflowsmock.NewWorkflowAPI(t)
+		dst.OnList(mock.Anything, mock.Anything).Return(workflowsmock.WorkflowList(workflowsmock.Workflow{ID: "other", Name: "nightly"}), nil)
+		create := dst.OnCreate(mock.Anything, isTemporary).Return(&v1.CreateWorkflowCreatedWorkflow{ID: "new", Name: "nightly-importing"}, nil).Once()
+		remove := dst.OnDelete(mock.Anything, "other").Return(nil).Once()
+		remove.NotBefore(create.Call)
+		dst.OnUpdate(mock.Anything, "new", v1.UpdateWorkflowReq{Name: v1.NewOptString("nightly")}).
+			Return(&v1.UpdateWorkflowOKWorkflow{ID: "new", Name: "nightly"}, nil).Once().NotBefore(remove.Call)
+
+		result, err := (&workflows.Client{Workflows: dst}).Import(t.Context(), bundle, overwrite)
+		require.NoError(t, err)
+		assert.Equal(t, workflows.ImportedWorkflow{SourceID: "src", ID: "new", Name: "nightly", Action: workflows.ImportOverwritten, Revisions: map[int]int{1: 1}}, result.Workflows[0])
+	})
+
+	t.Run("create fails", func(t *testing.T) {
+		dst := workflowsmock.NewWorkflowAPI(t)
+		dst.OnList(mock.Anything, mock.Anything).Return(workflowsmock.WorkflowList(workflowsmock.Workflow{ID: "other", Name: "nightly"}), nil)
+		dst.OnCreate(mock.Anything, isTemporary).Return(nil, workflows.NewError("quota", nil)).Once()
+
+		_, err := (&workflows.Client{Workflows: dst}).Import(t.Context(), bundle, overwrite)
+		require.EqualError(t, err, "workflows: quota")
+		dst.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestReadBundle_invalid(t *testing.T) {
+	_, err := workflows.ReadBundle(strings.NewReader(`{"format":"other","version":1}`))
+	require.ErrorContains(t, err, "not a workflows bundle")
+	_, err = workflows.ReadBundle(strings.NewReader(`{"format":"sacloud-workflows-bundle","version":9}`))
+	require.ErrorContains(t, err, "unsupported bundle version 9")
+}
@@ -0,0 +1,340 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache provides read-through caches of WorkflowAPI and RevisionAPI.
+//
+// Workflow.Read and Revision.Read are cached for a TTL per entity. The runbook of a revision never
+// changes once created, so it is cached without expiry by workflow ID and revision number, and
+// Revisions.Runbook serves it from the cache alone. Updates, alias changes and deletions through the
+// wrapped APIs invalidate what they affect; changes made by other clients are seen after the TTL, or
+// right away by reads with a context made by workflows.WithoutCache.
+//
+//	c := cache.New(cache.WithStore(cache.NewLRU(1000)))
+//	client = c.Wrap(client)
+//	wf, err := client.Workflows.Read(ctx, id) // served from the cache until the TTL expires
+//	fmt.Println(c.Stats().Hits)
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+const (
+	// DefaultWorkflowTTL ワークフローをキャッシュする既定の時間
+	DefaultWorkflowTTL = time.Minute
+	// DefaultRevisionTTL リビジョン(エイリアスを含む)をキャッシュする既定の時間
+	DefaultRevisionTTL = 5 * time.Minute
+	// DefaultCapacity 既定のLRUの件数の上限
+	DefaultCapacity = 1000
+)
+
+// Stats キャッシュの統計
+type Stats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	// Expired Missesのうち、期限切れのエントリがあったもの
+	Expired int64 `json:"expired"`
+	// Invalidations 更新・削除によって捨てたキー・プレフィックスの数
+	Invalidations int64 `json:"invalidations"`
+	// StoreErrors Storeの読み書きに失敗した回数。失敗した場合はAPIを呼び出す
+	StoreErrors int64 `json:"storeErrors"`
+}
+
+// HitRatio returns the ratio of hits to all lookups, or 0 before any lookup
+func (s Stats) HitRatio() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
+
+// Option Newに渡す設定
+type Option func(*Cache)
+
+// WithStore sets where entries are kept. Defaults to NewLRU(DefaultCapacity).
+func WithStore(store Store) Option {
+	return func(c *Cache) { c.store = store }
+}
+
+// WithWorkflowTTL sets how long a workflow is cached. Defaults to DefaultWorkflowTTL; 0 or less disables caching of workflows.
+func WithWorkflowTTL(ttl time.Duration) Option {
+	return func(c *Cache) { c.workflowTTL = ttl }
+}
+
+// WithRevisionTTL sets how long a revision is cached. Defaults to DefaultRevisionTTL; 0 or less disables caching
+// of revisions but not of runbooks.
+func WithRevisionTTL(ttl time.Duration) Option {
+	return func(c *Cache) { c.revisionTTL = ttl }
+}
+
+// WithClock sets the function returning the current time. Defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(c *Cache) { c.now = now }
+}
+
+// Cache WorkflowAPI・RevisionAPIで共有するキャッシュ
+type Cache struct {
+	store       Store
+	workflowTTL time.Duration
+	revisionTTL time.Duration
+	now         func() time.Time
+
+	hits, misses, expired, invalidations, storeErrors atomic.Int64
+}
+
+// New creates a cache
+func New(opts ...Option) *Cache {
+	c := &Cache{workflowTTL: DefaultWorkflowTTL, revisionTTL: DefaultRevisionTTL, now: time.Now}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.store == nil {
+		c.store = NewLRU(DefaultCapacity)
+	}
+	return c
+}
+
+// Stats returns the statistics so far
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Expired:       c.expired.Load(),
+		Invalidations: c.invalidations.Load(),
+		StoreErrors:   c.storeErrors.Load(),
+	}
+}
+
+// Wrap returns a copy of the client whose Workflows and Revisions go through the cache.
+// Reads with a context made by workflows.WithoutCache, as those of UpdateIfUnchanged and the tag
+// helpers, go to the API and refresh the cache.
+func (c *Cache) Wrap(client *workflows.Client) *workflows.Client {
+	ret := *client
+	ret.Workflows = c.Workflows(client.Workflows)
+	ret.Revisions = c.Revisions(client.Revisions)
+	return &ret
+}
+
+// Workflows wraps api with the cache
+func (c *Cache) Workflows(api workflows.WorkflowAPI) *Workflows {
+	return &Workflows{WorkflowAPI: api, cache: c}
+}
+
+// Revisions wraps api with the cache
+func (c *Cache) Revisions(api workflows.RevisionAPI) *Revisions {
+	return &Revisions{RevisionAPI: api, cache: c}
+}
+
+func workflowKey(id string) string { return "workflow/" + id }
+
+func revisionKey(workflowID string, n int) string {
+	return "revision/" + workflowID + "/" + strconv.Itoa(n)
+}
+
+func runbookKey(workflowID string, n int) string {
+	return "runbook/" + workflowID + "/" + strconv.Itoa(n)
+}
+
+// get decodes the entry of the key into v and reports whether it was a fresh hit
+func (c *Cache) get(ctx context.Context, key string, v any) bool {
+	e, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		c.storeErrors.Add(1)
+	}
+	if ok && e.expired(c.now()) {
+		c.expired.Add(1)
+		ok = false
+	}
+	if ok {
+		if err := json.Unmarshal(e.Value, v); err != nil {
+			ok = false
+		}
+	}
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return ok
+}
+
+// set stores v for ttl, or without expiry if ttl is 0
+func (c *Cache) set(ctx context.Context, key string, v any, ttl time.Duration) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		c.storeErrors.Add(1)
+		return
+	}
+	e := Entry{Value: data}
+	if ttl > 0 {
+		e.Expires = c.now().Add(ttl)
+	}
+	if err := c.store.Set(ctx, key, e); err != nil {
+		c.storeErrors.Add(1)
+	}
+}
+
+func (c *Cache) invalidate(ctx context.Context, key string) {
+	c.invalidations.Add(1)
+	if err := c.store.Delete(ctx, key); err != nil {
+		c.storeErrors.Add(1)
+	}
+}
+
+func (c *Cache) invalidatePrefix(ctx context.Context, prefix string) {
+	c.invalidations.Add(1)
+	if err := c.store.DeletePrefix(ctx, prefix); err != nil {
+		c.storeErrors.Add(1)
+	}
+}
+
+// Workflows キャッシュを通すWorkflowAPI。Read以外はそのまま呼び出す
+type Workflows struct {
+	workflows.WorkflowAPI
+	cache *Cache
+}
+
+var _ workflows.WorkflowAPI = (*Workflows)(nil)
+
+func (w *Workflows) Read(ctx context.Context, id string) (*v1.GetWorkflowOKWorkflow, error) {
+	c := w.cache
+	if c.workflowTTL <= 0 {
+		return w.WorkflowAPI.Read(ctx, id)
+	}
+	var ret v1.GetWorkflowOKWorkflow
+	if !workflows.CacheBypassed(ctx) && c.get(ctx, workflowKey(id), &ret) {
+		return &ret, nil
+	}
+	res, err := w.WorkflowAPI.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, workflowKey(id), res, c.workflowTTL)
+	return res, nil
+}
+
+func (w *Workflows) Update(ctx context.Context, id string, req v1.UpdateWorkflowReq) (*v1.UpdateWorkflowOKWorkflow, error) {
+	res, err := w.WorkflowAPI.Update(ctx, id, req)
+	w.cache.invalidate(ctx, workflowKey(id))
+	return res, err
+}
+
+// Delete deletes the workflow and drops it, its revisions and their runbooks from the cache
+func (w *Workflows) Delete(ctx context.Context, id string) error {
+	err := w.WorkflowAPI.Delete(ctx, id)
+	if err == nil {
+		w.cache.invalidate(ctx, workflowKey(id))
+		w.cache.invalidatePrefix(ctx, "revision/"+id+"/")
+		w.cache.invalidatePrefix(ctx, "runbook/"+id+"/")
+	}
+	return err
+}
+
+// Revisions キャッシュを通すRevisionAPI
+type Revisions struct {
+	workflows.RevisionAPI
+	cache *Cache
+}
+
+var _ workflows.RevisionAPI = (*Revisions)(nil)
+
+func (r *Revisions) Read(ctx context.Context, workflowID string, revisionNumber int) (*v1.GetWorkflowRevisionsOKRevision, error) {
+	c := r.cache
+	key := revisionKey(workflowID, revisionNumber)
+	if c.revisionTTL > 0 {
+		var ret v1.GetWorkflowRevisionsOKRevision
+		if !workflows.CacheBypassed(ctx) && c.get(ctx, key, &ret) {
+			return &ret, nil
+		}
+	}
+	res, err := r.RevisionAPI.Read(ctx, workflowID, revisionNumber)
+	if err != nil {
+		return nil, err
+	}
+	if c.revisionTTL > 0 {
+		c.set(ctx, key, res, c.revisionTTL)
+	}
+	c.set(ctx, runbookKey(workflowID, revisionNumber), res.Runbook, 0)
+	return res, nil
+}
+
+// List lists the revisions as is and keeps their runbooks in the cache
+func (r *Revisions) List(ctx context.Context, params v1.ListWorkflowRevisionsParams) (*v1.ListWorkflowRevisionsOK, error) {
+	res, err := r.RevisionAPI.List(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	for _, rev := range res.Revisions {
+		r.cache.set(ctx, runbookKey(rev.WorkflowId, rev.RevisionId), rev.Runbook, 0)
+	}
+	return res, nil
+}
+
+func (r *Revisions) Create(ctx context.Context, workflowID string, req v1.CreateWorkflowRevisionReq) (*v1.CreateWorkflowRevisionCreatedRevision, error) {
+	res, err := r.RevisionAPI.Create(ctx, workflowID, req)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.set(ctx, runbookKey(workflowID, res.RevisionId), res.Runbook, 0)
+	if req.RevisionAlias.Set {
+		// the alias may have been taken from another revision
+		r.cache.invalidatePrefix(ctx, "revision/"+workflowID+"/")
+	}
+	return res, nil
+}
+
+// UpdateAlias sets the alias and drops the revisions of the workflow from the cache,
+// since the alias may have been moved from another revision
+func (r *Revisions) UpdateAlias(ctx context.Context, workflowID string, revisionNumber int, req v1.UpdateWorkflowRevisionAliasReq) (*v1.UpdateWorkflowRevisionAliasOKRevision, error) {
+	res, err := r.RevisionAPI.UpdateAlias(ctx, workflowID, revisionNumber, req)
+	r.cache.invalidatePrefix(ctx, "revision/"+workflowID+"/")
+	return res, err
+}
+
+func (r *Revisions) DeleteAlias(ctx context.Context, workflowID string, revisionNumber int) error {
+	err := r.RevisionAPI.DeleteAlias(ctx, workflowID, revisionNumber)
+	r.cache.invalidate(ctx, revisionKey(workflowID, revisionNumber))
+	return err
+}
+
+// Runbook returns the runbook of the revision, from the cache if it has ever been read through it
+func (r *Revisions) Runbook(ctx context.Context, workflowID string, revisionNumber int) (string, error) {
+	var runbook string
+	if r.cache.get(ctx, runbookKey(workflowID, revisionNumber), &runbook) {
+		return runbook, nil
+	}
+	res, err := r.RevisionAPI.Read(ctx, workflowID, revisionNumber)
+	if err != nil {
+		return "", err
+	}
+	r.cache.set(ctx, runbookKey(workflowID, revisionNumber), res.Runbook, 0)
+	if r.cache.revisionTTL > 0 {
+		r.cache.set(ctx, revisionKey(workflowID, revisionNumber), res, r.cache.revisionTTL)
+	}
+	return res.Runbook, nil
+}
+
+// Invalidate drops the workflow, its revisions and their runbooks, e.g. after a change made by another client
+func (c *Cache) Invalidate(ctx context.Context, workflowID string) {
+	c.invalidate(ctx, workflowKey(workflowID))
+	c.invalidatePrefix(ctx, "revision/"+workflowID+"/")
+	c.invalidatePrefix(ctx, "runbook/"+workflowID+"/")
+}
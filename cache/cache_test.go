@@ -0,0 +1,180 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/cache"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var updatedAt = time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+func TestCache_Workflows(t *testing.T) {
+	now := updatedAt
+	api := workflowsmock.NewWorkflowAPI(t)
+	c := cache.New(cache.WithClock(func() time.Time { return now }))
+	client := c.Wrap(&workflows.Client{Workflows: api, Revisions: workflowsmock.NewRevisionAPI(t)})
+
+	api.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Name: "v1", UpdatedAt: updatedAt}.Get(), nil).Once()
+	for range 2 {
+		wf, err := client.Workflows.Read(t.Context(), "wf")
+		require.NoError(t, err)
+		assert.Equal(t, "v1", wf.Name)
+	}
+
+	// expired
+	now = now.Add(cache.DefaultWorkflowTTL)
+	api.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Name: "v2", UpdatedAt: updatedAt}.Get(), nil).Once()
+	wf, err := client.Workflows.Read(t.Context(), "wf")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", wf.Name)
+
+	// invalidated by an update
+	api.OnUpdate(mock.Anything, "wf", mock.Anything).Return(workflowsmock.Workflow{ID: "wf", Name: "v3"}.Updated(), nil).Once()
+	_, err = client.Workflows.Update(t.Context(), "wf", v1.UpdateWorkflowReq{Name: v1.NewOptString("v3")})
+	require.NoError(t, err)
+	api.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Name: "v3", UpdatedAt: updatedAt}.Get(), nil).Once()
+	wf, err = client.Workflows.Read(t.Context(), "wf")
+	require.NoError(t, err)
+	assert.Equal(t, "v3", wf.Name)
+
+	// errors are not cached
+	api.OnRead(mock.Anything, "missing").Return(nil, errors.New("not found")).Twice()
+	for range 2 {
+		_, err = client.Workflows.Read(t.Context(), "missing")
+		require.Error(t, err)
+	}
+
+	assert.Equal(t, cache.Stats{Hits: 1, Misses: 5, Expired: 1, Invalidations: 1}, c.Stats())
+}
+
+func TestCache_UpdateIfUnchanged(t *testing.T) {
+	api := workflowsmock.NewWorkflowAPI(t)
+	client := cache.New().Wrap(&workflows.Client{Workflows: api, Revisions: workflowsmock.NewRevisionAPI(t)})
+
+	api.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Description: "mine", UpdatedAt: updatedAt}.Get(), nil).Once()
+	wf, err := client.Workflows.Read(t.Context(), "wf")
+	require.NoError(t, err)
+
+	// another client changes the workflow while it is cached; the update must not overwrite that change
+	changed := workflowsmock.Workflow{ID: "wf", Description: "theirs", UpdatedAt: updatedAt.Add(time.Minute)}.Get()
+	api.OnRead(mock.Anything, "wf").Return(changed, nil).Once()
+	_, err = client.UpdateIfUnchanged(t.Context(), "wf", wf.UpdatedAt, func(_ *v1.GetWorkflowOKWorkflow, req *v1.UpdateWorkflowReq) error {
+		req.Description = v1.NewOptString("overwritten")
+		return nil
+	})
+	require.ErrorIs(t, err, workflows.ErrConcurrentModification)
+
+	// the bypassing read refreshed the cache
+	wf, err = client.Workflows.Read(t.Context(), "wf")
+	require.NoError(t, err)
+	assert.Equal(t, "theirs", wf.Description.Value)
+}
+
+func TestCache_Revisions(t *testing.T) {
+	api := workflowsmock.NewRevisionAPI(t)
+	c := cache.New()
+	revisions := c.Revisions(api)
+	rev := workflowsmock.Revision{ID: 1, WorkflowID: "wf", Alias: "prod", Runbook: "steps: {}", CreatedAt: updatedAt, UpdatedAt: updatedAt}
+
+	api.OnRead(mock.Anything, "wf", 1).Return(rev.Get(), nil).Once()
+	for range 2 {
+		got, err := revisions.Read(t.Context(), "wf", 1)
+		require.NoError(t, err)
+		assert.Equal(t, rev.Get(), got)
+	}
+
+	// moving the alias drops the revisions of the workflow, but not the runbooks
+	api.OnUpdateAlias(mock.Anything, "wf", 2, mock.Anything).Return(workflowsmock.Revision{ID: 2, WorkflowID: "wf", Alias: "prod"}.AliasUpdated(), nil).Once()
+	_, err := revisions.UpdateAlias(t.Context(), "wf", 2, v1.UpdateWorkflowRevisionAliasReq{RevisionAlias: "prod"})
+	require.NoError(t, err)
+	rev.Alias = ""
+	api.OnRead(mock.Anything, "wf", 1).Return(rev.Get(), nil).Once()
+	got, err := revisions.Read(t.Context(), "wf", 1)
+	require.NoError(t, err)
+	assert.False(t, got.RevisionAlias.Set)
+
+	runbook, err := revisions.Runbook(t.Context(), "wf", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "steps: {}", runbook)
+
+	// runbooks listed or created are kept without expiry
+	api.OnList(mock.Anything, mock.Anything).Return(workflowsmock.RevisionList(workflowsmock.Revision{ID: 3, WorkflowID: "wf", Runbook: "three"}), nil).Once()
+	_, err = revisions.List(t.Context(), v1.ListWorkflowRevisionsParams{ID: "wf"})
+	require.NoError(t, err)
+	api.OnCreate(mock.Anything, "wf", mock.Anything).Return(workflowsmock.Revision{ID: 4, WorkflowID: "wf", Runbook: "four"}.Created(), nil).Once()
+	_, err = revisions.Create(t.Context(), "wf", v1.CreateWorkflowRevisionReq{Runbook: "four"})
+	require.NoError(t, err)
+	for n, want := range map[int]string{3: "three", 4: "four"} {
+		runbook, err := revisions.Runbook(t.Context(), "wf", n)
+		require.NoError(t, err)
+		assert.Equal(t, want, runbook)
+	}
+
+	assert.Equal(t, int64(4), c.Stats().Hits)
+}
+
+func TestCache_Delete(t *testing.T) {
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	revs := workflowsmock.NewRevisionAPI(t)
+	c := cache.New(cache.WithStore(cache.NewFileStore(t.TempDir())))
+	client := c.Wrap(&workflows.Client{Workflows: wfs, Revisions: revs})
+
+	wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Name: "wf", UpdatedAt: updatedAt}.Get(), nil).Twice()
+	revs.OnRead(mock.Anything, "wf", 1).Return(workflowsmock.Revision{ID: 1, WorkflowID: "wf", Runbook: "one"}.Get(), nil).Twice()
+	wfs.OnDelete(mock.Anything, "wf").Return(nil).Once()
+
+	read := func() {
+		_, err := client.Workflows.Read(t.Context(), "wf")
+		require.NoError(t, err)
+		_, err = client.Revisions.(*cache.Revisions).Runbook(t.Context(), "wf", 1)
+		require.NoError(t, err)
+	}
+	read()
+	read()
+	require.NoError(t, client.Workflows.Delete(t.Context(), "wf"))
+	// a workflow of the same ID created later is read again
+	read()
+	assert.Equal(t, cache.Stats{Hits: 2, Misses: 4, Invalidations: 3}, c.Stats())
+}
+
+// brokenStore 常に失敗するStore
+type brokenStore struct{}
+
+func (brokenStore) Get(context.Context, string) (cache.Entry, bool, error) {
+	return cache.Entry{}, false, errors.New("broken")
+}
+func (brokenStore) Set(context.Context, string, cache.Entry) error { return errors.New("broken") }
+func (brokenStore) Delete(context.Context, string) error           { return errors.New("broken") }
+func (brokenStore) DeletePrefix(context.Context, string) error     { return errors.New("broken") }
+
+func TestCache_storeError(t *testing.T) {
+	api := workflowsmock.NewWorkflowAPI(t)
+	c := cache.New(cache.WithStore(brokenStore{}))
+	api.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Name: "wf"}.Get(), nil).Once()
+	_, err := c.Workflows(api).Read(t.Context(), "wf")
+	require.NoError(t, err, "falls back to the API")
+	assert.Equal(t, cache.Stats{Misses: 1, StoreErrors: 2}, c.Stats())
+}
@@ -0,0 +1,231 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry キャッシュされた値
+type Entry struct {
+	// Value レスポンスをJSONにしたもの
+	Value []byte `json:"value"`
+	// Expires 期限。ゼロ値の場合は無期限
+	Expires time.Time `json:"expires"`
+}
+
+// expired reports whether the entry has expired at now
+func (e Entry) expired(now time.Time) bool {
+	return !e.Expires.IsZero() && !now.Before(e.Expires)
+}
+
+// Store キャッシュの保存先
+//
+// キーは"workflow/<ID>"のように"/"で区切られる。期限切れの判定はCacheが行うため、Storeは期限を保存するだけでよい。
+type Store interface {
+	// Get returns the entry of the key, false if there is none
+	Get(ctx context.Context, key string) (Entry, bool, error)
+	Set(ctx context.Context, key string, e Entry) error
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix deletes every entry whose key starts with prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// LRU 件数の上限を超えると最も長く使われていないものから捨てるメモリ上のStore
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	evicted  int
+}
+
+var _ Store = (*LRU)(nil)
+
+// lruItem LRUのリストの要素
+type lruItem struct {
+	key   string
+	entry Entry
+}
+
+// NewLRU returns an LRU holding up to capacity entries. capacity <= 0 means no limit.
+func NewLRU(capacity int) *LRU {
+	return &LRU{capacity: capacity, entries: map[string]*list.Element{}, order: list.New()}
+}
+
+func (s *LRU) Get(_ context.Context, key string) (Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return Entry{}, false, nil
+	}
+	s.order.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true, nil
+}
+
+func (s *LRU) Set(_ context.Context, key string, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[key]; ok {
+		el.Value.(*lruItem).entry = e
+		s.order.MoveToFront(el)
+		return nil
+	}
+	s.entries[key] = s.order.PushFront(&lruItem{key: key, entry: e})
+	for s.capacity > 0 && s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*lruItem).key)
+		s.evicted++
+	}
+	return nil
+}
+
+func (s *LRU) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[key]; ok {
+		s.order.Remove(el)
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+func (s *LRU) DeletePrefix(_ context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, el := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			s.order.Remove(el)
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries
+func (s *LRU) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// Evicted returns the number of entries dropped for the capacity
+func (s *LRU) Evicted() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.evicted
+}
+
+// FileStore 1エントリを1ファイルとしてディレクトリに保存するStore
+//
+// プロセスをまたいでキャッシュを残したい場合に使う。ファイル名はキーをエスケープしたもの。
+type FileStore struct {
+	dir string
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore returns a store in dir, which is created if missing
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key)+".json")
+}
+
+func (s *FileStore) Get(_ context.Context, key string) (Entry, bool, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Entry{}, false, nil
+	} else if err != nil {
+		return Entry{}, false, fmt.Errorf("cache: %w", err)
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		// a broken file is a miss, it is overwritten by the next Set
+		return Entry{}, false, nil
+	}
+	return e, true, nil
+}
+
+func (s *FileStore) Set(_ context.Context, key string, e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() //nolint:errcheck,gosec
+		return fmt.Errorf("cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) Delete(_ context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("cache: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) DeletePrefix(_ context.Context, prefix string) error {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	for _, ent := range entries {
+		name, ok := strings.CutSuffix(ent.Name(), ".json")
+		if !ok {
+			continue
+		}
+		key, err := url.PathUnescape(name)
+		if err != nil || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, ent.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("cache: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	for name, store := range map[string]cache.Store{
+		"lru":  cache.NewLRU(0),
+		"file": cache.NewFileStore(filepath.Join(t.TempDir(), "cache")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			_, ok, err := store.Get(ctx, "workflow/a")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			expires := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+			for _, key := range []string{"workflow/a", "revision/a/1", "revision/a/2", "revision/ab/1"} {
+				require.NoError(t, store.Set(ctx, key, cache.Entry{Value: []byte(`"` + key + `"`), Expires: expires}))
+			}
+			e, ok, err := store.Get(ctx, "revision/a/1")
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, `"revision/a/1"`, string(e.Value))
+			assert.True(t, e.Expires.Equal(expires))
+
+			require.NoError(t, store.DeletePrefix(ctx, "revision/a/"))
+			require.NoError(t, store.Delete(ctx, "workflow/a"))
+			require.NoError(t, store.Delete(ctx, "workflow/missing"))
+			for key, want := range map[string]bool{"workflow/a": false, "revision/a/1": false, "revision/a/2": false, "revision/ab/1": true} {
+				_, ok, err := store.Get(ctx, key)
+				require.NoError(t, err)
+				assert.Equal(t, want, ok, key)
+			}
+		})
+	}
+}
+
+func TestLRU_evict(t *testing.T) {
+	ctx := t.Context()
+	lru := cache.NewLRU(2)
+	require.NoError(t, lru.Set(ctx, "a", cache.Entry{}))
+	require.NoError(t, lru.Set(ctx, "b", cache.Entry{}))
+	_, _, _ = lru.Get(ctx, "a")
+	require.NoError(t, lru.Set(ctx, "c", cache.Entry{}))
+
+	_, ok, _ := lru.Get(ctx, "b")
+	assert.False(t, ok, "least recently used")
+	_, ok, _ = lru.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, lru.Len())
+	assert.Equal(t, 1, lru.Evicted())
+}
+
+func TestFileStore_broken(t *testing.T) {
+	dir := t.TempDir()
+	store := cache.NewFileStore(dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "workflow%2Fa.json"), []byte("{"), 0o600))
+	_, ok, err := store.Get(t.Context(), "workflow/a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,202 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// DefaultDeleteTimeout DeleteWorkflowが実行の終了を待つ既定の時間
+const DefaultDeleteTimeout = 5 * time.Minute
+
+// DeleteOptions DeleteWorkflowの設定
+type DeleteOptions struct {
+	// CancelRunning 実行中・待機中の実行をキャンセルし、終了するまで待つ
+	CancelRunning bool
+	// PurgeExecutions 終了した実行の記録を削除する
+	PurgeExecutions bool
+	// RemoveAliases リビジョンのエイリアスを外す
+	RemoveAliases bool
+	// Timeout キャンセルした実行の終了を待つ時間。0の場合はDefaultDeleteTimeout
+	Timeout time.Duration
+	// PollInterval 実行の状態を確認する間隔。0の場合はDefaultStreamInterval
+	PollInterval time.Duration
+	// Concurrency キャンセル・削除で同時に送るリクエスト数。0の場合はDefaultBulkConcurrency
+	Concurrency int
+}
+
+// DeleteStage DeleteWorkflowの段階
+type DeleteStage string
+
+const (
+	DeleteStageCancel   DeleteStage = "cancel"
+	DeleteStageWait     DeleteStage = "wait"
+	DeleteStagePurge    DeleteStage = "purge"
+	DeleteStageAliases  DeleteStage = "aliases"
+	DeleteStageWorkflow DeleteStage = "workflow"
+)
+
+// DeleteStageResult 1つの段階で行ったこと
+type DeleteStageResult struct {
+	Stage   DeleteStage
+	Summary string
+	Elapsed time.Duration
+}
+
+// DeleteReport DeleteWorkflowの結果。エラーで中断した場合も、それまでに行ったことが記録される
+type DeleteReport struct {
+	WorkflowID string
+	// Stages 完了した段階。実行した順に並ぶ
+	Stages   []DeleteStageResult
+	Canceled *BulkReport
+	Purged   *BulkReport
+	// RemovedAliases エイリアスを外したリビジョン番号
+	RemovedAliases []int
+	Deleted        bool
+}
+
+func (r *DeleteReport) record(stage DeleteStage, start time.Time, format string, args ...any) {
+	r.Stages = append(r.Stages, DeleteStageResult{Stage: stage, Summary: fmt.Sprintf(format, args...), Elapsed: time.Since(start)})
+}
+
+// activeStatuses 終了していない実行の状態
+var activeStatuses = []v1.ListExecutionOKExecutionsItemStatus{
+	v1.ListExecutionOKExecutionsItemStatusQueued,
+	v1.ListExecutionOKExecutionsItemStatusRunning,
+	v1.ListExecutionOKExecutionsItemStatusCanceling,
+}
+
+// DeleteWorkflow deletes the workflow after clearing what keeps the API from deleting it with
+// C-0040: it cancels the active executions and waits for them to finish, deletes the execution
+// records and removes the revision aliases, each only if enabled in opts.
+//
+// The report is returned even on error, telling how far the deletion got.
+func (c *Client) DeleteWorkflow(ctx context.Context, id string, opts DeleteOptions) (*DeleteReport, error) {
+	report := &DeleteReport{WorkflowID: id}
+	bulk := BulkOptions{Concurrency: opts.Concurrency}
+
+	if opts.CancelRunning {
+		start := time.Now()
+		canceled, err := c.CancelAll(ctx, id, ExecutionFilter{}, bulk)
+		if err != nil {
+			return report, err
+		}
+		report.Canceled = canceled
+		if err := canceled.Err(); err != nil {
+			return report, err
+		}
+		report.record(DeleteStageCancel, start, "%d canceled, %d skipped", canceled.Count(BulkDone), canceled.Count(BulkSkipped))
+
+		start = time.Now()
+		if err := c.waitExecutions(ctx, id, opts); err != nil {
+			return report, err
+		}
+		report.record(DeleteStageWait, start, "all executions finished")
+	}
+
+	if opts.PurgeExecutions {
+		start := time.Now()
+		purged, err := c.PurgeExecutions(ctx, id, ExecutionFilter{}, bulk)
+		if err != nil {
+			return report, err
+		}
+		report.Purged = purged
+		if err := purged.Err(); err != nil {
+			return report, err
+		}
+		report.record(DeleteStagePurge, start, "%d deleted, %d skipped", purged.Count(BulkDone), purged.Count(BulkSkipped))
+	}
+
+	if opts.RemoveAliases {
+		start := time.Now()
+		if err := c.removeAliases(ctx, id, report); err != nil {
+			return report, err
+		}
+		report.record(DeleteStageAliases, start, "%d aliases removed", len(report.RemovedAliases))
+	}
+
+	start := time.Now()
+	if err := c.Workflows.Delete(ctx, id); err != nil {
+		return report, err
+	}
+	report.Deleted = true
+	report.record(DeleteStageWorkflow, start, "workflow %s deleted", id)
+	return report, nil
+}
+
+// waitExecutions polls the executions until none of them is active
+func (c *Client) waitExecutions(ctx context.Context, id string, opts DeleteOptions) error {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultDeleteTimeout
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultStreamInterval
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		active, err := c.matchingExecutions(ctx, id, ExecutionFilter{Statuses: activeStatuses}, time.Now())
+		if err != nil {
+			return err
+		}
+		if len(active) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return NewError(fmt.Sprintf("%d executions still active after %s", len(active), timeout), ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// removeAliases removes the alias of every revision having one
+func (c *Client) removeAliases(ctx context.Context, id string, report *DeleteReport) error {
+	var aliased []int
+	for page, seen := 1, 0; ; page++ {
+		res, err := c.Revisions.List(ctx, v1.ListWorkflowRevisionsParams{
+			ID:        id,
+			Page:      v1.NewOptInt(page),
+			PageLimit: v1.NewOptInt(bulkPageLimit),
+		})
+		if err != nil {
+			return err
+		}
+		for _, r := range res.Revisions {
+			if r.RevisionAlias.Value != "" {
+				aliased = append(aliased, r.RevisionId)
+			}
+		}
+		seen += len(res.Revisions)
+		if len(res.Revisions) < bulkPageLimit || seen >= res.Total {
+			break
+		}
+	}
+
+	for _, rev := range aliased {
+		if err := c.Revisions.DeleteAlias(ctx, id, rev); err != nil {
+			return err
+		}
+		report.RemovedAliases = append(report.RemovedAliases, rev)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DeleteWorkflow(t *testing.T) {
+	executions := workflowsmock.NewExecutionAPI(t)
+	// listed by CancelAll, then twice while waiting, then by PurgeExecutions
+	executions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.ExecutionList(workflowsmock.Execution{ID: "e1", Status: "Running"}), nil).Once()
+	executions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.ExecutionList(workflowsmock.Execution{ID: "e1", Status: "Canceling"}), nil).Once()
+	executions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.ExecutionList(workflowsmock.Execution{ID: "e1", Status: "Canceled"}), nil).Twice()
+	executions.OnCancel(mock.Anything, "wf", "e1").Return(workflowsmock.Execution{ID: "e1", Status: "Canceling"}.Canceled(), nil).Once()
+	executions.OnDelete(mock.Anything, "wf", "e1").Return(nil).Once()
+	revisions := workflowsmock.NewRevisionAPI(t)
+	revisions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.RevisionList(
+		workflowsmock.Revision{ID: 1, WorkflowID: "wf"},
+		workflowsmock.Revision{ID: 2, WorkflowID: "wf", Alias: "stable"},
+	), nil).Once()
+	revisions.OnDeleteAlias(mock.Anything, "wf", 2).Return(nil).Once()
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnDelete(mock.Anything, "wf").Return(nil).Once()
+
+	client := &workflows.Client{Workflows: wfs, Executions: executions, Revisions: revisions}
+	report, err := client.DeleteWorkflow(t.Context(), "wf", workflows.DeleteOptions{
+		CancelRunning:   true,
+		PurgeExecutions: true,
+		RemoveAliases:   true,
+		PollInterval:    time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, report.Deleted)
+	assert.Equal(t, []int{2}, report.RemovedAliases)
+	assert.Equal(t, 1, report.Canceled.Count(workflows.BulkDone))
+	assert.Equal(t, 1, report.Purged.Count(workflows.BulkDone))
+	var stages []workflows.DeleteStage
+	for _, s := range report.Stages {
+		stages = append(stages, s.Stage)
+	}
+	assert.Equal(t, []workflows.DeleteStage{
+		workflows.DeleteStageCancel,
+		workflows.DeleteStageWait,
+		workflows.DeleteStagePurge,
+		workflows.DeleteStageAliases,
+		workflows.DeleteStageWorkflow,
+	}, stages)
+}
+
+func TestClient_DeleteWorkflow_timeout(t *testing.T) {
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.ExecutionList(workflowsmock.Execution{ID: "e1", Status: "Running"}), nil)
+	executions.OnCancel(mock.Anything, "wf", "e1").Return(nil, workflows.NewAPIError("Execution.Cancel", http.StatusConflict, errors.New("C-0050 This execution cannot be canceled.")))
+
+	client := &workflows.Client{Executions: executions}
+	report, err := client.DeleteWorkflow(t.Context(), "wf", workflows.DeleteOptions{
+		CancelRunning: true,
+		Timeout:       20 * time.Millisecond,
+		PollInterval:  5 * time.Millisecond,
+	})
+	require.ErrorContains(t, err, "1 executions still active")
+	assert.False(t, report.Deleted)
+	require.Len(t, report.Stages, 1)
+	assert.Equal(t, workflows.DeleteStageCancel, report.Stages[0].Stage)
+}
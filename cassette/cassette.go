@@ -0,0 +1,338 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cassette provides an HTTP client that records API interactions to a file and replays them.
+//
+// In ModeRecord the wrapped client talks to the real API and every interaction is stored with
+// credentials removed and volatile IDs replaced by stable placeholders.
+// In ModeReplay no request leaves the process; responses are served from the file in recorded order.
+//
+//	rec, err := cassette.New("testdata/cassettes/TestWorkflowAPI.json", cassette.ModeReplay, nil)
+//	client, err := workflows.New(workflows.WithHTTPClient(rec))
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	ht "github.com/ogen-go/ogen/http"
+)
+
+// Mode 記録するか再生するか
+type Mode int
+
+const (
+	// ModeReplay ファイルに記録されたレスポンスを返す
+	ModeReplay Mode = iota
+	// ModeRecord 実際のAPIにリクエストし、その内容をファイルに記録する
+	ModeRecord
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// Interaction 1回のリクエストとレスポンスの組
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request 記録されたリクエスト
+type Request struct {
+	Method string `json:"method"`
+	// URL パスとクエリ。ホストはゾーンやプロファイルで変わるため記録しない
+	URL  string `json:"url"`
+	Body string `json:"body,omitempty"`
+}
+
+// Response 記録されたレスポンス
+type Response struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+}
+
+// IDPattern 記録時にプレースホルダへ置換するIDの書式
+type IDPattern struct {
+	Regexp *regexp.Regexp
+	// Placeholder n番目(1始まり)に見つかったIDのプレースホルダを返す。元の書式を保つこと
+	Placeholder func(n int) string
+}
+
+// DefaultIDPatterns 実行IDなどのUUIDと、ワークフロー/アカウント等の12桁のID
+//
+// 先に書かれたパターンが優先される。
+var DefaultIDPatterns = []IDPattern{
+	{
+		Regexp:      regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`),
+		Placeholder: func(n int) string { return fmt.Sprintf("00000000-0000-4000-8000-%012d", n) },
+	},
+	{
+		Regexp:      regexp.MustCompile(`\b[0-9]{12}\b`),
+		Placeholder: func(n int) string { return fmt.Sprintf("%012d", n) },
+	},
+}
+
+// Option Recorderの設定
+type Option func(*Recorder)
+
+// WithIDPatterns replaces the ID patterns scrubbed on record. Defaults to DefaultIDPatterns.
+func WithIDPatterns(patterns ...IDPattern) Option {
+	return func(r *Recorder) { r.patterns = patterns }
+}
+
+// WithScrubber adds a function applied to every interaction before it is written to the file
+func WithScrubber(scrub func(*Interaction)) Option {
+	return func(r *Recorder) { r.scrubbers = append(r.scrubbers, scrub) }
+}
+
+// Recorder ht.Clientを実装し、APIとのやり取りを記録/再生する
+type Recorder struct {
+	path      string
+	mode      Mode
+	inner     ht.Client
+	patterns  []IDPattern
+	scrubbers []func(*Interaction)
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	ids          map[string]string
+	next         int
+	combined     *regexp.Regexp
+	anchored     []*regexp.Regexp
+}
+
+var _ ht.Client = (*Recorder)(nil)
+
+// New creates a Recorder. inner is the client used in ModeRecord and may be nil in ModeReplay.
+// In ModeReplay the cassette file must exist.
+func New(path string, mode Mode, inner ht.Client, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:     path,
+		mode:     mode,
+		inner:    inner,
+		patterns: DefaultIDPatterns,
+		ids:      map[string]string{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	switch mode {
+	case ModeRecord:
+		if inner == nil {
+			return nil, errors.New("cassette: inner client is required to record")
+		}
+	case ModeReplay:
+		data, err := os.ReadFile(path) //nolint:gosec
+		if err != nil {
+			return nil, fmt.Errorf("cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			return nil, fmt.Errorf("cassette: %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.interactions))
+	default:
+		return nil, fmt.Errorf("cassette: unknown mode %s", mode)
+	}
+	return r, nil
+}
+
+// Mode returns the mode of the Recorder
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Do implements ht.Client
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeReplay {
+		return r.replay(req)
+	}
+	return r.record(req)
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	url := req.URL.RequestURI()
+	for i, in := range r.interactions {
+		if r.used[i] || in.Request.Method != req.Method || in.Request.URL != url {
+			continue
+		}
+		r.used[i] = true
+		return in.Response.httpResponse(req), nil
+	}
+	return nil, fmt.Errorf("cassette: no recorded interaction left for %s %s (body %q)", req.Method, url, body)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.inner.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := readBody(&res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	in := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    r.scrub(req.URL.RequestURI()),
+			Body:   r.scrub(reqBody),
+		},
+		Response: Response{
+			StatusCode:  res.StatusCode,
+			ContentType: res.Header.Get("Content-Type"),
+			Body:        r.scrub(resBody),
+		},
+	}
+	for _, scrub := range r.scrubbers {
+		scrub(&in)
+	}
+	r.interactions = append(r.interactions, in)
+	return res, nil
+}
+
+// scrub replaces IDs by placeholders, consistently across the cassette
+func (r *Recorder) scrub(s string) string {
+	if r.combined == nil {
+		var alts []string
+		for _, p := range r.patterns {
+			alts = append(alts, "(?:"+p.Regexp.String()+")")
+			r.anchored = append(r.anchored, regexp.MustCompile("^(?:"+p.Regexp.String()+")$"))
+		}
+		r.combined = regexp.MustCompile(strings.Join(alts, "|"))
+	}
+
+	return r.combined.ReplaceAllStringFunc(s, func(id string) string {
+		if placeholder, ok := r.ids[id]; ok {
+			return placeholder
+		}
+		for i, anchored := range r.anchored {
+			if anchored.MatchString(id) {
+				r.next++
+				placeholder := r.patterns[i].Placeholder(r.next)
+				r.ids[id] = placeholder
+				r.ids[placeholder] = placeholder
+				return placeholder
+			}
+		}
+		return id
+	})
+}
+
+// Save writes the recorded interactions to the file. It does nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil { //nolint:gosec
+		return fmt.Errorf("cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil { //nolint:gosec
+		return fmt.Errorf("cassette: %w", err)
+	}
+	return nil
+}
+
+// Unused returns the interactions that have not been replayed yet. It returns nil in ModeRecord.
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode != ModeReplay {
+		return nil
+	}
+
+	var ret []Interaction
+	for i, in := range r.interactions {
+		if !r.used[i] {
+			ret = append(ret, in)
+		}
+	}
+	return ret
+}
+
+func (res Response) httpResponse(req *http.Request) *http.Response {
+	header := http.Header{}
+	if res.ContentType != "" {
+		header.Set("Content-Type", res.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode)),
+		StatusCode:    res.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(res.Body)),
+		ContentLength: int64(len(res.Body)),
+		Request:       req,
+	}
+}
+
+// readBody reads the whole body and puts a fresh reader back in its place
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return "", fmt.Errorf("cassette: %w", err)
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
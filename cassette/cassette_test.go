@@ -0,0 +1,110 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassette_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sacloud/workflows-api-go/cassette"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_recordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/workflows":
+			_, _ = w.Write([]byte(`{"Id":"987654321098","ExecutionId":"3f2b8e9c-1d4a-4b6e-9f0a-123456789012"}`))
+		case "/workflows/987654321098/executions/3f2b8e9c-1d4a-4b6e-9f0a-123456789012":
+			_, _ = w.Write([]byte(`{"Status":"Running"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "test.json")
+
+	// record
+	rec, err := cassette.New(path, cassette.ModeRecord, server.Client())
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/workflows", strings.NewReader(`{"Name":"test"}`))
+	require.NoError(t, err)
+	req.SetBasicAuth("token", "secret")
+	res, err := rec.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(res.Body)
+	assert.Contains(t, string(body), "987654321098", "the caller gets the real response")
+
+	res, err = rec.Do(mustRequest(t, server.URL+"/workflows/987654321098/executions/3f2b8e9c-1d4a-4b6e-9f0a-123456789012"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Empty(t, rec.Unused(), "nothing is replayed while recording")
+	require.NoError(t, rec.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	assert.NotContains(t, string(data), "987654321098")
+	assert.NotContains(t, string(data), "3f2b8e9c")
+	assert.Contains(t, string(data), `/workflows/000000000001/executions/00000000-0000-4000-8000-000000000002`)
+
+	// replay
+	replay, err := cassette.New(path, cassette.ModeReplay, nil)
+	require.NoError(t, err)
+	assert.Len(t, replay.Unused(), 2)
+
+	res, err = replay.Do(mustRequestMethod(t, http.MethodPost, "http://example.invalid/workflows"))
+	require.NoError(t, err)
+	body, _ = io.ReadAll(res.Body)
+	assert.JSONEq(t, `{"Id":"000000000001","ExecutionId":"00000000-0000-4000-8000-000000000002"}`, string(body))
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+	res, err = replay.Do(mustRequest(t, "http://example.invalid/workflows/000000000001/executions/00000000-0000-4000-8000-000000000002"))
+	require.NoError(t, err)
+	body, _ = io.ReadAll(res.Body)
+	assert.JSONEq(t, `{"Status":"Running"}`, string(body))
+	assert.Empty(t, replay.Unused())
+
+	_, err = replay.Do(mustRequestMethod(t, http.MethodPost, "http://example.invalid/workflows"))
+	require.Error(t, err)
+}
+
+func TestRecorder_missingCassette(t *testing.T) {
+	_, err := cassette.New(filepath.Join(t.TempDir(), "missing.json"), cassette.ModeReplay, nil)
+	require.Error(t, err)
+
+	_, err = cassette.New("unused.json", cassette.ModeRecord, nil)
+	require.Error(t, err)
+}
+
+func mustRequest(t *testing.T, url string) *http.Request {
+	return mustRequestMethod(t, http.MethodGet, url)
+}
+
+func mustRequestMethod(t *testing.T, method, url string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, url, http.NoBody)
+	require.NoError(t, err)
+	return req
+}
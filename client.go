@@ -61,14 +61,63 @@ func NewClient(client saclient.ClientAPI) (*v1.Client, error) {
 
 // NewClientWithAPIRootURL creates a new workflows API client with a custom API root URL
 func NewClientWithAPIRootURL(client saclient.ClientAPI, apiRootURL string) (*v1.Client, error) {
+	augmented, err := augment(client)
+	if err != nil {
+		return nil, err
+	}
+	return v1.NewClient(apiRootURL, voidSecuritySource{}, v1.WithClient(augmented))
+}
+
+func augment(client saclient.ClientAPI) (saclient.ClientAPI, error) {
 	if dupable, ok := client.(saclient.ClientOptionAPI); !ok {
 		return nil, NewError("client does not implement saclient.ClientOptionAPI", nil)
-	} else if augmented, err := dupable.DupWith(
-		saclient.WithUserAgent(UserAgent),
-		saclient.WithForceAutomaticAuthentication(),
-	); err != nil {
-		return nil, err
 	} else {
-		return v1.NewClient(apiRootURL, voidSecuritySource{}, v1.WithClient(augmented))
+		return dupable.DupWith(
+			saclient.WithUserAgent(UserAgent),
+			saclient.WithForceAutomaticAuthentication(),
+		)
+	}
+}
+
+// Client 各APIをまとめたクライアント
+//
+// Newで作成したClientは複数のgoroutineから同時に利用できる。
+// 各フィールドはインターフェースのため、テスト時は任意の実装に差し替えられる。
+type Client struct {
+	Workflows    WorkflowAPI
+	Executions   ExecutionAPI
+	Revisions    RevisionAPI
+	Subscription SubscriptionAPI
+
+	conn *Conn
+}
+
+// New creates a new Client configured by the given options
+func New(opts ...Option) (*Client, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	client, err := o.newClient()
+	if err != nil {
+		return nil, err
 	}
+	return NewWithConn(NewConn(client, o.interceptorChain()...)), nil
+}
+
+// NewWithConn creates a new Client whose APIs all share the given Conn
+func NewWithConn(conn *Conn) *Client {
+	return &Client{
+		Workflows:    NewWorkflowOpWithConn(conn),
+		Executions:   NewExecutionOpWithConn(conn),
+		Revisions:    NewRevisionOpWithConn(conn),
+		Subscription: NewSubscriptionOpWithConn(conn),
+		conn:         conn,
+	}
+}
+
+// Conn returns the Conn shared by the APIs of the Client
+func (c *Client) Conn() *Conn {
+	return c.conn
 }
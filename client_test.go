@@ -15,7 +15,13 @@
 package workflows_test
 
 import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/sacloud/saclient-go"
 	. "github.com/sacloud/workflows-api-go"
@@ -30,3 +36,68 @@ func TestNewClient(t *testing.T) {
 	assert.NoError(err)
 	assert.NotNil(actual)
 }
+
+func TestNew(t *testing.T) {
+	assert := require.New(t)
+
+	actual, err := New()
+	assert.NoError(err)
+	assert.NotNil(actual.Workflows)
+	assert.NotNil(actual.Executions)
+	assert.NotNil(actual.Revisions)
+	assert.NotNil(actual.Subscription)
+	assert.NotNil(actual.Conn())
+
+	actual, err = New(
+		WithSaclient(&saclient.Client{}),
+		WithZone("is1b"),
+		WithRetry(3, time.Second, 5*time.Second),
+		WithRateLimit(10),
+	)
+	assert.NoError(err)
+	assert.NotNil(actual)
+
+	actual, err = New(WithRetry(0, 0, 0))
+	assert.NoError(err)
+	assert.NotNil(actual)
+}
+
+type recordingTracer struct {
+	spans []string
+	errs  []error
+}
+
+func (r *recordingTracer) Start(ctx context.Context, operation string) (context.Context, func(error)) {
+	r.spans = append(r.spans, operation)
+	return ctx, func(err error) { r.errs = append(r.errs, err) }
+}
+
+func TestNew_withHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/workflow/1.0/workflows/123456789012" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"is_ok":false,"Message":"U-0010 Workflow not found."}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	tracer := &recordingTracer{}
+	client, err := New(
+		WithHTTPClient(server.Client()),
+		WithAPIRootURL(server.URL+"/api/workflow/1.0/"),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithTracer(tracer),
+	)
+	require.NoError(t, err)
+
+	_, err = client.Workflows.Read(t.Context(), "123456789012")
+	require.Error(t, err)
+	require.True(t, saclient.IsNotFoundError(err))
+	require.Equal(t, []string{"Workflow.Read"}, tracer.spans)
+	require.Len(t, tracer.errs, 1)
+	require.ErrorIs(t, tracer.errs[0], err)
+	require.Contains(t, logs.String(), "operation=Workflow.Read")
+}
@@ -0,0 +1,100 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// Target CloneWorkflowの複製先
+type Target struct {
+	// Client 複製先のClient。nilの場合は複製元と同じ。別のゾーンへはMultiZoneClient.Zoneで得たClientを指定する
+	Client *Client
+	// Name 複製後の名前。空の場合は複製元と同じ名前になるため、別のClientを指定する必要がある
+	Name string
+}
+
+// CloneOptions CloneWorkflowの設定
+type CloneOptions struct {
+	// Alias 指定した場合、このエイリアスが付いたリビジョンだけを複製する
+	Alias string
+	// DropServicePrincipal trueの場合、ServicePrincipalIdを複製しない。別のアカウントへ複製する場合に使う
+	DropServicePrincipal bool
+	// Tags nilでなければ、複製元のタグの代わりに設定する
+	Tags []string
+	// Publish nilでなければ、複製元の公開状態の代わりに設定する
+	Publish *bool
+	// Conflict 複製先に同じ名前のワークフローがある場合の振る舞い
+	Conflict ConflictPolicy
+}
+
+// CloneWorkflow copies the workflow with all its revisions in order and their aliases, to a new
+// name or to another account or zone, e.g. to promote a workflow from staging to production.
+func (c *Client) CloneWorkflow(ctx context.Context, srcID string, dst Target, opts CloneOptions) (*ImportedWorkflow, error) {
+	to := dst.Client
+	if to == nil {
+		to = c
+	}
+	if to == c && dst.Name == "" {
+		return nil, NewError("cloning into the same client needs a new name", nil)
+	}
+
+	bundle, err := c.Export(ctx, srcID)
+	if err != nil {
+		return nil, err
+	}
+	bw := &bundle.Workflows[0]
+
+	if opts.Alias != "" {
+		var found bool
+		for _, r := range bw.Revisions {
+			if r.Alias == opts.Alias {
+				bw.Revisions, found = []BundleRevision{r}, true
+				break
+			}
+		}
+		if !found {
+			return nil, NewError(fmt.Sprintf("workflow %s has no revision with alias %q", srcID, opts.Alias), nil)
+		}
+	}
+	if dst.Name != "" {
+		bw.Workflow.Name = dst.Name
+	}
+	if opts.Tags != nil {
+		bw.Workflow.Tags = []v1.GetWorkflowOKWorkflowTagsItem{}
+		for _, tag := range opts.Tags {
+			bw.Workflow.Tags = append(bw.Workflow.Tags, v1.GetWorkflowOKWorkflowTagsItem{Name: tag})
+		}
+	}
+	if opts.Publish != nil {
+		bw.Workflow.Publish = *opts.Publish
+	}
+
+	importOpts := ImportOptions{Conflict: opts.Conflict}
+	if !opts.DropServicePrincipal {
+		if importOpts.ServicePrincipalID, err = WorkflowServicePrincipalID(&bw.Workflow); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := to.Import(ctx, bundle, importOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Workflows[0], nil
+}
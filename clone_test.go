@@ -0,0 +1,61 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"testing"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CloneWorkflow(t *testing.T) {
+	src := workflowsmock.Workflow{ID: "src", Name: "nightly", Publish: true, Tags: []string{"staging"}}.Get()
+	src.ServicePrincipalId = v1.NewOptGetWorkflowOKWorkflowServicePrincipalId(v1.NewStringGetWorkflowOKWorkflowServicePrincipalId("113000000001"))
+
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnRead(mock.Anything, "src").Return(src, nil)
+	wfs.OnList(mock.Anything, mock.Anything).Return(workflowsmock.WorkflowList(), nil)
+	wfs.OnCreate(mock.Anything, mock.MatchedBy(func(req v1.CreateWorkflowReq) bool {
+		return req.Name == "nightly-prod" && req.Runbook == "v2" && req.RevisionAlias.Value == "stable" && !req.Publish &&
+			len(req.Tags) == 1 && req.Tags[0].Name == "prod" && req.ServicePrincipalId.Value.String == "113000000001"
+	})).Return(workflowsmock.Workflow{ID: "dst", Name: "nightly-prod"}.Created(), nil).Once()
+	revisions := workflowsmock.NewRevisionAPI(t)
+	revisions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.RevisionList(
+		workflowsmock.Revision{ID: 1, WorkflowID: "src", Runbook: "v1"},
+		workflowsmock.Revision{ID: 2, WorkflowID: "src", Runbook: "v2", Alias: "stable"},
+		workflowsmock.Revision{ID: 3, WorkflowID: "src", Runbook: "v3"},
+	), nil)
+
+	client := &workflows.Client{Workflows: wfs, Revisions: revisions}
+	publish := false
+	cloned, err := client.CloneWorkflow(t.Context(), "src", workflows.Target{Name: "nightly-prod"}, workflows.CloneOptions{
+		Alias:   "stable",
+		Tags:    []string{"prod"},
+		Publish: &publish,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "dst", cloned.ID)
+	assert.Equal(t, map[int]int{2: 1}, cloned.Revisions)
+
+	_, err = client.CloneWorkflow(t.Context(), "src", workflows.Target{}, workflows.CloneOptions{})
+	require.ErrorContains(t, err, "needs a new name")
+	_, err = client.CloneWorkflow(t.Context(), "src", workflows.Target{Name: "x"}, workflows.CloneOptions{Alias: "missing"})
+	require.ErrorContains(t, err, `no revision with alias "missing"`)
+}
@@ -0,0 +1,591 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/submit"
+	"github.com/sacloud/workflows-api-go/timeline"
+)
+
+var executionCommands = map[string]command{
+	"start":      {usage: "start WORKFLOW_ID [--args JSON] [--policy P]", help: "start an execution", run: startExecution},
+	"list":       {usage: "list WORKFLOW_ID", help: "list executions", run: listExecutions},
+	"get":        {usage: "get WORKFLOW_ID EXECUTION_ID", help: "show an execution", run: getExecution},
+	"cancel":     {usage: "cancel WORKFLOW_ID EXECUTION_ID", help: "cancel an execution", run: cancelExecution},
+	"delete":     {usage: "delete WORKFLOW_ID EXECUTION_ID", help: "delete an execution", run: deleteExecution},
+	"cancel-all": {usage: "cancel-all WORKFLOW_ID [flags]", help: "cancel every queued or running execution", run: cancelAllExecutions},
+	"purge":      {usage: "purge WORKFLOW_ID [flags]", help: "delete finished executions", run: purgeExecutions},
+	"history":    {usage: "history WORKFLOW_ID EXECUTION_ID", help: "show the history of an execution", run: executionHistory},
+	"logs":       {usage: "logs WORKFLOW_ID EXECUTION_ID [--follow]", help: "print the history events, -f to wait for new ones", run: executionLogs},
+	"diagnose":   {usage: "diagnose WORKFLOW_ID EXECUTION_ID [--markdown]", help: "explain why an execution failed", run: diagnoseExecution},
+	"vars":       {usage: "vars WORKFLOW_ID EXECUTION_ID [--at N|--var NAME|--diff FROM,TO]", help: "inspect the variables during an execution", run: executionVariables},
+	"timeline":   {usage: "timeline WORKFLOW_ID EXECUTION_ID", help: "show the step durations and the critical path", run: executionTimeline},
+}
+
+func startExecution(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("start", flag.ContinueOnError)
+	name := fs.String("name", "", "name of the execution")
+	revision := fs.Int("revision", 0, "revision number to run (default: the latest)")
+	alias := fs.String("alias", "", "revision alias to run")
+	arguments := fs.String("args", "", "arguments as a JSON object")
+	argsFile := fs.String("args-file", "", "file containing the arguments, - for the standard input")
+	policy := fs.String("policy", "", "when the workflow is busy: auto (by its concurrency mode), wait, reject or enqueue (default: none, fail as the API does)")
+	maxWait := fs.Duration("max-wait", 0, "longest wait with --policy (default: no limit)")
+	pos, err := parse(a, fs, "executions start WORKFLOW_ID [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+
+	req := v1.CreateExecutionReq{}
+	if *name != "" {
+		req.Name = v1.NewOptString(*name)
+	}
+	if *revision > 0 {
+		req.RevisionId = v1.NewOptInt(*revision)
+	}
+	if *alias != "" {
+		req.RevisionAlias = v1.NewOptString(*alias)
+	}
+	if *argsFile != "" {
+		if *arguments, err = a.readFile(*argsFile); err != nil {
+			return err
+		}
+	}
+	if *arguments != "" {
+		req.Args = v1.NewOptString(*arguments)
+	}
+
+	var e *v1.CreateExecutionCreatedExecution
+	if *policy == "" {
+		e, err = a.client.Executions.Create(ctx, pos[0], v1.NewOptCreateExecutionReq(req))
+	} else {
+		e, err = a.submitExecution(ctx, pos[0], req, *policy, *maxWait)
+	}
+	if err != nil {
+		return err
+	}
+	return a.print(e, func() table {
+		return executionTable(executionRow{e.ExecutionId, e.Name, string(e.Status), e.Revision, e.CreatedAt})
+	})
+}
+
+// submitExecution starts the execution through submit.Submitter, reporting the wait on the standard error
+func (a *app) submitExecution(ctx context.Context, workflowID string, req v1.CreateExecutionReq, policy string, maxWait time.Duration) (*v1.CreateExecutionCreatedExecution, error) {
+	p, err := submit.ParsePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	s := submit.New(a.client, submit.WithPolicy(p), submit.WithMaxWait(maxWait),
+		submit.WithProgress(func(_ context.Context, p submit.Progress) {
+			switch {
+			case p.Execution != nil:
+				fmt.Fprintf(a.stderr, "created %s but unable to get the queue status: %v\n", p.Execution.ExecutionId, p.Err)
+			case p.LocalPosition > 0:
+				fmt.Fprintf(a.stderr, "waiting for %d submissions ahead (%s)\n", p.LocalPosition, p.Waited.Round(time.Second))
+			case p.Active > 0:
+				fmt.Fprintf(a.stderr, "waiting for %d active executions of the %s workflow (%s)\n", p.Active, p.Mode, p.Waited.Round(time.Second))
+			case p.Err != nil:
+				fmt.Fprintf(a.stderr, "retrying: %v (%s)\n", p.Err, p.Waited.Round(time.Second))
+			}
+		}))
+	res, err := s.Submit(ctx, workflowID, v1.NewOptCreateExecutionReq(req))
+	if err != nil {
+		return nil, err
+	}
+	if q := res.Queue; q != nil && q.Position > 0 {
+		fmt.Fprintf(a.stderr, "queued behind %d executions", q.Position)
+		if q.EstimatedWait > 0 {
+			fmt.Fprintf(a.stderr, ", starting in about %s", q.EstimatedWait.Round(time.Second))
+		}
+		fmt.Fprintln(a.stderr)
+	}
+	return res.Execution, nil
+}
+
+func listExecutions(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	var p pagination
+	p.register(fs)
+	pos, err := parse(a, fs, "executions list WORKFLOW_ID [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+
+	params := v1.ListExecutionParams{ID: pos[0]}
+	params.Page, params.PageLimit = p.params()
+	res, err := a.client.Executions.List(ctx, params)
+	if err != nil {
+		return err
+	}
+	return a.print(res, func() table {
+		var rows []executionRow
+		for _, e := range res.Executions {
+			rows = append(rows, executionRow{e.ExecutionId, e.Name, string(e.Status), e.Revision, e.CreatedAt})
+		}
+		return executionTable(rows...)
+	})
+}
+
+func getExecution(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	pos, err := parse(a, fs, "executions get WORKFLOW_ID EXECUTION_ID", args, 2)
+	if err != nil {
+		return err
+	}
+	e, err := a.client.Executions.Read(ctx, pos[0], pos[1])
+	if err != nil {
+		return err
+	}
+	return a.print(e, func() table {
+		t := executionTable(executionRow{e.ExecutionId, e.Name, string(e.Status), e.Revision, e.CreatedAt})
+		t.header = append(t.header, "STEPS", "RESULT", "ERROR")
+		t.rows[0] = append(t.rows[0], strconv.Itoa(e.StepCount), orDash(e.Result), orDash(e.Error))
+		return t
+	})
+}
+
+func cancelExecution(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("cancel", flag.ContinueOnError)
+	pos, err := parse(a, fs, "executions cancel WORKFLOW_ID EXECUTION_ID", args, 2)
+	if err != nil {
+		return err
+	}
+	e, err := a.client.Executions.Cancel(ctx, pos[0], pos[1])
+	if err != nil {
+		return err
+	}
+	return a.print(e, func() table {
+		return executionTable(executionRow{e.ExecutionId, e.Name, string(e.Status), e.Revision, e.CreatedAt})
+	})
+}
+
+func deleteExecution(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	pos, err := parse(a, fs, "executions delete WORKFLOW_ID EXECUTION_ID", args, 2)
+	if err != nil {
+		return err
+	}
+	if err := a.client.Executions.Delete(ctx, pos[0], pos[1]); err != nil {
+		return err
+	}
+	return a.done("execution %s deleted", pos[1])
+}
+
+// bulkFlags cancel-all, purgeの共通フラグ
+type bulkFlags struct {
+	statuses    stringList
+	olderThan   time.Duration
+	revision    int
+	name        string
+	concurrency int
+	dryRun      bool
+}
+
+func (f *bulkFlags) register(fs *flag.FlagSet) {
+	fs.Var(&f.statuses, "status", "status of the executions to target, can be repeated")
+	fs.DurationVar(&f.olderThan, "older-than", 0, "target only executions created at least this long ago, e.g. 720h")
+	fs.IntVar(&f.revision, "revision", 0, "target only executions of this revision")
+	fs.StringVar(&f.name, "name", "", "target only executions whose name matches this glob pattern")
+	fs.IntVar(&f.concurrency, "concurrency", workflows.DefaultBulkConcurrency, "number of requests sent at once")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "only list the executions that would be affected")
+}
+
+func (f *bulkFlags) filter() workflows.ExecutionFilter {
+	filter := workflows.ExecutionFilter{OlderThan: f.olderThan, Revision: f.revision, Name: f.name}
+	for _, s := range f.statuses {
+		filter.Statuses = append(filter.Statuses, v1.ListExecutionOKExecutionsItemStatus(s))
+	}
+	return filter
+}
+
+func (f *bulkFlags) options() workflows.BulkOptions {
+	return workflows.BulkOptions{Concurrency: f.concurrency, DryRun: f.dryRun}
+}
+
+func cancelAllExecutions(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("cancel-all", flag.ContinueOnError)
+	var f bulkFlags
+	f.register(fs)
+	pos, err := parse(a, fs, "executions cancel-all WORKFLOW_ID [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+	report, err := a.client.CancelAll(ctx, pos[0], f.filter(), f.options())
+	if err != nil {
+		return err
+	}
+	return a.printBulkReport(report)
+}
+
+func purgeExecutions(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	var f bulkFlags
+	f.register(fs)
+	pos, err := parse(a, fs, "executions purge WORKFLOW_ID [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+	if f.olderThan <= 0 && len(f.statuses) == 0 && f.revision == 0 && f.name == "" {
+		fmt.Fprintln(a.stderr, "purge needs at least one of --older-than, --status, --revision or --name")
+		return errUsage
+	}
+	report, err := a.client.PurgeExecutions(ctx, pos[0], f.filter(), f.options())
+	if err != nil {
+		return err
+	}
+	return a.printBulkReport(report)
+}
+
+// bulkEntry BulkResultの出力用の形式
+type bulkEntry struct {
+	ExecutionId string `json:"ExecutionId"`
+	Name        string `json:"Name"`
+	Status      string `json:"Status"`
+	Outcome     string `json:"Outcome"`
+	Error       string `json:"Error,omitempty"`
+}
+
+// printBulkReport prints the result of each execution, and returns the failures as an error
+func (a *app) printBulkReport(report *workflows.BulkReport) error {
+	entries := make([]bulkEntry, 0, len(report.Results))
+	for _, r := range report.Results {
+		e := bulkEntry{ExecutionId: r.Execution.ExecutionId, Name: r.Execution.Name, Status: string(r.Execution.Status), Outcome: string(r.Outcome)}
+		if r.Err != nil {
+			e.Error = r.Err.Error()
+		}
+		entries = append(entries, e)
+	}
+	err := a.print(entries, func() table {
+		t := table{header: []string{"ID", "NAME", "STATUS", "OUTCOME", "ERROR"}}
+		for _, e := range entries {
+			t.rows = append(t.rows, []string{e.ExecutionId, orDash(e.Name), e.Status, e.Outcome, orDash(e.Error)})
+		}
+		return t
+	})
+	if err != nil {
+		return err
+	}
+	return report.Err()
+}
+
+func executionHistory(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	var p pagination
+	p.register(fs)
+	pos, err := parse(a, fs, "executions history WORKFLOW_ID EXECUTION_ID [flags]", args, 2)
+	if err != nil {
+		return err
+	}
+
+	params := v1.ListExecutionHistoryParams{ID: pos[0], ExecutionId: pos[1]}
+	params.Page, params.PageLimit = p.params()
+	res, err := a.client.Executions.ListHistory(ctx, params)
+	if err != nil {
+		return err
+	}
+	return a.print(res, func() table {
+		t := table{header: []string{"TIME", "TYPE", "JOB", "THREAD"}}
+		for _, h := range res.Histories {
+			t.rows = append(t.rows, []string{formatTime(h.CreatedAt), string(h.Type), orDash(h.JobId), orDash(h.ThreadId)})
+		}
+		return t
+	})
+}
+
+func executionLogs(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	follow := fs.Bool("follow", false, "wait for new events until the execution ends")
+	fs.BoolVar(follow, "f", false, "shorthand for --follow")
+	interval := fs.Duration("interval", workflows.DefaultStreamInterval, "polling interval with --follow")
+	pos, err := parse(a, fs, "executions logs WORKFLOW_ID EXECUTION_ID [flags]", args, 2)
+	if err != nil {
+		return err
+	}
+
+	opts := workflows.StreamOptions{Follow: *follow, Interval: *interval}
+	for ev, err := range workflows.StreamHistory(ctx, a.client.Executions, pos[0], pos[1], opts) {
+		if err != nil {
+			return err
+		}
+		if err := a.printLogEntry(newLogEntry(ev)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func diagnoseExecution(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("diagnose", flag.ContinueOnError)
+	markdown := fs.Bool("markdown", false, "render the report as Markdown instead of plain text")
+	pos, err := parse(a, fs, "executions diagnose WORKFLOW_ID EXECUTION_ID [flags]", args, 2)
+	if err != nil {
+		return err
+	}
+	d, err := a.client.DiagnoseExecution(ctx, pos[0], pos[1])
+	if err != nil {
+		return err
+	}
+	if a.output != formatTable {
+		return a.print(d, nil)
+	}
+	report := d.Text()
+	if *markdown {
+		report = d.Markdown()
+	}
+	_, err = fmt.Fprint(a.stdout, report)
+	return err
+}
+
+func executionVariables(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("vars", flag.ContinueOnError)
+	at := fs.Int("at", -1, "show the variables after the N-th history event (default: the last)")
+	name := fs.String("var", "", "show the changes of the variable")
+	diff := fs.String("diff", "", "show the differences between two history events, e.g. 3,10")
+	pos, err := parse(a, fs, "executions vars WORKFLOW_ID EXECUTION_ID [flags]", args, 2)
+	if err != nil {
+		return err
+	}
+	h, err := a.client.LoadVariableHistory(ctx, pos[0], pos[1])
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *name != "":
+		changes := h.Changes(*name)
+		return a.print(changes, func() table {
+			t := table{header: []string{"INDEX", "TIME", "EVENT", "OLD", "NEW"}}
+			for _, c := range changes {
+				t.rows = append(t.rows, []string{strconv.Itoa(c.Index), formatTime(c.At), string(c.Type), compactJSON(c.Old), compactJSON(c.New)})
+			}
+			return t
+		})
+	case *diff != "":
+		var from, to int
+		if _, err := fmt.Sscanf(*diff, "%d,%d", &from, &to); err != nil {
+			return fmt.Errorf("invalid --diff %q, must be FROM,TO", *diff)
+		}
+		diffs, err := h.Diff(from, to)
+		if err != nil {
+			return err
+		}
+		return a.print(diffs, func() table {
+			t := table{header: []string{"NAME", "KIND", "OLD", "NEW"}}
+			for _, d := range diffs {
+				t.rows = append(t.rows, []string{d.Name, string(d.Kind), compactJSON(d.Old), compactJSON(d.New)})
+			}
+			return t
+		})
+	default:
+		index := *at
+		if index < 0 {
+			index = h.Len() - 1
+		}
+		state, err := h.StateAt(index)
+		if err != nil {
+			return err
+		}
+		return a.print(state, func() table {
+			t := table{header: []string{"NAME", "VALUE"}}
+			for _, k := range sortedKeys(state) {
+				t.rows = append(t.rows, []string{k, compactJSON(state[k])})
+			}
+			return t
+		})
+	}
+}
+
+// compactJSON formats a variable value for a table cell
+func compactJSON(v any) string {
+	if v == nil {
+		return "-"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func executionTimeline(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("timeline", flag.ContinueOnError)
+	chromeTrace := fs.String("chrome-trace", "", "write the timeline to the file in the Chrome trace-event format")
+	otlp := fs.String("otlp", "", "write the timeline to the file as OTLP/JSON spans")
+	pos, err := parse(a, fs, "executions timeline WORKFLOW_ID EXECUTION_ID [flags]", args, 2)
+	if err != nil {
+		return err
+	}
+
+	var events []timeline.Event
+	for ev, err := range workflows.StreamHistory(ctx, a.client.Executions, pos[0], pos[1], workflows.StreamOptions{}) {
+		if err != nil {
+			return err
+		}
+		events = append(events, ev)
+	}
+	tl := timeline.Build(events)
+
+	if *chromeTrace != "" {
+		if err := writeFile(*chromeTrace, tl.WriteChromeTrace); err != nil {
+			return err
+		}
+	}
+	if *otlp != "" {
+		if err := writeFile(*otlp, func(w io.Writer) error { return tl.WriteOTLP(w, "workflows") }); err != nil {
+			return err
+		}
+	}
+
+	critical := map[int]bool{}
+	for _, s := range tl.CriticalPath() {
+		critical[s.ID] = true
+	}
+	type spanView struct {
+		Kind     string        `json:"Kind"`
+		Name     string        `json:"Name"`
+		ThreadID string        `json:"ThreadId"`
+		Start    time.Time     `json:"Start"`
+		Duration time.Duration `json:"Duration"`
+		Critical bool          `json:"Critical"`
+		Open     bool          `json:"Open,omitempty"`
+		Failed   bool          `json:"Failed,omitempty"`
+	}
+	var spans []spanView
+	for _, s := range tl.Spans {
+		spans = append(spans, spanView{string(s.Kind), s.Name, s.ThreadID, s.Start, s.Duration(), critical[s.ID], s.Open, s.Failed})
+	}
+	return a.print(spans, func() table {
+		t := table{header: []string{"KIND", "NAME", "THREAD", "START", "DURATION", "CRITICAL"}}
+		for _, s := range spans {
+			mark := ""
+			if s.Critical {
+				mark = "*"
+			}
+			name := s.Name
+			if s.Open {
+				name += " (running)"
+			} else if s.Failed {
+				name += " (failed)"
+			}
+			t.rows = append(t.rows, []string{s.Kind, name, orDash(s.ThreadID), formatTime(s.Start), s.Duration.String(), mark})
+		}
+		return t
+	})
+}
+
+func writeFile(name string, write func(io.Writer) error) error {
+	f, err := os.Create(name) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// logEntry 実行履歴のイベントをVariablesとMetaをデコードした形で表したもの
+type logEntry struct {
+	CreatedAt  time.Time `json:"CreatedAt"`
+	Type       string    `json:"Type"`
+	JobID      string    `json:"JobId,omitempty"`
+	ThreadID   string    `json:"ThreadId,omitempty"`
+	Meta       any       `json:"Meta,omitempty"`
+	Variables  any       `json:"Variables,omitempty"`
+	StackTrace string    `json:"StackTrace,omitempty"`
+}
+
+func newLogEntry(ev v1.ListExecutionHistoryOKHistoriesItem) logEntry {
+	return logEntry{
+		CreatedAt:  ev.CreatedAt,
+		Type:       string(ev.Type),
+		JobID:      ev.JobId,
+		ThreadID:   ev.ThreadId,
+		Meta:       workflows.DecodeHistoryField(ev.Meta),
+		Variables:  workflows.DecodeHistoryField(ev.Variables),
+		StackTrace: ev.StackTrace,
+	}
+}
+
+// printLogEntry writes an entry as soon as it arrives: one line per entry for the table format,
+// one JSON object per line for JSON and one document per entry for YAML
+func (a *app) printLogEntry(e logEntry) error {
+	switch a.output {
+	case formatJSON:
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(a.stdout, "%s\n", data)
+		return err
+	case formatYAML:
+		data, err := yaml.Marshal(e)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(a.stdout, "---\n%s", data)
+		return err
+	default:
+		line := fmt.Sprintf("%s  %-20s  job=%s thread=%s", formatTime(e.CreatedAt), e.Type, orDash(e.JobID), orDash(e.ThreadID))
+		for _, field := range []struct {
+			name  string
+			value any
+		}{{"meta", e.Meta}, {"variables", e.Variables}} {
+			if field.value == nil {
+				continue
+			}
+			data, err := json.Marshal(field.value)
+			if err != nil {
+				return err
+			}
+			line += fmt.Sprintf(" %s=%s", field.name, data)
+		}
+		if e.StackTrace != "" {
+			line += "\n" + e.StackTrace
+		}
+		_, err := fmt.Fprintln(a.stdout, line)
+		return err
+	}
+}
+
+// executionRow 各種実行型の表示に共通する項目
+type executionRow struct {
+	id        string
+	name      string
+	status    string
+	revision  int
+	createdAt time.Time
+}
+
+func executionTable(rows ...executionRow) table {
+	t := table{header: []string{"ID", "NAME", "STATUS", "REVISION", "CREATED"}}
+	for _, r := range rows {
+		t.rows = append(t.rows, []string{r.id, orDash(r.name), r.status, strconv.Itoa(r.revision), formatTime(r.createdAt)})
+	}
+	return t
+}
@@ -0,0 +1,218 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// workflows is a command-line tool for the SAKURA Cloud Workflows API.
+//
+//	workflows [global flags] <group> <command> [flags] [args]
+//
+// Credentials are read through saclient-go, i.e. from --token/--secret, the
+// SAKURACLOUD_* environment variables or the profile selected by --profile.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/sacloud/saclient-go"
+	"github.com/sacloud/workflows-api-go"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Environ(), os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// app 1回のコマンド実行の状態
+type app struct {
+	client *workflows.Client
+	// options clientの作成に使ったオプション。別のゾーンのClientを作る際に使う
+	options []workflows.Option
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+	output  format
+}
+
+// command サブコマンドの実装
+type command struct {
+	usage string
+	help  string
+	run   func(ctx context.Context, a *app, args []string) error
+}
+
+var groups = map[string]map[string]command{
+	"workflows":    workflowCommands,
+	"revisions":    revisionCommands,
+	"executions":   executionCommands,
+	"subscription": subscriptionCommands,
+	"webhook":      webhookCommands,
+}
+
+// groupAliases 短縮形のグループ名
+var groupAliases = map[string]string{
+	"exec": "executions",
+}
+
+// errUsage is returned when the command line is malformed; the usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+// run executes the command line and returns the exit code. opts are appended to the options
+// derived from the flags, which lets tests replace the HTTP client.
+func run(ctx context.Context, args, environ []string, stdin io.Reader, stdout, stderr io.Writer, opts ...workflows.Option) int {
+	var sa saclient.Client
+	fs := sa.FlagSet(flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := formatTable
+	fs.Var(&output, "output", "output format: table, json or yaml")
+	fs.Var(&output, "o", "shorthand for --output")
+	zone := fs.String("zone", "", "zone of the API endpoint (default: the endpoint configured in the profile)")
+	apiRootURL := fs.String("api-root-url", "", "API root URL, takes precedence over --zone")
+	fs.Usage = func() { printUsage(stderr, fs) }
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	rest := fs.Args()
+	if len(rest) == 0 {
+		fs.Usage()
+		return 2
+	}
+	group := rest[0]
+	if alias, ok := groupAliases[group]; ok {
+		group = alias
+	}
+	cmds, ok := groups[group]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command group %q\n\n", rest[0])
+		fs.Usage()
+		return 2
+	}
+	cmd, rest, ok := lookup(cmds, rest[1:])
+	if !ok {
+		printGroupUsage(stderr, group, cmds)
+		return 2
+	}
+
+	if err := sa.SetEnviron(environ); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	options := []workflows.Option{workflows.WithSaclient(&sa)}
+	if *zone != "" {
+		options = append(options, workflows.WithZone(*zone))
+	}
+	if *apiRootURL != "" {
+		options = append(options, workflows.WithAPIRootURL(*apiRootURL))
+	}
+	options = append(options, opts...)
+	client, err := workflows.New(options...)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	a := &app{client: client, options: options, stdin: stdin, stdout: stdout, stderr: stderr, output: output}
+	if err := cmd.run(ctx, a, rest); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// lookup finds the command named by the leading words of args, e.g. "alias set"
+func lookup(cmds map[string]command, args []string) (command, []string, bool) {
+	for n := min(len(args), 2); n > 0; n-- {
+		if cmd, ok := cmds[strings.Join(args[:n], " ")]; ok {
+			return cmd, args[n:], true
+		}
+	}
+	return command{}, nil, false
+}
+
+func printUsage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: workflows [global flags] <group> <command> [flags] [args]\n\nGroups:\n")
+	for _, name := range sortedKeys(groups) {
+		fmt.Fprintf(w, "  %s\n", name)
+	}
+	for _, alias := range sortedKeys(groupAliases) {
+		fmt.Fprintf(w, "  %s (alias of %s)\n", alias, groupAliases[alias])
+	}
+	fmt.Fprintf(w, "\nGlobal flags:\n")
+	fs.PrintDefaults()
+}
+
+func printGroupUsage(w io.Writer, group string, cmds map[string]command) {
+	fmt.Fprintf(w, "Usage: workflows [global flags] %s <command> [flags] [args]\n\nCommands:\n", group)
+	for _, name := range sortedKeys(cmds) {
+		fmt.Fprintf(w, "  %-40s %s\n", cmds[name].usage, cmds[name].help)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parse parses flags interspersed with positional arguments and checks the number of the latter.
+// A negative nargs accepts one or more positional arguments.
+func parse(a *app, fs *flag.FlagSet, usage string, args []string, nargs int) ([]string, error) {
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: workflows %s\n", usage)
+		fs.PrintDefaults()
+	}
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if (nargs >= 0 && len(positional) != nargs) || (nargs < 0 && len(positional) == 0) {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
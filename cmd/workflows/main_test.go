@@ -0,0 +1,263 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	"github.com/sacloud/workflows-api-go/webhook"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWorkflowJSON = `{"Id":"123456789012","Name":"test-workflow","Publish":true,"Logging":false,"Tags":[],"CreatedAt":"2025-01-01T00:00:00Z","UpdatedAt":"2025-01-01T00:00:00Z"}`
+
+// doerFunc is an ht.Client serving requests in process
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+// execute runs the command line against handler and returns the exit code, stdout and stderr
+func execute(t *testing.T, handler http.HandlerFunc, args ...string) (int, string, string) {
+	t.Helper()
+
+	doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Result(), nil
+	})
+	var stdout, stderr bytes.Buffer
+	code := run(t.Context(), args, nil, strings.NewReader(""), &stdout, &stderr, workflows.WithHTTPClient(doer))
+	return code, stdout.String(), stderr.String()
+}
+
+func writeJSON(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, body)
+}
+
+func TestRun_workflowsList(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/workflows"), r.URL.Path)
+		assert.Equal(t, "test", r.URL.Query().Get("Name"))
+		writeJSON(w, http.StatusOK, `{"is_ok":true,"Total":1,"From":0,"Count":1,"Workflows":[`+testWorkflowJSON+`]}`)
+	}
+
+	code, stdout, stderr := execute(t, handler, "workflows", "list", "--name", "test")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "ID")
+	assert.Contains(t, stdout, "123456789012")
+	assert.Contains(t, stdout, "test-workflow")
+
+	code, stdout, stderr = execute(t, handler, "-o", "json", "workflows", "list", "--name", "test")
+	require.Equal(t, 0, code, stderr)
+	var got struct{ Workflows []struct{ Name string } }
+	require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+	require.Len(t, got.Workflows, 1)
+	assert.Equal(t, "test-workflow", got.Workflows[0].Name)
+
+	code, stdout, stderr = execute(t, handler, "--output", "yaml", "workflows", "list", "--name", "test")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "Name: test-workflow")
+}
+
+func TestRun_workflowsUpdate(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		// only the flags given on the command line are sent
+		assert.JSONEq(t, `{"Name":"renamed","Publish":false}`, string(body))
+		writeJSON(w, http.StatusOK, `{"is_ok":true,"Workflow":`+testWorkflowJSON+`}`)
+	}
+
+	code, _, stderr := execute(t, handler, "workflows", "update", "123456789012", "--name", "renamed", "--publish=false")
+	require.Equal(t, 0, code, stderr)
+}
+
+func TestRun_revisionsAlias(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/workflows/123456789012/revisions/2/revision_alias"), r.URL.Path)
+		writeJSON(w, http.StatusOK, `{"is_ok":true,"Revision":{"RevisionId":2,"WorkflowId":"123456789012","Runbook":"","CreatedAt":"2025-01-01T00:00:00Z","UpdatedAt":"2025-01-01T00:00:00Z"}}`)
+	}
+
+	code, stdout, stderr := execute(t, handler, "revisions", "alias", "unset", "123456789012", "2")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "alias of revision 2 removed")
+}
+
+func TestRun_errors(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, `{"is_ok":false,"Message":"C-0040 Workflow not found"}`)
+	}
+
+	code, _, stderr := execute(t, handler, "workflows", "get", "123456789012")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "C-0040")
+
+	code, _, stderr = execute(t, handler, "nothing")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command group "nothing"`)
+
+	code, _, stderr = execute(t, handler, "workflows", "get")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "Usage: workflows workflows get WORKFLOW_ID")
+
+	code, _, _ = execute(t, handler, "-o", "xml", "workflows", "list")
+	assert.Equal(t, 2, code)
+}
+
+func TestRun_executionsCancelAll(t *testing.T) {
+	wf := workflowsmock.Workflow{ID: "123456789012", Name: "test-workflow"}
+	list, err := workflowsmock.ExecutionList(
+		workflowsmock.Execution{ID: "e1", Name: "nightly", Workflow: wf, Status: "Running", Args: "{}", Result: "-", Error: "-"},
+		workflowsmock.Execution{ID: "e2", Name: "nightly", Workflow: wf, Status: "Succeeded", Args: "{}", Result: "-", Error: "-"},
+	).MarshalJSON()
+	require.NoError(t, err)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "dry run must not cancel")
+		writeJSON(w, http.StatusOK, string(list))
+	}
+
+	code, stdout, stderr := execute(t, handler, "-o", "json", "executions", "cancel-all", "123456789012", "--dry-run")
+	require.Equal(t, 0, code, stderr)
+	var entries []map[string]string
+	require.NoError(t, json.Unmarshal([]byte(stdout), &entries))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "e1", entries[0]["ExecutionId"])
+	assert.Equal(t, "dry-run", entries[0]["Outcome"])
+
+	code, _, stderr = execute(t, handler, "executions", "purge", "123456789012")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "--older-than")
+}
+
+const testHistoryJSON = `{"is_ok":true,"Total":2,"From":0,"Count":2,"Histories":[` +
+	`{"WorkflowExecutionId":"exec","JobId":"job","ThreadId":"main","Type":"workflowWillStart","CreatedAt":"2025-01-01T00:00:00Z","Meta":"{}","StackTrace":"-","Variables":"{\"name\":\"value\"}"},` +
+	`{"WorkflowExecutionId":"exec","JobId":"job","ThreadId":"main","Type":"workflowDidCompleted","CreatedAt":"2025-01-01T00:00:01Z","Meta":"{}","StackTrace":"-","Variables":"{}"}]}`
+
+func TestRun_execLogs(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/workflows/123456789012/executions/exec/exec_history"), r.URL.Path)
+		assert.Equal(t, "asc", r.URL.Query().Get("SortOrder"))
+		writeJSON(w, http.StatusOK, testHistoryJSON)
+	}
+
+	code, stdout, stderr := execute(t, handler, "exec", "logs", "123456789012", "exec", "--follow")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, `workflowWillStart     job=job thread=main meta={} variables={"name":"value"}`)
+	assert.Contains(t, stdout, "workflowDidCompleted")
+
+	code, stdout, stderr = execute(t, handler, "-o", "json", "exec", "logs", "123456789012", "exec")
+	require.Equal(t, 0, code, stderr)
+	var first struct {
+		Type      string
+		Variables map[string]string
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 2)
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "workflowWillStart", first.Type)
+	assert.Equal(t, "value", first.Variables["name"])
+}
+
+func TestRun_executionsTimeline(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, testHistoryJSON)
+	}
+
+	trace := filepath.Join(t.TempDir(), "trace.json")
+	code, stdout, stderr := execute(t, handler, "executions", "timeline", "123456789012", "exec", "--chrome-trace", trace)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "workflow")
+	assert.Contains(t, stdout, "1s")
+
+	data, err := os.ReadFile(trace)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"traceEvents"`)
+}
+
+func TestRun_executionsVars(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, testHistoryJSON)
+	}
+
+	code, stdout, stderr := execute(t, handler, "executions", "vars", "123456789012", "exec", "--at", "0")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "name")
+	assert.Contains(t, stdout, `"value"`)
+
+	code, stdout, stderr = execute(t, handler, "-o", "json", "executions", "vars", "123456789012", "exec", "--var", "name")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, `"New": "value"`)
+}
+
+func TestRun_webhookRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	var got []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(webhook.EventIDHeader))
+		cancel()
+	}))
+	defer receiver.Close()
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "endpoints.yaml")
+	require.NoError(t, os.WriteFile(config, []byte("endpoints:\n- name: ci\n  url: "+receiver.URL+"\n"), 0o600))
+
+	execution := workflowsmock.Execution{ID: "e1", Name: "e1", Workflow: workflowsmock.Workflow{ID: "123456789012", Name: "wf"}, Status: "Running",
+		Args: "{}", Result: "{}", Error: "-", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	var lists int
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/workflows/123456789012/executions"), r.URL.Path)
+		if lists++; lists > 1 {
+			execution.Status = "Succeeded"
+		}
+		body, err := workflowsmock.ExecutionList(execution).MarshalJSON()
+		require.NoError(t, err)
+		writeJSON(w, http.StatusOK, string(body))
+	}
+
+	doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Result(), nil
+	})
+	var stdout, stderr bytes.Buffer
+	code := run(ctx, []string{"webhook", "run", "--config", config, "--dead-letter", filepath.Join(dir, "dead.jsonl"),
+		"--workflow", "123456789012", "--interval", "1ms"}, nil, strings.NewReader(""), &stdout, &stderr, workflows.WithHTTPClient(doer))
+	require.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, []string{"e1/Succeeded"}, got)
+
+	code, _, stderr2 := execute(t, handler, "webhook", "run")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr2, "Usage: workflows webhook run --config FILE")
+}
@@ -0,0 +1,168 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+var revisionCommands = map[string]command{
+	"create":      {usage: "create WORKFLOW_ID --runbook-file FILE", help: "create a revision", run: createRevision},
+	"list":        {usage: "list WORKFLOW_ID", help: "list revisions", run: listRevisions},
+	"get":         {usage: "get WORKFLOW_ID REVISION", help: "show a revision", run: getRevision},
+	"alias set":   {usage: "alias set WORKFLOW_ID REVISION ALIAS", help: "set the alias of a revision", run: setRevisionAlias},
+	"alias unset": {usage: "alias unset WORKFLOW_ID REVISION", help: "remove the alias of a revision", run: unsetRevisionAlias},
+}
+
+func createRevision(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	runbookFile := fs.String("runbook-file", "", "file containing the runbook, - for the standard input (required)")
+	alias := fs.String("alias", "", "alias of the revision")
+	pos, err := parse(a, fs, "revisions create WORKFLOW_ID --runbook-file FILE [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+	if *runbookFile == "" {
+		fs.Usage()
+		return errUsage
+	}
+	runbook, err := a.readFile(*runbookFile)
+	if err != nil {
+		return err
+	}
+
+	req := v1.CreateWorkflowRevisionReq{Runbook: runbook}
+	if *alias != "" {
+		req.RevisionAlias = v1.NewOptString(*alias)
+	}
+	r, err := a.client.Revisions.Create(ctx, pos[0], req)
+	if err != nil {
+		return err
+	}
+	return a.print(r, func() table {
+		return revisionTable(revisionRow{r.RevisionId, r.WorkflowId, r.RevisionAlias.Or(""), r.CreatedAt})
+	})
+}
+
+func listRevisions(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	var p pagination
+	p.register(fs)
+	pos, err := parse(a, fs, "revisions list WORKFLOW_ID [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+
+	params := v1.ListWorkflowRevisionsParams{ID: pos[0]}
+	params.Page, params.PageLimit = p.params()
+	res, err := a.client.Revisions.List(ctx, params)
+	if err != nil {
+		return err
+	}
+	return a.print(res, func() table {
+		var rows []revisionRow
+		for _, r := range res.Revisions {
+			rows = append(rows, revisionRow{r.RevisionId, r.WorkflowId, r.RevisionAlias.Or(""), r.CreatedAt})
+		}
+		return revisionTable(rows...)
+	})
+}
+
+func getRevision(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	pos, err := parse(a, fs, "revisions get WORKFLOW_ID REVISION", args, 2)
+	if err != nil {
+		return err
+	}
+	revision, err := parseRevision(pos[1])
+	if err != nil {
+		return err
+	}
+	r, err := a.client.Revisions.Read(ctx, pos[0], revision)
+	if err != nil {
+		return err
+	}
+	if a.output == formatTable {
+		// the runbook is the interesting part of a single revision
+		_, err := fmt.Fprint(a.stdout, r.Runbook)
+		return err
+	}
+	return a.print(r, nil)
+}
+
+func setRevisionAlias(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("alias set", flag.ContinueOnError)
+	pos, err := parse(a, fs, "revisions alias set WORKFLOW_ID REVISION ALIAS", args, 3)
+	if err != nil {
+		return err
+	}
+	revision, err := parseRevision(pos[1])
+	if err != nil {
+		return err
+	}
+	r, err := a.client.Revisions.UpdateAlias(ctx, pos[0], revision, v1.UpdateWorkflowRevisionAliasReq{RevisionAlias: pos[2]})
+	if err != nil {
+		return err
+	}
+	return a.print(r, func() table {
+		return revisionTable(revisionRow{r.RevisionId, r.WorkflowId, r.RevisionAlias.Or(""), r.CreatedAt})
+	})
+}
+
+func unsetRevisionAlias(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("alias unset", flag.ContinueOnError)
+	pos, err := parse(a, fs, "revisions alias unset WORKFLOW_ID REVISION", args, 2)
+	if err != nil {
+		return err
+	}
+	revision, err := parseRevision(pos[1])
+	if err != nil {
+		return err
+	}
+	if err := a.client.Revisions.DeleteAlias(ctx, pos[0], revision); err != nil {
+		return err
+	}
+	return a.done("alias of revision %d removed", revision)
+}
+
+func parseRevision(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid revision number %q", s)
+	}
+	return n, nil
+}
+
+// revisionRow 各種リビジョン型の表示に共通する項目
+type revisionRow struct {
+	id         int
+	workflowID string
+	alias      string
+	createdAt  time.Time
+}
+
+func revisionTable(rows ...revisionRow) table {
+	t := table{header: []string{"REVISION", "WORKFLOW", "ALIAS", "CREATED"}}
+	for _, r := range rows {
+		t.rows = append(t.rows, []string{strconv.Itoa(r.id), r.workflowID, orDash(r.alias), formatTime(r.createdAt)})
+	}
+	return t
+}
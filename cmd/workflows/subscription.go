@@ -0,0 +1,205 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/billing"
+)
+
+var subscriptionCommands = map[string]command{
+	"plans":       {usage: "plans", help: "list the plans", run: listPlans},
+	"show":        {usage: "show", help: "show the current subscription", run: showSubscription},
+	"estimate":    {usage: "estimate [--by workflow|tag|plan]", help: "estimate the cost of this month", run: estimateCost},
+	"subscribe":   {usage: "subscribe PLAN_ID", help: "subscribe to a plan", run: subscribe},
+	"ensure":      {usage: "ensure PLAN", help: "subscribe to a plan by name or grade unless already subscribed", run: ensureSubscription},
+	"change-plan": {usage: "change-plan PLAN", help: "switch to a plan by name or grade", run: changePlan},
+	"unsubscribe": {usage: "unsubscribe [--force]", help: "cancel the subscription", run: unsubscribe},
+}
+
+func listPlans(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("plans", flag.ContinueOnError)
+	if _, err := parse(a, fs, "subscription plans", args, 0); err != nil {
+		return err
+	}
+	res, err := a.client.Subscription.ListPlans(ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(res, func() table {
+		t := table{header: []string{"ID", "NAME", "BASE PRICE", "INCLUDED STEPS", "OVERAGE"}}
+		for _, p := range res.Plans {
+			t.rows = append(t.rows, []string{
+				strconv.Itoa(p.ID),
+				p.Name,
+				strconv.Itoa(p.BasePrice),
+				strconv.Itoa(p.IncludedSteps),
+				fmt.Sprintf("%d / %d steps", p.OveragePricePerUnit, p.OverageStepUnit),
+			})
+		}
+		return t
+	})
+}
+
+func showSubscription(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("show", flag.ContinueOnError)
+	if _, err := parse(a, fs, "subscription show", args, 0); err != nil {
+		return err
+	}
+	res, err := a.client.Subscription.Read(ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(res, func() table {
+		t := table{header: []string{"PLAN", "NAME", "ACTIVATED"}}
+		if plan, ok := res.MonthAppliedPlan.Get(); ok {
+			t.rows = append(t.rows, []string{strconv.Itoa(plan.PlanId), plan.PlanName, formatTime(plan.ActivateFrom)})
+		} else if plan, ok := res.CurrentPlan.Get(); ok {
+			t.rows = append(t.rows, []string{strconv.Itoa(plan.PlanId), "-", formatTime(plan.ActivateFrom)})
+		}
+		return t
+	})
+}
+
+func estimateCost(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("estimate", flag.ContinueOnError)
+	by := fs.String("by", "", "break the projected cost down by workflow, tag or plan")
+	if _, err := parse(a, fs, "subscription estimate [--by workflow|tag|plan]", args, 0); err != nil {
+		return err
+	}
+	switch *by {
+	case "", "workflow", "tag", "plan":
+	default:
+		fmt.Fprintf(a.stderr, "unknown breakdown %q (must be one of workflow, tag or plan)\n", *by)
+		return errUsage
+	}
+	r, err := billing.Estimate(ctx, a.client, time.Now())
+	if err != nil {
+		return err
+	}
+	return a.print(r, func() table {
+		switch *by {
+		case "workflow":
+			t := table{header: []string{"ID", "NAME", "EXECUTIONS", "STEPS", "COST"}}
+			for _, u := range r.Workflows {
+				t.rows = append(t.rows, []string{u.WorkflowID, u.Name, strconv.Itoa(u.Executions), strconv.Itoa(u.Steps), strconv.Itoa(u.Cost)})
+			}
+			return t
+		case "tag":
+			t := table{header: []string{"TAG", "WORKFLOWS", "STEPS", "COST"}}
+			for _, u := range r.Tags {
+				t.rows = append(t.rows, []string{orDash(u.Tag), strconv.Itoa(u.Workflows), strconv.Itoa(u.Steps), strconv.Itoa(u.Cost)})
+			}
+			return t
+		case "plan":
+			t := table{header: []string{"ID", "NAME", "BASE PRICE", "OVERAGE", "TAX", "TOTAL"}}
+			for _, p := range r.Plans {
+				t.rows = append(t.rows, []string{
+					strconv.Itoa(p.Plan.ID), p.Plan.Name,
+					strconv.Itoa(p.Cost.Base), strconv.Itoa(p.Cost.Overage), strconv.Itoa(p.Cost.Tax), strconv.Itoa(p.Cost.Total),
+				})
+			}
+			return t
+		}
+		return table{
+			header: []string{"PLAN", "STEPS", "PROJECTED STEPS", "CURRENT", "PROJECTED", "RECOMMENDED"},
+			rows: [][]string{{
+				r.Plan.Name,
+				strconv.Itoa(r.Current.Steps),
+				strconv.Itoa(r.Projected.Steps),
+				strconv.Itoa(r.Current.Total),
+				strconv.Itoa(r.Projected.Total),
+				r.Recommended.Name,
+			}},
+		}
+	})
+}
+
+func subscribe(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("subscribe", flag.ContinueOnError)
+	pos, err := parse(a, fs, "subscription subscribe PLAN_ID", args, 1)
+	if err != nil {
+		return err
+	}
+	planID, err := strconv.Atoi(pos[0])
+	if err != nil {
+		return fmt.Errorf("invalid plan ID %q", pos[0])
+	}
+	if err := a.client.Subscription.Create(ctx, v1.CreateSubscriptionReq{PlanId: planID}); err != nil {
+		return err
+	}
+	return a.done("subscribed to plan %d", planID)
+}
+
+func ensureSubscription(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("ensure", flag.ContinueOnError)
+	pos, err := parse(a, fs, "subscription ensure PLAN", args, 1)
+	if err != nil {
+		return err
+	}
+	change, err := a.client.EnsureSubscription(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return a.printPlanChange(change)
+}
+
+func changePlan(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("change-plan", flag.ContinueOnError)
+	pos, err := parse(a, fs, "subscription change-plan PLAN", args, 1)
+	if err != nil {
+		return err
+	}
+	change, err := a.client.ChangePlan(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return a.printPlanChange(change)
+}
+
+func (a *app) printPlanChange(change *workflows.PlanChange) error {
+	return a.print(change, func() table {
+		from, applied := "-", "-"
+		if change.From != nil {
+			from = change.From.Name
+		}
+		if change.After != nil {
+			applied = change.After.PlanName
+		}
+		return table{
+			header: []string{"FROM", "TO", "CHANGED", "ACTIVATED", "APPLIED THIS MONTH"},
+			rows:   [][]string{{from, change.To.Name, strconv.FormatBool(change.Changed), formatTime(change.ActivateFrom), applied}},
+		}
+	})
+}
+
+func unsubscribe(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("unsubscribe", flag.ContinueOnError)
+	force := fs.Bool("force", false, "unsubscribe even if workflows remain")
+	if _, err := parse(a, fs, "subscription unsubscribe [--force]", args, 0); err != nil {
+		return err
+	}
+	if err := a.client.Unsubscribe(ctx, workflows.UnsubscribeOptions{Force: *force}); err != nil {
+		return err
+	}
+	return a.done("unsubscribed")
+}
@@ -0,0 +1,73 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+
+	"github.com/sacloud/workflows-api-go/informer"
+	"github.com/sacloud/workflows-api-go/webhook"
+)
+
+var webhookCommands = map[string]command{
+	"run": {usage: "run --config FILE [--dead-letter FILE]", help: "notify the webhook endpoints of finished executions until interrupted", run: runWebhook},
+}
+
+func runWebhook(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	config := fs.String("config", "", "YAML or JSON file listing the endpoints (required)")
+	deadLetter := fs.String("dead-letter", "", "file to append undeliverable events to as JSON lines")
+	interval := fs.Duration("interval", informer.DefaultInterval, "how often the executions are listed")
+	workers := fs.Int("workers", webhook.DefaultWorkers, "number of events delivered at once to each endpoint")
+	var workflowIDs stringList
+	fs.Var(&workflowIDs, "workflow", "watch only the executions of the workflow; can be repeated")
+	if _, err := parse(a, fs, "webhook run --config FILE [--dead-letter FILE] [flags]", args, 0); err != nil {
+		return err
+	}
+	if *config == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	data, err := os.ReadFile(*config)
+	if err != nil {
+		return err
+	}
+	endpoints, err := webhook.LoadEndpoints(data)
+	if err != nil {
+		return err
+	}
+	opts := []webhook.Option{
+		webhook.WithLogger(slog.New(slog.NewTextHandler(a.stderr, nil))),
+		webhook.WithWorkers(*workers),
+		webhook.WithWorkflows(workflowIDs...),
+		webhook.WithInformerOptions(informer.WithInterval(*interval)),
+	}
+	if *deadLetter != "" {
+		opts = append(opts, webhook.WithDeadLetter(webhook.NewFileDeadLetter(*deadLetter)))
+	}
+	d, err := webhook.New(a.client, endpoints, opts...)
+	if err != nil {
+		return err
+	}
+	if err := d.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
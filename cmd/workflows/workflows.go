@@ -0,0 +1,503 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+var workflowCommands = map[string]command{
+	"create":  {usage: "create --name NAME --runbook-file FILE", help: "create a workflow", run: createWorkflow},
+	"list":    {usage: "list [--name NAME] [--selector SELECTOR]", help: "list workflows", run: listWorkflows},
+	"get":     {usage: "get WORKFLOW_ID", help: "show a workflow", run: getWorkflow},
+	"update":  {usage: "update WORKFLOW_ID [flags]", help: "update a workflow", run: updateWorkflow},
+	"tags":    {usage: "tags WORKFLOW_ID [--add TAG] [--remove TAG] [--set TAG]", help: "change the tags of a workflow", run: tagWorkflow},
+	"delete":  {usage: "delete WORKFLOW_ID [--cascade]", help: "delete a workflow, --cascade to clear its executions first", run: deleteWorkflow},
+	"suggest": {usage: "suggest NAME", help: "suggest workflow names", run: suggestWorkflows},
+	"export":  {usage: "export WORKFLOW_ID... [--file FILE]", help: "write workflows and their revisions to a bundle", run: exportWorkflows},
+	"clone":   {usage: "clone WORKFLOW_ID --name NAME [--to-zone ZONE]", help: "copy a workflow with its revisions", run: cloneWorkflow},
+	"import":  {usage: "import FILE [--conflict POLICY]", help: "create workflows from a bundle", run: importWorkflows},
+}
+
+// stringList 繰り返し指定できるフラグ
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// pagination 一覧系コマンドに共通のフラグ
+type pagination struct {
+	page  int
+	limit int
+}
+
+func (p *pagination) register(fs *flag.FlagSet) {
+	fs.IntVar(&p.page, "page", 0, "page number (default: the first page)")
+	fs.IntVar(&p.limit, "limit", 0, "number of items per page (default: the API default)")
+}
+
+func (p *pagination) params() (page, limit v1.OptInt) {
+	if p.page > 0 {
+		page = v1.NewOptInt(p.page)
+	}
+	if p.limit > 0 {
+		limit = v1.NewOptInt(p.limit)
+	}
+	return page, limit
+}
+
+// readFile reads the named file, or the standard input for "-"
+func (a *app) readFile(name string) (string, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(a.stdin)
+	} else {
+		data, err = os.ReadFile(name) //nolint:gosec
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func createWorkflow(ctx context.Context, a *app, args []string) error {
+	const usage = "workflows create --name NAME --runbook-file FILE [flags]"
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	name := fs.String("name", "", "name of the workflow (required)")
+	description := fs.String("description", "", "description of the workflow")
+	runbookFile := fs.String("runbook-file", "", "file containing the runbook, - for the standard input (required)")
+	publish := fs.Bool("publish", false, "publish the workflow")
+	logging := fs.Bool("logging", false, "enable logging")
+	alias := fs.String("alias", "", "alias of the first revision")
+	concurrencyMode := fs.String("concurrency-mode", "", "parallel, lock or queue")
+	servicePrincipalID := fs.String("service-principal-id", "", "ID of the service principal the workflow runs as")
+	var tags stringList
+	fs.Var(&tags, "tag", "tag of the workflow, can be repeated")
+	if _, err := parse(a, fs, usage, args, 0); err != nil {
+		return err
+	}
+	if *name == "" || *runbookFile == "" {
+		fs.Usage()
+		return errUsage
+	}
+	runbook, err := a.readFile(*runbookFile)
+	if err != nil {
+		return err
+	}
+
+	req := v1.CreateWorkflowReq{
+		Name:    *name,
+		Runbook: runbook,
+		Publish: *publish,
+		Logging: *logging,
+		Tags:    []v1.CreateWorkflowReqTagsItem{},
+	}
+	if *description != "" {
+		req.Description = v1.NewOptString(*description)
+	}
+	if *alias != "" {
+		req.RevisionAlias = v1.NewOptString(*alias)
+	}
+	if *concurrencyMode != "" {
+		req.ConcurrencyMode = v1.NewOptCreateWorkflowReqConcurrencyMode(v1.CreateWorkflowReqConcurrencyMode(*concurrencyMode))
+	}
+	if *servicePrincipalID != "" {
+		id, err := workflows.ParseServicePrincipalID(*servicePrincipalID)
+		if err != nil {
+			return err
+		}
+		req.ServicePrincipalId = id.CreateWorkflowReq()
+	}
+	for _, tag := range tags {
+		req.Tags = append(req.Tags, v1.CreateWorkflowReqTagsItem{Name: tag})
+	}
+
+	created, err := a.client.Workflows.Create(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.print(created, func() table {
+		return workflowTable(workflowRow{created.ID, created.Name, created.Publish, created.Logging, created.CreatedAt})
+	})
+}
+
+func listWorkflows(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	name := fs.String("name", "", "filter by name (partial match)")
+	selector := fs.String("selector", "", "filter by tags such as env=prod,team!=infra; lists all the pages")
+	var p pagination
+	p.register(fs)
+	if _, err := parse(a, fs, "workflows list [flags]", args, 0); err != nil {
+		return err
+	}
+	if *selector != "" {
+		return a.selectWorkflows(ctx, *selector, *name)
+	}
+
+	params := v1.ListWorkflowParams{}
+	params.Page, params.PageLimit = p.params()
+	if *name != "" {
+		params.Name = v1.NewOptString(*name)
+		params.NameMatchType = v1.NewOptListWorkflowNameMatchType(v1.ListWorkflowNameMatchTypePartial)
+	}
+	res, err := a.client.Workflows.List(ctx, params)
+	if err != nil {
+		return err
+	}
+	return a.print(res, func() table {
+		var rows []workflowRow
+		for _, w := range res.Workflows {
+			rows = append(rows, workflowRow{w.ID, w.Name, w.Publish, w.Logging, w.CreatedAt})
+		}
+		return workflowTable(rows...)
+	})
+}
+
+func (a *app) selectWorkflows(ctx context.Context, selector, name string) error {
+	sel, err := workflows.ParseSelector(selector)
+	if err != nil {
+		return err
+	}
+	wfs, err := a.client.SelectWorkflows(ctx, sel)
+	if err != nil {
+		return err
+	}
+	if name != "" {
+		wfs = slices.DeleteFunc(wfs, func(w v1.ListWorkflowOKWorkflowsItem) bool { return !strings.Contains(w.Name, name) })
+	}
+	return a.print(wfs, func() table {
+		var rows []workflowRow
+		for _, w := range wfs {
+			rows = append(rows, workflowRow{w.ID, w.Name, w.Publish, w.Logging, w.CreatedAt})
+		}
+		return workflowTable(rows...)
+	})
+}
+
+func getWorkflow(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	pos, err := parse(a, fs, "workflows get WORKFLOW_ID", args, 1)
+	if err != nil {
+		return err
+	}
+	w, err := a.client.Workflows.Read(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return a.print(w, func() table {
+		return workflowTable(workflowRow{w.ID, w.Name, w.Publish, w.Logging, w.CreatedAt})
+	})
+}
+
+func updateWorkflow(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	name := fs.String("name", "", "new name")
+	description := fs.String("description", "", "new description")
+	publish := fs.Bool("publish", false, "publish or unpublish the workflow")
+	logging := fs.Bool("logging", false, "enable or disable logging")
+	concurrencyMode := fs.String("concurrency-mode", "", "parallel, lock or queue")
+	clearTags := fs.Bool("clear-tags", false, "remove all tags")
+	var tags stringList
+	fs.Var(&tags, "tag", "replace the tags, can be repeated")
+	pos, err := parse(a, fs, "workflows update WORKFLOW_ID [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+
+	// only the flags given on the command line are sent
+	req := v1.UpdateWorkflowReq{}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			req.Name = v1.NewOptString(*name)
+		case "description":
+			req.Description = v1.NewOptString(*description)
+		case "publish":
+			req.Publish = v1.NewOptBool(*publish)
+		case "logging":
+			req.Logging = v1.NewOptBool(*logging)
+		case "concurrency-mode":
+			req.ConcurrencyMode = v1.NewOptUpdateWorkflowReqConcurrencyMode(v1.UpdateWorkflowReqConcurrencyMode(*concurrencyMode))
+		}
+	})
+	if len(tags) > 0 || *clearTags {
+		req.Tags = []v1.UpdateWorkflowReqTagsItem{}
+		for _, tag := range tags {
+			req.Tags = append(req.Tags, v1.UpdateWorkflowReqTagsItem{Name: tag})
+		}
+	}
+
+	w, err := a.client.Workflows.Update(ctx, pos[0], req)
+	if err != nil {
+		return err
+	}
+	return a.print(w, func() table {
+		return workflowTable(workflowRow{w.ID, w.Name, w.Publish, w.Logging, w.CreatedAt})
+	})
+}
+
+func tagWorkflow(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("tags", flag.ContinueOnError)
+	var add, remove, set stringList
+	fs.Var(&add, "add", "tag to add, can be repeated")
+	fs.Var(&remove, "remove", "tag to remove, can be repeated")
+	fs.Var(&set, "set", "replace the tags, can be repeated")
+	pos, err := parse(a, fs, "workflows tags WORKFLOW_ID [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+	if len(set) > 0 && (len(add) > 0 || len(remove) > 0) {
+		fmt.Fprintln(a.stderr, "--set cannot be combined with --add or --remove")
+		return errUsage
+	}
+
+	var tags []string
+	switch {
+	case len(set) > 0:
+		tags, err = a.client.SetTags(ctx, pos[0], set...)
+	case len(add) > 0 || len(remove) > 0:
+		if len(add) > 0 {
+			if tags, err = a.client.AddTags(ctx, pos[0], add...); err != nil {
+				return err
+			}
+		}
+		if len(remove) > 0 {
+			tags, err = a.client.RemoveTags(ctx, pos[0], remove...)
+		}
+	default:
+		var w *v1.GetWorkflowOKWorkflow
+		if w, err = a.client.Workflows.Read(ctx, pos[0]); err == nil {
+			for _, tag := range w.Tags {
+				tags = append(tags, tag.Name)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	return a.print(tags, func() table {
+		t := table{header: []string{"TAG"}}
+		for _, tag := range tags {
+			t.rows = append(t.rows, []string{tag})
+		}
+		return t
+	})
+}
+
+func deleteWorkflow(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	var opts workflows.DeleteOptions
+	cascade := fs.Bool("cascade", false, "same as --cancel-running --purge --remove-aliases")
+	fs.BoolVar(&opts.CancelRunning, "cancel-running", false, "cancel the active executions and wait for them to finish")
+	fs.BoolVar(&opts.PurgeExecutions, "purge", false, "delete the execution records")
+	fs.BoolVar(&opts.RemoveAliases, "remove-aliases", false, "remove the revision aliases")
+	fs.DurationVar(&opts.Timeout, "timeout", workflows.DefaultDeleteTimeout, "how long to wait for the canceled executions")
+	pos, err := parse(a, fs, "workflows delete WORKFLOW_ID [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+	if *cascade {
+		opts.CancelRunning, opts.PurgeExecutions, opts.RemoveAliases = true, true, true
+	}
+	if !opts.CancelRunning && !opts.PurgeExecutions && !opts.RemoveAliases {
+		if err := a.client.Workflows.Delete(ctx, pos[0]); err != nil {
+			return err
+		}
+		return a.done("workflow %s deleted", pos[0])
+	}
+
+	report, err := a.client.DeleteWorkflow(ctx, pos[0], opts)
+	for _, s := range report.Stages {
+		fmt.Fprintf(a.stderr, "%s: %s (%s)\n", s.Stage, s.Summary, s.Elapsed.Round(time.Millisecond))
+	}
+	if err != nil {
+		return err
+	}
+	return a.done("workflow %s deleted", pos[0])
+}
+
+func suggestWorkflows(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("suggest", flag.ContinueOnError)
+	var p pagination
+	p.register(fs)
+	pos, err := parse(a, fs, "workflows suggest NAME", args, 1)
+	if err != nil {
+		return err
+	}
+
+	params := v1.ListWorkflowSuggestParams{Name: pos[0]}
+	params.Page, params.PageLimit = p.params()
+	res, err := a.client.Workflows.ListSuggest(ctx, params)
+	if err != nil {
+		return err
+	}
+	return a.print(res, func() table {
+		t := table{header: []string{"NAME", "COUNT"}}
+		for _, s := range res.Suggests {
+			t.rows = append(t.rows, []string{s.Name, strconv.Itoa(s.Count)})
+		}
+		return t
+	})
+}
+
+// workflowRow 各種ワークフロー型の表示に共通する項目
+type workflowRow struct {
+	id, name         string
+	publish, logging bool
+	createdAt        time.Time
+}
+
+func workflowTable(rows ...workflowRow) table {
+	t := table{header: []string{"ID", "NAME", "PUBLISH", "LOGGING", "CREATED"}}
+	for _, r := range rows {
+		t.rows = append(t.rows, []string{r.id, r.name, strconv.FormatBool(r.publish), strconv.FormatBool(r.logging), formatTime(r.createdAt)})
+	}
+	return t
+}
+
+func exportWorkflows(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	file := fs.String("file", "-", "file to write the bundle to, - for the standard output")
+	executions := fs.Int("executions", 0, "number of recent executions to include")
+	ids, err := parse(a, fs, "workflows export WORKFLOW_ID... [flags]", args, -1)
+	if err != nil {
+		return err
+	}
+	bundle, err := a.client.ExportWithOptions(ctx, workflows.ExportOptions{Executions: *executions}, ids...)
+	if err != nil {
+		return err
+	}
+	if *file == "-" {
+		_, err := bundle.WriteTo(a.stdout)
+		return err
+	}
+	return writeFile(*file, func(w io.Writer) error {
+		_, err := bundle.WriteTo(w)
+		return err
+	})
+}
+
+func importWorkflows(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	conflict := fs.String("conflict", "", "what to do with a workflow of the same name: skip, overwrite or rename (default: fail)")
+	servicePrincipalID := fs.String("service-principal-id", "", "service principal to set on the imported workflows")
+	pos, err := parse(a, fs, "workflows import FILE [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+	policy, err := a.conflictPolicy(*conflict)
+	if err != nil {
+		return err
+	}
+	data, err := a.readFile(pos[0])
+	if err != nil {
+		return err
+	}
+	bundle, err := workflows.ReadBundle(strings.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	opts := workflows.ImportOptions{Conflict: policy}
+	if *servicePrincipalID != "" {
+		if opts.ServicePrincipalID, err = workflows.ParseServicePrincipalID(*servicePrincipalID); err != nil {
+			return err
+		}
+	}
+	result, err := a.client.Import(ctx, bundle, opts)
+	if result != nil && len(result.Workflows) > 0 {
+		perr := a.print(result.Workflows, func() table {
+			t := table{header: []string{"SOURCE ID", "ID", "NAME", "ACTION", "REVISIONS"}}
+			for _, w := range result.Workflows {
+				t.rows = append(t.rows, []string{w.SourceID, w.ID, w.Name, string(w.Action), strconv.Itoa(len(w.Revisions))})
+			}
+			return t
+		})
+		if err == nil {
+			err = perr
+		}
+	}
+	return err
+}
+
+func cloneWorkflow(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("clone", flag.ContinueOnError)
+	var target workflows.Target
+	var opts workflows.CloneOptions
+	var tags stringList
+	fs.StringVar(&target.Name, "name", "", "name of the copy (default: the same name, only with --to-zone)")
+	toZone := fs.String("to-zone", "", "zone to copy the workflow to")
+	fs.StringVar(&opts.Alias, "alias", "", "copy only the revision with this alias")
+	fs.BoolVar(&opts.DropServicePrincipal, "drop-service-principal", false, "do not copy the service principal")
+	fs.Var(&tags, "tag", "tag of the copy instead of the original ones, can be repeated")
+	publish := fs.Bool("publish", false, "publish the copy (default: same as the original)")
+	conflict := fs.String("conflict", "", "what to do with a workflow of the same name: skip, overwrite or rename (default: fail)")
+	pos, err := parse(a, fs, "workflows clone WORKFLOW_ID [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "tag":
+			opts.Tags = tags
+		case "publish":
+			opts.Publish = publish
+		}
+	})
+	if opts.Conflict, err = a.conflictPolicy(*conflict); err != nil {
+		return err
+	}
+	if *toZone != "" {
+		target.Client, err = workflows.New(append(slices.Clone(a.options), workflows.WithZone(*toZone))...)
+		if err != nil {
+			return err
+		}
+	}
+
+	cloned, err := a.client.CloneWorkflow(ctx, pos[0], target, opts)
+	if err != nil {
+		return err
+	}
+	return a.done("workflow %s cloned as %s (%s, %d revisions)", pos[0], cloned.ID, cloned.Name, len(cloned.Revisions))
+}
+
+func (a *app) conflictPolicy(s string) (workflows.ConflictPolicy, error) {
+	switch policy := workflows.ConflictPolicy(s); policy {
+	case workflows.ConflictFail, workflows.ConflictSkip, workflows.ConflictOverwrite, workflows.ConflictRename:
+		return policy, nil
+	default:
+		fmt.Fprintf(a.stderr, "unknown conflict policy %q\n", s)
+		return "", errUsage
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// ErrConcurrentModification UpdateIfUnchangedが、期待した時点以降の他者による変更を検出したか、再試行しても更新の競合を避けられなかったことを表す
+var ErrConcurrentModification = errors.New("workflow was modified concurrently")
+
+// DefaultUpdateRetries UpdateIfUnchangedが競合を検出した際に再試行する既定の回数
+const DefaultUpdateRetries = 3
+
+// DefaultUpdateBackoff UpdateIfUnchangedが再試行する前に待つ既定の時間。試行ごとに倍になる
+const DefaultUpdateBackoff = 200 * time.Millisecond
+
+// UpdateOptions UpdateIfUnchangedの設定
+type UpdateOptions struct {
+	// Retries 競合を検出した際に再試行する回数。0の場合はDefaultUpdateRetries、負の場合は再試行しない
+	Retries int
+	// Backoff 再試行する前に待つ時間。0の場合はDefaultUpdateBackoff
+	Backoff time.Duration
+}
+
+// MutateFunc 最新のワークフローを元に更新リクエストを組み立てる関数
+//
+// 競合した場合は新しい状態で再び呼ばれるため、wfだけから結果が決まるようにする。
+// reqを空のままにすると何も送らない。エラーを返すと更新を中止し、そのエラーを返す。
+type MutateFunc func(wf *v1.GetWorkflowOKWorkflow, req *v1.UpdateWorkflowReq) error
+
+// noCacheKey WithoutCacheがコンテキストに設定する値のキー
+type noCacheKey struct{}
+
+// WithoutCache returns a context telling caching layers wrapping the APIs, such as the cache package,
+// to read from the API instead of serving what they hold. UpdateIfUnchanged reads through such a context,
+// so that it compares against the current state and not against one cached for a while.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// CacheBypassed reports whether ctx was made by WithoutCache
+func CacheBypassed(ctx context.Context) bool {
+	bypassed, _ := ctx.Value(noCacheKey{}).(bool)
+	return bypassed
+}
+
+// UpdateIfUnchanged updates the workflow only if nobody else has since expectedUpdatedAt.
+// See UpdateIfUnchangedWithOptions.
+func (c *Client) UpdateIfUnchanged(ctx context.Context, id string, expectedUpdatedAt time.Time, mutate MutateFunc) (*v1.UpdateWorkflowOKWorkflow, error) {
+	return c.UpdateIfUnchangedWithOptions(ctx, id, expectedUpdatedAt, UpdateOptions{}, mutate)
+}
+
+// UpdateIfUnchangedWithOptions reads the workflow, checks that its UpdatedAt is expectedUpdatedAt, builds the
+// request with mutate from what was read, reads again right before sending and sends the request only if
+// UpdatedAt has not moved in between.
+//
+// If the workflow is not at expectedUpdatedAt, it fails with ErrConcurrentModification right away, as
+// somebody else changed it after the caller looked at it. A zero expectedUpdatedAt skips this check;
+// a change detected between the two reads is then retried from the new state after a backoff, up to
+// opts.Retries times, so that mutate always sees the state its request is applied to. With a non-zero
+// expectedUpdatedAt such a change fails with ErrConcurrentModification too. The API has no conditional
+// update, so a change landing between the last read and the update is still not detected.
+//
+// If mutate leaves the request empty nothing is sent and the workflow as read is returned.
+func (c *Client) UpdateIfUnchangedWithOptions(ctx context.Context, id string, expectedUpdatedAt time.Time, opts UpdateOptions, mutate MutateFunc) (*v1.UpdateWorkflowOKWorkflow, error) {
+	retries := opts.Retries
+	if retries == 0 {
+		retries = DefaultUpdateRetries
+	}
+	backoff := opts.Backoff
+	if backoff <= 0 {
+		backoff = DefaultUpdateBackoff
+	}
+
+	read := WithoutCache(ctx)
+	for attempt := 0; ; attempt++ {
+		wf, err := c.Workflows.Read(read, id)
+		if err != nil {
+			return nil, err
+		}
+		if !expectedUpdatedAt.IsZero() && !wf.UpdatedAt.Equal(expectedUpdatedAt) {
+			return nil, modifiedError(id, wf.UpdatedAt, expectedUpdatedAt)
+		}
+		var req v1.UpdateWorkflowReq
+		if err := mutate(wf, &req); err != nil {
+			return nil, err
+		}
+		if isEmptyUpdate(&req) {
+			return convertGenerated[v1.UpdateWorkflowOKWorkflow](wf)
+		}
+
+		check, err := c.Workflows.Read(read, id)
+		if err != nil {
+			return nil, err
+		}
+		if check.UpdatedAt.Equal(wf.UpdatedAt) {
+			return c.Workflows.Update(ctx, id, req)
+		}
+		if !expectedUpdatedAt.IsZero() {
+			return nil, modifiedError(id, check.UpdatedAt, expectedUpdatedAt)
+		}
+
+		if attempt >= retries {
+			return nil, NewError(fmt.Sprintf("unable to update workflow %s", id),
+				fmt.Errorf("%w: gave up after %d attempts", ErrConcurrentModification, attempt+1))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff << attempt):
+		}
+	}
+}
+
+// modifiedError reports that the workflow is no longer at the UpdatedAt the caller expected
+func modifiedError(id string, updatedAt, expected time.Time) error {
+	return NewError(fmt.Sprintf("unable to update workflow %s", id),
+		fmt.Errorf("%w: updated at %s, expected %s", ErrConcurrentModification, updatedAt.Format(time.RFC3339), expected.Format(time.RFC3339)))
+}
+
+// isEmptyUpdate reports whether the request changes nothing
+func isEmptyUpdate(req *v1.UpdateWorkflowReq) bool {
+	return !req.Name.Set && !req.Description.Set && !req.Publish.Set && !req.Logging.Set &&
+		req.Tags == nil && !req.ConcurrencyMode.Set
+}
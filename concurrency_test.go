@@ -0,0 +1,105 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_UpdateIfUnchanged(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Minute)
+	appendDescription := func(wf *v1.GetWorkflowOKWorkflow, req *v1.UpdateWorkflowReq) error {
+		req.Description = v1.NewOptString(wf.Description.Or("") + "+")
+		return nil
+	}
+	opts := workflows.UpdateOptions{Backoff: time.Millisecond}
+
+	t.Run("unchanged", func(t *testing.T) {
+		wfs := workflowsmock.NewWorkflowAPI(t)
+		wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Description: "a", UpdatedAt: t0}.Get(), nil).Twice()
+		wfs.OnUpdate(mock.Anything, "wf", v1.UpdateWorkflowReq{Description: v1.NewOptString("a+")}).Return(&v1.UpdateWorkflowOKWorkflow{ID: "wf"}, nil).Once()
+
+		client := &workflows.Client{Workflows: wfs}
+		_, err := client.UpdateIfUnchangedWithOptions(t.Context(), "wf", t0, opts, appendDescription)
+		require.NoError(t, err)
+	})
+
+	t.Run("stale expectation", func(t *testing.T) {
+		// somebody changed the description after the caller read it; it must not be overwritten
+		wfs := workflowsmock.NewWorkflowAPI(t)
+		wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Description: "b", UpdatedAt: t1}.Get(), nil).Once()
+
+		client := &workflows.Client{Workflows: wfs}
+		_, err := client.UpdateIfUnchangedWithOptions(t.Context(), "wf", t0, opts, appendDescription)
+		require.ErrorIs(t, err, workflows.ErrConcurrentModification)
+		assert.EqualError(t, err, "workflows: unable to update workflow wf: workflow was modified concurrently: updated at 2025-01-01T00:01:00Z, expected 2025-01-01T00:00:00Z")
+	})
+
+	t.Run("changed before the update", func(t *testing.T) {
+		wfs := workflowsmock.NewWorkflowAPI(t)
+		wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Description: "a", UpdatedAt: t0}.Get(), nil).Once()
+		wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Description: "b", UpdatedAt: t1}.Get(), nil).Once()
+
+		client := &workflows.Client{Workflows: wfs}
+		_, err := client.UpdateIfUnchangedWithOptions(t.Context(), "wf", t0, opts, appendDescription)
+		require.ErrorIs(t, err, workflows.ErrConcurrentModification)
+	})
+
+	t.Run("retries without expectation", func(t *testing.T) {
+		wfs := workflowsmock.NewWorkflowAPI(t)
+		wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Description: "a", UpdatedAt: t0}.Get(), nil).Once()
+		wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Description: "b", UpdatedAt: t1}.Get(), nil).Times(3)
+		wfs.OnUpdate(mock.Anything, "wf", v1.UpdateWorkflowReq{Description: v1.NewOptString("b+")}).Return(&v1.UpdateWorkflowOKWorkflow{ID: "wf"}, nil).Once()
+
+		client := &workflows.Client{Workflows: wfs}
+		_, err := client.UpdateIfUnchangedWithOptions(t.Context(), "wf", time.Time{}, opts, appendDescription)
+		require.NoError(t, err)
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		var n int
+		wfs := workflowsmock.NewWorkflowAPI(t)
+		wfs.OnRead(mock.Anything, "wf").RunAndReturn(func(_ context.Context, _ string) (*v1.GetWorkflowOKWorkflow, error) {
+			n++
+			return workflowsmock.Workflow{ID: "wf", UpdatedAt: t0.Add(time.Duration(n) * time.Second)}.Get(), nil
+		})
+
+		client := &workflows.Client{Workflows: wfs}
+		_, err := client.UpdateIfUnchangedWithOptions(t.Context(), "wf", time.Time{}, workflows.UpdateOptions{Retries: 2, Backoff: time.Millisecond}, appendDescription)
+		require.ErrorIs(t, err, workflows.ErrConcurrentModification)
+		assert.EqualError(t, err, "workflows: unable to update workflow wf: workflow was modified concurrently: gave up after 3 attempts")
+		assert.Equal(t, 6, n, "read and re-read on each attempt")
+	})
+
+	t.Run("nothing to change", func(t *testing.T) {
+		wfs := workflowsmock.NewWorkflowAPI(t)
+		wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Name: "name", UpdatedAt: t0}.Get(), nil).Once()
+
+		client := &workflows.Client{Workflows: wfs}
+		wf, err := client.UpdateIfUnchanged(t.Context(), "wf", t0, func(*v1.GetWorkflowOKWorkflow, *v1.UpdateWorkflowReq) error { return nil })
+		require.NoError(t, err)
+		assert.Equal(t, "name", wf.Name)
+	})
+}
@@ -0,0 +1,334 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/timeline"
+)
+
+// Diagnosis 失敗した実行の調査結果
+type Diagnosis struct {
+	WorkflowID  string
+	ExecutionID string
+	Status      v1.GetExecutionOKExecutionStatus
+	Revision    int
+	// Failed 実行が失敗しているか。falseの場合、以降のフィールドは空
+	Failed   bool
+	FailedAt time.Time
+	// StepPath 失敗したステップと、それを囲むステップの名前。外側から順に並ぶ
+	StepPath []string
+	// Function 失敗した関数呼び出しの名前
+	Function string
+	// ErrorCode "E-1001"のようなエラーコード。見つからない場合は空
+	ErrorCode    string
+	ErrorMessage string
+	StackTrace   string
+	// Variables 失敗までに記録された最後の変数のスナップショット
+	Variables any
+	// Source 失敗したステップのRunbook上の記述
+	Source []SourceLine
+	// SourceError リビジョンを読めずSourceが空の場合、その原因
+	SourceError error
+}
+
+// SourceLine Runbookの1行
+type SourceLine struct {
+	Number int
+	Text   string
+}
+
+// DiagnoseExecution collects what is needed to investigate a failed execution: the failing step
+// path, the error code and message, the last variables and the Runbook lines of the step.
+// The Runbook is read from the revision the execution ran. If it cannot be read, the report is still
+// returned with Source empty and the error in SourceError.
+//
+// An execution that has not failed yields a Diagnosis whose Failed is false.
+func (c *Client) DiagnoseExecution(ctx context.Context, workflowID, executionID string) (*Diagnosis, error) {
+	exec, err := c.Executions.Read(ctx, workflowID, executionID)
+	if err != nil {
+		return nil, err
+	}
+	d := &Diagnosis{
+		WorkflowID:  workflowID,
+		ExecutionID: executionID,
+		Status:      exec.Status,
+		Revision:    exec.Revision,
+	}
+	if exec.Status != v1.GetExecutionOKExecutionStatusFailed {
+		return d, nil
+	}
+	d.Failed = true
+	d.FailedAt = exec.UpdatedAt
+	d.ErrorCode, d.ErrorMessage = decodeExecutionError(exec.Error)
+
+	var events []v1.ListExecutionHistoryOKHistoriesItem
+	for ev, err := range StreamHistory(ctx, c.Executions, workflowID, executionID, StreamOptions{}) {
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	tl := timeline.Build(events)
+
+	failure, ok := failureEvent(events)
+	if ok {
+		d.FailedAt = failure.CreatedAt
+		d.StackTrace = failure.StackTrace
+		d.StepPath, d.Function = failurePath(tl, failure)
+		d.Variables = lastVariables(events, failure)
+		if d.ErrorCode == "" && d.ErrorMessage == "" {
+			d.ErrorCode, d.ErrorMessage = decodeExecutionError(failure.Meta)
+		}
+	}
+
+	if exec.Revision > 0 && len(d.StepPath) > 0 {
+		rev, err := c.Revisions.Read(ctx, workflowID, exec.Revision)
+		if err != nil {
+			d.SourceError = err
+			return d, nil
+		}
+		d.Source = runbookSource(rev.Runbook, d.StepPath[len(d.StepPath)-1], d.StackTrace)
+	}
+	return d, nil
+}
+
+// failureEvent returns the last functionDidFailed event, or the workflowDidFailed event if no function failed
+func failureEvent(events []v1.ListExecutionHistoryOKHistoriesItem) (v1.ListExecutionHistoryOKHistoriesItem, bool) {
+	var found v1.ListExecutionHistoryOKHistoriesItem
+	var ok bool
+	for _, ev := range events {
+		switch ev.Type {
+		case v1.ListExecutionHistoryOKHistoriesItemTypeFunctionDidFailed:
+			if !ok || found.Type != ev.Type || ev.CreatedAt.After(found.CreatedAt) {
+				found, ok = ev, true
+			}
+		case v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidFailed:
+			if !ok {
+				found, ok = ev, true
+			}
+		}
+	}
+	return found, ok
+}
+
+// failurePath returns the names of the steps enclosing the failure, outermost first, and the failed function
+func failurePath(tl *timeline.Timeline, failure v1.ListExecutionHistoryOKHistoriesItem) ([]string, string) {
+	leaf := -1
+	for _, s := range tl.Thread(failure.ThreadId) {
+		failedHere := s.Failed && s.End.Equal(failure.CreatedAt)
+		// a step the failure interrupted never gets its end event
+		if failedHere || (s.Open && !s.Start.After(failure.CreatedAt)) {
+			if leaf < 0 || s.Depth >= tl.Spans[leaf].Depth {
+				leaf = s.ID
+			}
+		}
+	}
+
+	var path []string
+	var function string
+	for id := leaf; id >= 0; id = tl.Spans[id].Parent {
+		switch s := tl.Spans[id]; s.Kind {
+		case timeline.KindStep:
+			path = append([]string{s.Name}, path...)
+		case timeline.KindFunction:
+			if function == "" {
+				function = s.Name
+			}
+		}
+	}
+	return path, function
+}
+
+// lastVariables returns the variables of the last event recorded up to the failure, preferring its thread
+func lastVariables(events []v1.ListExecutionHistoryOKHistoriesItem, failure v1.ListExecutionHistoryOKHistoriesItem) any {
+	var sameThread, anyThread string
+	for _, ev := range events {
+		if ev.CreatedAt.After(failure.CreatedAt) || ev.Variables == "" {
+			continue
+		}
+		anyThread = ev.Variables
+		if ev.ThreadId == failure.ThreadId {
+			sameThread = ev.Variables
+		}
+	}
+	if sameThread != "" {
+		return DecodeHistoryField(sameThread)
+	}
+	return DecodeHistoryField(anyThread)
+}
+
+var errorCodePattern = regexp.MustCompile(`^\s*([A-Z]-\d{4})\s*:?\s*(.*)$`)
+
+// decodeExecutionError splits an error such as "E-1001 request to https://example.com timed out" into its code and message.
+// A JSON object with code and message keys is also accepted.
+func decodeExecutionError(s string) (code, message string) {
+	s = strings.TrimSpace(s)
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(s), &obj); err == nil {
+		for _, key := range []string{"code", "Code"} {
+			if v, ok := obj[key]; ok {
+				code = fmt.Sprint(v)
+			}
+		}
+		for _, key := range []string{"message", "Message", "error", "Error"} {
+			if v, ok := obj[key].(string); ok {
+				message = v
+			}
+		}
+		if code != "" || message != "" {
+			return code, message
+		}
+	}
+	if m := errorCodePattern.FindStringSubmatch(s); m != nil {
+		return m[1], m[2]
+	}
+	return "", s
+}
+
+var stackLinePattern = regexp.MustCompile(`(?i)\bline[ :]*(\d+)`)
+
+// sourceContext 行番号しかわからない場合に前後に含める行数
+const sourceContext = 3
+
+// runbookSource returns the block of the Runbook defining the step, i.e. the line with the step
+// name as a key and the lines indented under it. If the step is not found, the lines around the
+// line number mentioned in the stack trace are returned instead.
+func runbookSource(runbook, step, stackTrace string) []SourceLine {
+	lines := strings.Split(strings.TrimRight(runbook, "\n"), "\n")
+	key := regexp.MustCompile(`^(\s*)(?:-\s*)?["']?` + regexp.QuoteMeta(step) + `["']?\s*:`)
+
+	for i, line := range lines {
+		m := key.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		indent := len(m[1])
+		end := i + 1
+		for end < len(lines) {
+			trimmed := strings.TrimLeft(lines[end], " \t")
+			if trimmed != "" && len(lines[end])-len(trimmed) <= indent {
+				break
+			}
+			end++
+		}
+		return numbered(lines, i, end)
+	}
+
+	if m := stackLinePattern.FindStringSubmatch(stackTrace); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err == nil && n >= 1 && n <= len(lines) {
+			return numbered(lines, max(0, n-1-sourceContext), min(len(lines), n+sourceContext))
+		}
+	}
+	return nil
+}
+
+func numbered(lines []string, from, to int) []SourceLine {
+	var ret []SourceLine
+	for i := from; i < to; i++ {
+		ret = append(ret, SourceLine{Number: i + 1, Text: lines[i]})
+	}
+	return ret
+}
+
+// Text renders the diagnosis as plain text
+func (d *Diagnosis) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Execution %s of workflow %s (revision %d): %s\n", d.ExecutionID, d.WorkflowID, d.Revision, d.Status)
+	if !d.Failed {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Failed at: %s\n", d.FailedAt.Format(time.RFC3339))
+	if len(d.StepPath) > 0 {
+		fmt.Fprintf(&b, "Step:      %s\n", strings.Join(d.StepPath, " > "))
+	}
+	if d.Function != "" {
+		fmt.Fprintf(&b, "Function:  %s\n", d.Function)
+	}
+	if d.ErrorCode != "" {
+		fmt.Fprintf(&b, "Code:      %s\n", d.ErrorCode)
+	}
+	fmt.Fprintf(&b, "Message:   %s\n", d.ErrorMessage)
+	if len(d.Source) > 0 {
+		b.WriteString("\nRunbook:\n")
+		for _, l := range d.Source {
+			fmt.Fprintf(&b, "%5d | %s\n", l.Number, l.Text)
+		}
+	} else if d.SourceError != nil {
+		fmt.Fprintf(&b, "\nRunbook:   unavailable (%s)\n", d.SourceError)
+	}
+	if d.Variables != nil {
+		fmt.Fprintf(&b, "\nVariables:\n%s\n", indentJSON(d.Variables))
+	}
+	if d.StackTrace != "" {
+		fmt.Fprintf(&b, "\nStack trace:\n%s\n", strings.TrimRight(d.StackTrace, "\n"))
+	}
+	return b.String()
+}
+
+// Markdown renders the diagnosis as Markdown, e.g. for an incident ticket
+func (d *Diagnosis) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Execution `%s`\n\n", d.ExecutionID)
+	fmt.Fprintf(&b, "| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Workflow | `%s` |\n| Revision | %d |\n| Status | %s |\n", d.WorkflowID, d.Revision, d.Status)
+	if !d.Failed {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "| Failed at | %s |\n", d.FailedAt.Format(time.RFC3339))
+	if len(d.StepPath) > 0 {
+		fmt.Fprintf(&b, "| Step | `%s` |\n", strings.Join(d.StepPath, " > "))
+	}
+	if d.Function != "" {
+		fmt.Fprintf(&b, "| Function | `%s` |\n", d.Function)
+	}
+	if d.ErrorCode != "" {
+		fmt.Fprintf(&b, "| Code | `%s` |\n", d.ErrorCode)
+	}
+	fmt.Fprintf(&b, "| Message | %s |\n", strings.ReplaceAll(d.ErrorMessage, "|", `\|`))
+	if len(d.Source) > 0 {
+		b.WriteString("\n### Runbook\n\n```yaml\n")
+		for _, l := range d.Source {
+			fmt.Fprintf(&b, "%5d | %s\n", l.Number, l.Text)
+		}
+		b.WriteString("```\n")
+	} else if d.SourceError != nil {
+		fmt.Fprintf(&b, "\n### Runbook\n\nUnavailable: %s\n", d.SourceError)
+	}
+	if d.Variables != nil {
+		fmt.Fprintf(&b, "\n### Variables\n\n```json\n%s\n```\n", indentJSON(d.Variables))
+	}
+	if d.StackTrace != "" {
+		fmt.Fprintf(&b, "\n### Stack trace\n\n```\n%s\n```\n", strings.TrimRight(d.StackTrace, "\n"))
+	}
+	return b.String()
+}
+
+func indentJSON(v any) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
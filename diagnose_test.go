@@ -0,0 +1,119 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testRunbook = `meta:
+  description: test
+args:
+  url:
+    type: string
+steps:
+  prepare:
+    assign: ok
+  fetch:
+    call: http.get
+    args:
+      url: ${args.url}
+    result: res
+  done:
+    return: ${res}
+`
+
+func TestClient_DiagnoseExecution(t *testing.T) {
+	at := func(typ v1.ListExecutionHistoryOKHistoriesItemType, sec int, meta, variables string) v1.ListExecutionHistoryOKHistoriesItem {
+		ev := historyEvent(typ, sec)
+		ev.JobId, ev.ThreadId, ev.Meta, ev.StackTrace, ev.Variables = "job", "main", meta, "-", variables
+		return ev
+	}
+	failed := at(v1.ListExecutionHistoryOKHistoriesItemTypeFunctionDidFailed, 3, `{"name":"http.get"}`, `{"url":"https://example.com"}`)
+	failed.StackTrace = "at steps.fetch (line 9)"
+	history := workflowsmock.History(
+		at(v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowWillStart, 0, `{}`, `{}`),
+		at(v1.ListExecutionHistoryOKHistoriesItemTypeStepWillExecute, 1, `{"name":"prepare"}`, `{}`),
+		at(v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted, 1, `{"name":"prepare"}`, `{"ok":true}`),
+		at(v1.ListExecutionHistoryOKHistoriesItemTypeStepWillExecute, 2, `{"name":"fetch"}`, `{"ok":true}`),
+		at(v1.ListExecutionHistoryOKHistoriesItemTypeFunctionWillCall, 2, `{"name":"http.get"}`, `{"ok":true}`),
+		failed,
+		at(v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidFailed, 4, `{}`, `{}`),
+	)
+
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnRead(mock.Anything, "wf", "exec").Return(workflowsmock.Execution{
+		ID:       "exec",
+		Status:   string(v1.GetExecutionOKExecutionStatusFailed),
+		Revision: 2,
+		Error:    "E-1001 request to https://example.com timed out",
+	}.Get(), nil)
+	executions.OnListHistory(mock.Anything, mock.Anything).Return(history, nil)
+	revisions := workflowsmock.NewRevisionAPI(t)
+	revisions.OnRead(mock.Anything, "wf", 2).Return(workflowsmock.Revision{ID: 2, WorkflowID: "wf", Runbook: testRunbook}.Get(), nil)
+
+	client := &workflows.Client{Executions: executions, Revisions: revisions}
+	d, err := client.DiagnoseExecution(t.Context(), "wf", "exec")
+	require.NoError(t, err)
+
+	assert.True(t, d.Failed)
+	assert.Equal(t, []string{"fetch"}, d.StepPath)
+	assert.Equal(t, "http.get", d.Function)
+	assert.Equal(t, "E-1001", d.ErrorCode)
+	assert.Equal(t, "request to https://example.com timed out", d.ErrorMessage)
+	assert.Equal(t, map[string]any{"url": "https://example.com"}, d.Variables)
+	require.Len(t, d.Source, 5)
+	assert.Equal(t, 9, d.Source[0].Number)
+	assert.Equal(t, "  fetch:", d.Source[0].Text)
+	assert.Equal(t, "    result: res", d.Source[4].Text)
+
+	text := d.Text()
+	assert.Contains(t, text, "Step:      fetch")
+	assert.Contains(t, text, "   10 |     call: http.get")
+	md := d.Markdown()
+	assert.Contains(t, md, "| Code | `E-1001` |")
+	assert.Contains(t, md, "```yaml\n    9 |   fetch:\n")
+
+	// the report survives a revision that cannot be read
+	failing := workflowsmock.NewRevisionAPI(t)
+	failing.OnRead(mock.Anything, "wf", 2).Return(nil, errors.New("revision gone"))
+	client.Revisions = failing
+	d, err = client.DiagnoseExecution(t.Context(), "wf", "exec")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fetch"}, d.StepPath)
+	assert.Equal(t, "E-1001", d.ErrorCode)
+	assert.Empty(t, d.Source)
+	require.EqualError(t, d.SourceError, "revision gone")
+	assert.Contains(t, d.Text(), "Runbook:   unavailable (revision gone)")
+}
+
+func TestClient_DiagnoseExecution_notFailed(t *testing.T) {
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnRead(mock.Anything, "wf", "exec").Return(workflowsmock.Execution{ID: "exec", Status: "Succeeded"}.Get(), nil)
+
+	client := &workflows.Client{Executions: executions}
+	d, err := client.DiagnoseExecution(t.Context(), "wf", "exec")
+	require.NoError(t, err)
+	assert.False(t, d.Failed)
+	assert.Contains(t, d.Text(), "Succeeded")
+}
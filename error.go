@@ -15,6 +15,9 @@
 package workflows
 
 import (
+	"errors"
+	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/sacloud/saclient-go"
@@ -23,6 +26,8 @@ import (
 type Error struct {
 	msg string
 	err error
+	// code APIが返したHTTPステータスコード。APIのエラーでなければ0
+	code int
 }
 
 func (e *Error) Unwrap() error { return e.err }
@@ -46,5 +51,75 @@ func (e *Error) Error() string {
 
 func NewError(msg string, err error) *Error { return &Error{msg: msg, err: err} }
 func NewAPIError(method string, code int, err error) *Error {
-	return NewError(method, saclient.NewError(code, "", err))
+	ret := NewError(method, saclient.NewError(code, "", err))
+	ret.code = code
+	return ret
+}
+
+// StatusCode returns the HTTP status code of the API error in the chain, or 0 if there is none
+func StatusCode(err error) int {
+	var e *Error
+	for errors.As(err, &e) {
+		if e.code != 0 {
+			return e.code
+		}
+		err = e.err
+	}
+	return 0
+}
+
+// IsConflictError reports whether the API rejected the request with 409 Conflict,
+// e.g. canceling an execution that has already finished
+func IsConflictError(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+// APIのエラーコードに対応するエラー。errors.Isで判定する
+var (
+	// ErrInvalidServicePrincipal サービスプリンシパルに問題がある(S-2003)
+	ErrInvalidServicePrincipal = errors.New("invalid service principal")
+	// ErrServicePrincipalNotAllowed APIトークンやサービスプリンシパルでの認証では、サービスプリンシパルを使うワークフローを作成できない(S-2004)
+	ErrServicePrincipalNotAllowed = errors.New("cannot create workflow with service principal using API token or service principal")
+	// ErrConcurrentExecutionsExceeded 最大同時実行数を超えている(Q-2002)
+	ErrConcurrentExecutionsExceeded = errors.New("the number of concurrent executions has exceeded")
+)
+
+var apiErrorCodes = map[error]string{
+	ErrInvalidServicePrincipal:      "S-2003",
+	ErrServicePrincipalNotAllowed:   "S-2004",
+	ErrConcurrentExecutionsExceeded: "Q-2002",
+}
+
+// Is reports whether the error is the API error of the code that target stands for,
+// e.g. errors.Is(err, ErrInvalidServicePrincipal) for S-2003
+func (e *Error) Is(target error) bool {
+	code, ok := apiErrorCodes[target]
+	return ok && e.apiErrorCode() == code
+}
+
+var apiErrorCodePattern = regexp.MustCompile(`^[A-Z]-\d{4}\b`)
+
+// apiErrorCode returns the code at the head of the message the API returned, or "" if e is not an API error
+func (e *Error) apiErrorCode() string {
+	if e.code == 0 || e.err == nil {
+		return ""
+	}
+	message := e.err
+	for next := errors.Unwrap(message); next != nil; next = errors.Unwrap(next) {
+		message = next
+	}
+	return apiErrorCodePattern.FindString(message.Error())
+}
+
+// ErrorCode returns the code such as "C-0050" that the API put at the head of the error message,
+// or "" if there is none
+func ErrorCode(err error) string {
+	var e *Error
+	for errors.As(err, &e) {
+		if e.code != 0 {
+			return e.apiErrorCode()
+		}
+		err = e.err
+	}
+	return ""
 }
@@ -16,6 +16,8 @@ package workflows
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/sacloud/saclient-go"
@@ -79,3 +81,38 @@ func TestNewAPIError(t *testing.T) {
 	assert.Equal("msg", err2.msg)
 	assert.False(saclient.IsNotFoundError(err2))
 }
+
+func TestErrorCode(t *testing.T) {
+	assert := require.New(t)
+
+	err := fmt.Errorf("cancel: %w", NewAPIError("Execution.Cancel", 409, errors.New("C-0050 This execution cannot be canceled.")))
+	assert.Equal(409, StatusCode(err))
+	assert.True(IsConflictError(err))
+	assert.Equal("C-0050", ErrorCode(err))
+
+	err2 := NewError("D-1234 not an API error", nil)
+	assert.Equal(0, StatusCode(err2))
+	assert.False(IsConflictError(err2))
+	assert.Empty(ErrorCode(err2))
+
+	// a code-like word that is not at the head of the API message is not a code
+	err3 := NewAPIError("Workflow.Update", 400, errors.New("Invalid runbook: see E-1001 in the manual."))
+	assert.Empty(ErrorCode(err3))
+	assert.NotErrorIs(NewAPIError("Workflow.Create", 400, errors.New("Rejected: S-2003")), ErrInvalidServicePrincipal)
+}
+
+func TestError_Is(t *testing.T) {
+	assert := require.New(t)
+
+	err := fmt.Errorf("create: %w", NewAPIError("Workflow.Create", 400, errors.New("S-2003 Invalid service principal.")))
+	assert.ErrorIs(err, ErrInvalidServicePrincipal)
+	assert.NotErrorIs(err, ErrServicePrincipalNotAllowed)
+
+	err = NewAPIError("Workflow.Create", 403, errors.New("S-2004 Cannot create workflow with service principal using API token or service principal."))
+	assert.ErrorIs(err, ErrServicePrincipalNotAllowed)
+
+	assert.NotErrorIs(NewError("S-2003 not an API error", nil), ErrInvalidServicePrincipal)
+
+	err = NewAPIError("Execution.Create", http.StatusPaymentRequired, errors.New("Q-2002 The number of concurrent executions has exceeded."))
+	assert.ErrorIs(err, ErrConcurrentExecutionsExceeded)
+}
@@ -16,7 +16,9 @@ package workflows
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
 	v1 "github.com/sacloud/workflows-api-go/apis/v1"
@@ -34,176 +36,239 @@ type ExecutionAPI interface {
 var _ ExecutionAPI = (*executionOp)(nil)
 
 type executionOp struct {
-	client *v1.Client
+	client       *v1.Client
+	interceptors []Interceptor
 }
 
 func NewExecutionOp(client *v1.Client) ExecutionAPI {
-	return &executionOp{client: client}
+	return NewExecutionOpWithConn(NewConn(client))
+}
+
+// NewExecutionOpWithConn creates a new ExecutionAPI sharing the interceptors installed on conn
+func NewExecutionOpWithConn(conn *Conn) ExecutionAPI {
+	return &executionOp{client: conn.client, interceptors: conn.interceptors}
 }
 
 func (op *executionOp) Create(ctx context.Context, workflowID string, req v1.OptCreateExecutionReq) (*v1.CreateExecutionCreatedExecution, error) {
 	const methodName = "Execution.Create"
 
-	res, err := op.client.CreateExecution(ctx, req, v1.CreateExecutionParams{ID: workflowID})
-	if err != nil {
-		return nil, NewAPIError(methodName, 0, err)
-	}
+	return intercept(ctx, op.interceptors, &Request{Operation: methodName, Body: &req, Params: &v1.CreateExecutionParams{ID: workflowID}}, func(ctx context.Context, rq *Request) (*v1.CreateExecutionCreatedExecution, error) {
+		body, err := requestBody[v1.OptCreateExecutionReq](rq)
+		if err != nil {
+			return nil, err
+		}
+		params, err := requestParams[v1.CreateExecutionParams](rq)
+		if err != nil {
+			return nil, err
+		}
+		res, err := op.client.CreateExecution(ctx, *body, *params)
+		if err != nil {
+			return nil, NewAPIError(methodName, 0, err)
+		}
 
-	switch r := res.(type) {
-	case *v1.CreateExecutionCreated:
-		return &r.Execution, nil
-	case *v1.CreateExecutionBadRequest:
-		return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.CreateExecutionUnauthorized:
-		return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.CreateExecutionForbidden:
-		return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.CreateExecutionNotFound:
-		return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.CreateExecutionConflict:
-		return nil, NewAPIError(methodName, http.StatusConflict, errors.New(r.Message))
-	case *v1.CreateExecutionInternalServerError:
-		return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return nil, NewAPIError(methodName, 0, err)
-	}
+		switch r := res.(type) {
+		case *v1.CreateExecutionCreated:
+			return &r.Execution, nil
+		case *v1.CreateExecutionBadRequest:
+			return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.CreateExecutionUnauthorized:
+			return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.CreateExecutionPaymentRequired:
+			return nil, NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
+		case *v1.CreateExecutionForbidden:
+			return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.CreateExecutionNotFound:
+			return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.CreateExecutionConflict:
+			return nil, NewAPIError(methodName, http.StatusConflict, errors.New(r.Message))
+		case *v1.CreateExecutionInternalServerError:
+			return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return nil, NewAPIError(methodName, 0, err)
+		}
+	})
 }
 
 func (op *executionOp) List(ctx context.Context, params v1.ListExecutionParams) (*v1.ListExecutionOK, error) {
 	const methodName = "Execution.List"
 
-	res, err := op.client.ListExecution(ctx, params)
-	if err != nil {
-		return nil, NewAPIError(methodName, 0, err)
-	}
+	return intercept(ctx, op.interceptors, &Request{Operation: methodName, Params: &params}, func(ctx context.Context, rq *Request) (*v1.ListExecutionOK, error) {
+		params, err := requestParams[v1.ListExecutionParams](rq)
+		if err != nil {
+			return nil, err
+		}
+		res, err := op.client.ListExecution(ctx, *params)
+		if err != nil {
+			return nil, NewAPIError(methodName, 0, err)
+		}
 
-	switch r := res.(type) {
-	case *v1.ListExecutionOK:
-		return r, nil
-	case *v1.ListExecutionBadRequest:
-		return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.ListExecutionUnauthorized:
-		return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.ListExecutionForbidden:
-		return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.ListExecutionNotFound:
-		return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.ListExecutionInternalServerError:
-		return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return nil, NewAPIError(methodName, 0, err)
-	}
+		switch r := res.(type) {
+		case *v1.ListExecutionOK:
+			return r, nil
+		case *v1.ListExecutionBadRequest:
+			return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.ListExecutionUnauthorized:
+			return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.ListExecutionForbidden:
+			return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.ListExecutionNotFound:
+			return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.ListExecutionInternalServerError:
+			return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return nil, NewAPIError(methodName, 0, err)
+		}
+	})
 }
 
 func (op *executionOp) Read(ctx context.Context, workflowID, executionID string) (*v1.GetExecutionOKExecution, error) {
 	const methodName = "Execution.Read"
 
-	res, err := op.client.GetExecution(ctx, v1.GetExecutionParams{
-		ID:          workflowID,
-		ExecutionId: executionID,
-	})
-	if err != nil {
-		return nil, NewAPIError(methodName, 0, err)
-	}
+	return intercept(ctx, op.interceptors, &Request{Operation: methodName, Params: &v1.GetExecutionParams{ID: workflowID, ExecutionId: executionID}}, func(ctx context.Context, rq *Request) (*v1.GetExecutionOKExecution, error) {
+		params, err := requestParams[v1.GetExecutionParams](rq)
+		if err != nil {
+			return nil, err
+		}
+		res, err := op.client.GetExecution(ctx, *params)
+		if err != nil {
+			return nil, NewAPIError(methodName, 0, err)
+		}
 
-	switch r := res.(type) {
-	case *v1.GetExecutionOK:
-		return &r.Execution, nil
-	case *v1.GetExecutionBadRequest:
-		return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.GetExecutionUnauthorized:
-		return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.GetExecutionForbidden:
-		return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.GetExecutionNotFound:
-		return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.GetExecutionInternalServerError:
-		return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return nil, NewAPIError(methodName, 0, err)
-	}
+		switch r := res.(type) {
+		case *v1.GetExecutionOK:
+			return &r.Execution, nil
+		case *v1.GetExecutionBadRequest:
+			return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.GetExecutionUnauthorized:
+			return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.GetExecutionForbidden:
+			return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.GetExecutionNotFound:
+			return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.GetExecutionInternalServerError:
+			return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return nil, NewAPIError(methodName, 0, err)
+		}
+	})
 }
 
 func (op *executionOp) Cancel(ctx context.Context, workflowID, executionID string) (*v1.CancelExecutionOKExecution, error) {
 	const methodName = "Execution.Cancel"
 
-	res, err := op.client.CancelExecution(ctx, v1.CancelExecutionParams{
-		ID:          workflowID,
-		ExecutionId: executionID,
-	})
-	if err != nil {
-		return nil, NewAPIError(methodName, 0, err)
-	}
+	return intercept(ctx, op.interceptors, &Request{Operation: methodName, Params: &v1.CancelExecutionParams{ID: workflowID, ExecutionId: executionID}}, func(ctx context.Context, rq *Request) (*v1.CancelExecutionOKExecution, error) {
+		params, err := requestParams[v1.CancelExecutionParams](rq)
+		if err != nil {
+			return nil, err
+		}
+		res, err := op.client.CancelExecution(ctx, *params)
+		if err != nil {
+			return nil, NewAPIError(methodName, 0, err)
+		}
 
-	switch r := res.(type) {
-	case *v1.CancelExecutionOK:
-		return &r.Execution, nil
-	case *v1.CancelExecutionBadRequest:
-		return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.CancelExecutionUnauthorized:
-		return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.CancelExecutionForbidden:
-		return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.CancelExecutionNotFound:
-		return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.CancelExecutionInternalServerError:
-		return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return nil, NewAPIError(methodName, 0, err)
-	}
+		switch r := res.(type) {
+		case *v1.CancelExecutionOK:
+			return &r.Execution, nil
+		case *v1.CancelExecutionAccepted:
+			return convertGenerated[v1.CancelExecutionOKExecution](&r.Execution)
+		case *v1.CancelExecutionBadRequest:
+			return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.CancelExecutionPaymentRequired:
+			return nil, NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
+		case *v1.CancelExecutionUnauthorized:
+			return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.CancelExecutionForbidden:
+			return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.CancelExecutionNotFound:
+			return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.CancelExecutionConflict:
+			return nil, NewAPIError(methodName, http.StatusConflict, errors.New(r.Message))
+		case *v1.CancelExecutionInternalServerError:
+			return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return nil, NewAPIError(methodName, 0, err)
+		}
+	})
 }
 
 func (op *executionOp) Delete(ctx context.Context, workflowID, executionID string) error {
 	const methodName = "Execution.Delete"
 
-	res, err := op.client.DeleteExecution(ctx, v1.DeleteExecutionParams{
-		ID:          workflowID,
-		ExecutionId: executionID,
-	})
-	if err != nil {
-		return NewAPIError(methodName, 0, err)
-	}
+	return interceptErr(ctx, op.interceptors, &Request{Operation: methodName, Params: &v1.DeleteExecutionParams{ID: workflowID, ExecutionId: executionID}}, func(ctx context.Context, rq *Request) error {
+		params, err := requestParams[v1.DeleteExecutionParams](rq)
+		if err != nil {
+			return err
+		}
+		res, err := op.client.DeleteExecution(ctx, *params)
+		if err != nil {
+			return NewAPIError(methodName, 0, err)
+		}
 
-	switch r := res.(type) {
-	case *v1.DeleteExecutionOK:
-		return nil
-	case *v1.DeleteExecutionBadRequest:
-		return NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.DeleteExecutionUnauthorized:
-		return NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.DeleteExecutionForbidden:
-		return NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.DeleteExecutionNotFound:
-		return NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.DeleteExecutionInternalServerError:
-		return NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return NewAPIError(methodName, 0, err)
-	}
+		switch r := res.(type) {
+		case *v1.DeleteExecutionOK:
+			return nil
+		case *v1.DeleteExecutionBadRequest:
+			return NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.DeleteExecutionPaymentRequired:
+			return NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
+		case *v1.DeleteExecutionUnauthorized:
+			return NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.DeleteExecutionForbidden:
+			return NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.DeleteExecutionNotFound:
+			return NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.DeleteExecutionConflict:
+			return NewAPIError(methodName, http.StatusConflict, errors.New(r.Message))
+		case *v1.DeleteExecutionInternalServerError:
+			return NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return NewAPIError(methodName, 0, err)
+		}
+	})
 }
 
 func (op *executionOp) ListHistory(ctx context.Context, params v1.ListExecutionHistoryParams) (*v1.ListExecutionHistoryOK, error) {
 	const methodName = "Execution.ListHistory"
 
-	res, err := op.client.ListExecutionHistory(ctx, params)
+	return intercept(ctx, op.interceptors, &Request{Operation: methodName, Params: &params}, func(ctx context.Context, rq *Request) (*v1.ListExecutionHistoryOK, error) {
+		params, err := requestParams[v1.ListExecutionHistoryParams](rq)
+		if err != nil {
+			return nil, err
+		}
+		res, err := op.client.ListExecutionHistory(ctx, *params)
+		if err != nil {
+			return nil, NewAPIError(methodName, 0, err)
+		}
+
+		switch r := res.(type) {
+		case *v1.ListExecutionHistoryOK:
+			return r, nil
+		case *v1.ListExecutionHistoryBadRequest:
+			return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.ListExecutionHistoryUnauthorized:
+			return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.ListExecutionHistoryForbidden:
+			return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.ListExecutionHistoryNotFound:
+			return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.ListExecutionHistoryInternalServerError:
+			return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return nil, NewAPIError(methodName, 0, err)
+		}
+	})
+}
+
+// convertGenerated copies a generated type into another one of the same shape,
+// e.g. an execution of CancelExecutionAccepted into CancelExecutionOKExecution
+func convertGenerated[T any](src json.Marshaler) (*T, error) {
+	data, err := src.MarshalJSON()
 	if err != nil {
-		return nil, NewAPIError(methodName, 0, err)
+		return nil, NewError(fmt.Sprintf("unable to convert %T", src), err)
 	}
-
-	switch r := res.(type) {
-	case *v1.ListExecutionHistoryOK:
-		return r, nil
-	case *v1.ListExecutionHistoryBadRequest:
-		return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.ListExecutionHistoryUnauthorized:
-		return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.ListExecutionHistoryForbidden:
-		return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.ListExecutionHistoryNotFound:
-		return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.ListExecutionHistoryInternalServerError:
-		return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return nil, NewAPIError(methodName, 0, err)
+	var ret T
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, NewError(fmt.Sprintf("unable to convert %T", src), err)
 	}
+	return &ret, nil
 }
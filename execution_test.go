@@ -18,8 +18,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/sacloud/packages-go/testutil"
-	"github.com/sacloud/saclient-go"
 	"github.com/sacloud/workflows-api-go"
 	v1 "github.com/sacloud/workflows-api-go/apis/v1"
 	"github.com/stretchr/testify/assert"
@@ -27,14 +25,9 @@ import (
 )
 
 func TestExecutionAPI(t *testing.T) {
-	testutil.PreCheckEnvsFunc("SAKURA_ACCESS_TOKEN", "SAKURA_ACCESS_TOKEN_SECRET")(t)
-
+	client := newAcceptanceClient(t)
 	ctx := t.Context()
 
-	var theClient saclient.Client
-	client, err := workflows.NewClient(&theClient)
-	require.NoError(t, err)
-
 	// setup
 	workflowAPI := workflows.NewWorkflowOp(client)
 	workflow, err := workflowAPI.Create(ctx, v1.CreateWorkflowReq{
@@ -0,0 +1,166 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// DefaultStreamInterval StreamHistoryがFollow時に履歴をポーリングする既定の間隔
+const DefaultStreamInterval = 2 * time.Second
+
+// defaultStreamPageLimit 1回のポーリングで取得する件数。APIの上限は500
+const defaultStreamPageLimit = 100
+
+// StreamOptions StreamHistoryの設定
+type StreamOptions struct {
+	// Follow trueの場合、実行が終了するまで新しいイベントを待ち続ける
+	Follow bool
+	// Interval Follow時のポーリング間隔。0の場合はDefaultStreamInterval
+	Interval time.Duration
+	// PageLimit 1回のリクエストで取得する件数。0の場合は100
+	PageLimit int
+}
+
+// StreamHistory yields the history events of an execution in chronological order.
+//
+// Without Follow it stops once every recorded event has been yielded. With Follow it polls
+// ListHistory until a terminal event (see IsTerminalEvent) arrives, like `kubectl logs -f`.
+// An event fetched twice by consecutive polls is yielded only once. Events are told apart by their
+// CreatedAt, Type, thread and step (the StackTrace and Meta), and only the keys from the earliest
+// CreatedAt of the last page on are kept, as older events cannot be fetched again.
+// The sequence ends after yielding an error, including the context's error on cancellation.
+func StreamHistory(ctx context.Context, api ExecutionAPI, workflowID, executionID string, opts StreamOptions) iter.Seq2[v1.ListExecutionHistoryOKHistoriesItem, error] {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultStreamInterval
+	}
+	limit := opts.PageLimit
+	if limit <= 0 {
+		limit = defaultStreamPageLimit
+	}
+
+	return func(yield func(v1.ListExecutionHistoryOKHistoriesItem, error) bool) {
+		var zero v1.ListExecutionHistoryOKHistoriesItem
+		seen := map[historyKey]struct{}{}
+		page := 1
+
+		for {
+			res, err := api.ListHistory(ctx, v1.ListExecutionHistoryParams{
+				ID:          workflowID,
+				ExecutionId: executionID,
+				Page:        v1.NewOptInt(page),
+				PageLimit:   v1.NewOptInt(limit),
+				SortOrder:   v1.NewOptListExecutionHistorySortOrder(v1.ListExecutionHistorySortOrderAsc),
+			})
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, ev := range res.Histories {
+				key := newHistoryKey(ev)
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+				if !yield(ev, nil) || IsTerminalEvent(ev.Type) {
+					return
+				}
+			}
+			if len(res.Histories) > 0 {
+				earliest := res.Histories[0].CreatedAt.UnixNano()
+				for _, ev := range res.Histories[1:] {
+					earliest = min(earliest, ev.CreatedAt.UnixNano())
+				}
+				for key := range seen {
+					if key.createdAt < earliest {
+						delete(seen, key)
+					}
+				}
+			}
+
+			// a full page means the next one may already have events
+			if len(res.Histories) >= limit {
+				page++
+				continue
+			}
+			if !opts.Follow {
+				return
+			}
+
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				yield(zero, ctx.Err())
+				return
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// historyKey 履歴イベントを識別するキー
+type historyKey struct {
+	createdAt int64
+	typ       v1.ListExecutionHistoryOKHistoriesItemType
+	jobID     string
+	threadID  string
+	// stackTraceとmetaでステップを区別する
+	stackTrace string
+	meta       string
+}
+
+func newHistoryKey(ev v1.ListExecutionHistoryOKHistoriesItem) historyKey {
+	return historyKey{
+		createdAt:  ev.CreatedAt.UnixNano(),
+		typ:        ev.Type,
+		jobID:      ev.JobId,
+		threadID:   ev.ThreadId,
+		stackTrace: ev.StackTrace,
+		meta:       ev.Meta,
+	}
+}
+
+// IsTerminalEvent reports whether the event type ends an execution
+func IsTerminalEvent(t v1.ListExecutionHistoryOKHistoriesItemType) bool {
+	switch t {
+	case v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCompleted,
+		v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidFailed,
+		v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// DecodeHistoryField decodes the Variables or Meta field of a history event, which the API
+// returns as a JSON encoded string. A string that is not JSON is returned as is.
+func DecodeHistoryField(s string) any {
+	if s == "" {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	return v
+}
@@ -0,0 +1,141 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func historyEvent(typ v1.ListExecutionHistoryOKHistoriesItemType, sec int) v1.ListExecutionHistoryOKHistoriesItem {
+	return v1.ListExecutionHistoryOKHistoriesItem{
+		WorkflowExecutionId: "exec",
+		Type:                typ,
+		CreatedAt:           time.Date(2025, 1, 1, 0, 0, sec, 0, time.UTC),
+		Variables:           "{}",
+	}
+}
+
+func TestStreamHistory_follow(t *testing.T) {
+	started := historyEvent(v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowWillStart, 0)
+	step := historyEvent(v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted, 1)
+	completed := historyEvent(v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCompleted, 2)
+
+	// each poll sees the events recorded so far
+	polls := [][]v1.ListExecutionHistoryOKHistoriesItem{
+		{started},
+		{started, step},
+		{started, step, completed},
+	}
+	api := workflowsmock.NewExecutionAPI(t)
+	api.OnListHistory(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, params v1.ListExecutionHistoryParams) (*v1.ListExecutionHistoryOK, error) {
+		assert.Equal(t, v1.ListExecutionHistorySortOrderAsc, params.SortOrder.Value)
+		assert.Equal(t, 1, params.Page.Value)
+		events := polls[0]
+		polls = polls[1:]
+		return workflowsmock.History(events...), nil
+	}).Times(3)
+
+	var got []v1.ListExecutionHistoryOKHistoriesItemType
+	for ev, err := range workflows.StreamHistory(t.Context(), api, "wf", "exec", workflows.StreamOptions{Follow: true, Interval: time.Millisecond}) {
+		require.NoError(t, err)
+		got = append(got, ev.Type)
+	}
+	assert.Equal(t, []v1.ListExecutionHistoryOKHistoriesItemType{started.Type, step.Type, completed.Type}, got)
+}
+
+func TestStreamHistory_dedup(t *testing.T) {
+	started := historyEvent(v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowWillStart, 0)
+	// the same step in two threads at the same time
+	main := historyEvent(v1.ListExecutionHistoryOKHistoriesItemTypeStepWillExecute, 1)
+	main.ThreadId, main.Meta = "main", `{"name":"fetch"}`
+	branch := main
+	branch.ThreadId = "branch"
+	// a later poll may return an event with other variables; it is still the same event
+	restarted := started
+	restarted.Variables = `{"a":1}`
+	completed := historyEvent(v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCompleted, 2)
+
+	polls := [][]v1.ListExecutionHistoryOKHistoriesItem{
+		{started, main, branch},
+		{restarted, main, branch, completed},
+	}
+	api := workflowsmock.NewExecutionAPI(t)
+	api.OnListHistory(mock.Anything, mock.Anything).RunAndReturn(func(context.Context, v1.ListExecutionHistoryParams) (*v1.ListExecutionHistoryOK, error) {
+		events := polls[0]
+		polls = polls[1:]
+		return workflowsmock.History(events...), nil
+	}).Times(2)
+
+	var got []string
+	for ev, err := range workflows.StreamHistory(t.Context(), api, "wf", "exec", workflows.StreamOptions{Follow: true, Interval: time.Millisecond}) {
+		require.NoError(t, err)
+		got = append(got, string(ev.Type)+"/"+ev.ThreadId)
+	}
+	assert.Equal(t, []string{
+		string(started.Type) + "/",
+		string(main.Type) + "/main",
+		string(branch.Type) + "/branch",
+		string(completed.Type) + "/",
+	}, got)
+}
+
+func TestStreamHistory_pages(t *testing.T) {
+	api := workflowsmock.NewExecutionAPI(t)
+	api.OnListHistory(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, params v1.ListExecutionHistoryParams) (*v1.ListExecutionHistoryOK, error) {
+		if params.Page.Value == 1 {
+			return workflowsmock.History(
+				historyEvent(v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowWillStart, 0),
+				historyEvent(v1.ListExecutionHistoryOKHistoriesItemTypeStepWillExecute, 1),
+			), nil
+		}
+		return workflowsmock.History(historyEvent(v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted, 2)), nil
+	}).Times(2)
+
+	var n int
+	for _, err := range workflows.StreamHistory(t.Context(), api, "wf", "exec", workflows.StreamOptions{PageLimit: 2}) {
+		require.NoError(t, err)
+		n++
+	}
+	assert.Equal(t, 3, n)
+}
+
+func TestStreamHistory_canceled(t *testing.T) {
+	api := workflowsmock.NewExecutionAPI(t)
+	api.OnListHistory(mock.Anything, mock.Anything).Return(workflowsmock.History(), nil)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	var last error
+	for _, err := range workflows.StreamHistory(ctx, api, "wf", "exec", workflows.StreamOptions{Follow: true, Interval: time.Millisecond}) {
+		last = err
+	}
+	require.ErrorIs(t, last, context.DeadlineExceeded)
+}
+
+func TestDecodeHistoryField(t *testing.T) {
+	assert.Equal(t, map[string]any{"a": float64(1)}, workflows.DecodeHistoryField(`{"a":1}`))
+	assert.Equal(t, "not json", workflows.DecodeHistoryField("not json"))
+	assert.Nil(t, workflows.DecodeHistoryField(""))
+}
@@ -0,0 +1,385 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package informer watches workflows and executions by polling their lists, as the Workflows API
+// has no watch endpoint.
+//
+// An Informer keeps the objects of the last list in a local store with indexes, compares each new
+// list with it and delivers Added, Updated and Deleted events with the old and new objects to the
+// handlers. Every resync interval the whole store is delivered again as Synced events, so that
+// handlers can repair what they missed. Handlers run on a bounded number of workers; the events
+// of one object are always handled in order by the same worker.
+//
+//	inf := informer.NewExecutionInformer(client, nil, informer.WithInterval(10*time.Second))
+//	inf.AddHandler(func(ctx context.Context, ev informer.Event[v1.ListExecutionOKExecutionsItem]) {
+//		if ev.Type == informer.Updated && ev.Old.Status != ev.New.Status {
+//			fmt.Println(ev.New.ExecutionId, ev.Old.Status, "->", ev.New.Status)
+//		}
+//	})
+//	err := inf.Run(ctx)
+package informer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+)
+
+// EventType イベントの種類
+type EventType string
+
+const (
+	// Added 前回の一覧になかったオブジェクトが現れた
+	Added EventType = "added"
+	// Updated オブジェクトの内容が前回の一覧から変わった
+	Updated EventType = "updated"
+	// Deleted 前回の一覧にあったオブジェクトがなくなった
+	Deleted EventType = "deleted"
+	// Synced 再同期のために変更の有無によらず送られる。OldとNewは同じオブジェクト
+	Synced EventType = "synced"
+)
+
+const (
+	// DefaultInterval 一覧を取得する既定の間隔
+	DefaultInterval = 30 * time.Second
+	// DefaultConcurrency ハンドラを実行する既定のワーカー数
+	DefaultConcurrency = 1
+)
+
+// Event オブジェクトの変更
+//
+// OldとNewはストアのオブジェクトを指すため、ハンドラは変更してはならない。
+type Event[T any] struct {
+	Type EventType
+	Key  string
+	// Old 変更前のオブジェクト。Addedの場合はnil
+	Old *T
+	// New 変更後のオブジェクト。Deletedの場合はnil
+	New *T
+}
+
+// Handler イベントを処理する関数
+type Handler[T any] func(ctx context.Context, ev Event[T])
+
+// ListFunc オブジェクトの一覧を全て取得する関数
+type ListFunc[T any] func(ctx context.Context) ([]T, error)
+
+// KeyFunc オブジェクトを一意に識別するキーを返す関数
+type KeyFunc[T any] func(obj *T) string
+
+// IndexFunc オブジェクトを索引に登録する値を返す関数
+type IndexFunc[T any] func(obj *T) []string
+
+// config Informerの設定
+type config struct {
+	interval    time.Duration
+	resync      time.Duration
+	concurrency int
+	selector    workflows.Selector
+	logger      *slog.Logger
+}
+
+// Option Informerの設定
+type Option func(*config)
+
+// WithInterval sets how often the list is polled. Defaults to DefaultInterval.
+func WithInterval(d time.Duration) Option {
+	return func(c *config) { c.interval = d }
+}
+
+// WithResync sets how often the whole store is delivered as Synced events. By default it never is.
+func WithResync(d time.Duration) Option {
+	return func(c *config) { c.resync = d }
+}
+
+// WithConcurrency sets how many handlers may run at once. Defaults to DefaultConcurrency.
+func WithConcurrency(n int) Option {
+	return func(c *config) { c.concurrency = n }
+}
+
+// WithSelector limits the workflows watched, and those whose executions are watched, to the ones the selector matches
+func WithSelector(sel workflows.Selector) Option {
+	return func(c *config) { c.selector = sel }
+}
+
+// WithLogger sets the logger of list errors. Defaults to slog.Default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) { c.logger = logger }
+}
+
+// item ストアの1件。dataは変更の検出に使う
+type item[T any] struct {
+	obj  T
+	data []byte
+}
+
+// Informer 一覧のポーリングでオブジェクトの変更を検出し、ハンドラに送る
+type Informer[T any] struct {
+	list ListFunc[T]
+	key  KeyFunc[T]
+	cfg  config
+
+	mu       sync.RWMutex
+	items    map[string]*item[T]
+	indexers map[string]IndexFunc[T]
+	// indices 索引の名前→値→キー
+	indices  map[string]map[string][]string
+	handlers []Handler[T]
+	synced   bool
+}
+
+// New returns an informer of the objects list returns, identified by key
+func New[T any](list ListFunc[T], key KeyFunc[T], opts ...Option) *Informer[T] {
+	cfg := config{interval: DefaultInterval, concurrency: DefaultConcurrency, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.concurrency = max(cfg.concurrency, 1)
+	return &Informer[T]{
+		list:     list,
+		key:      key,
+		cfg:      cfg,
+		items:    map[string]*item[T]{},
+		indexers: map[string]IndexFunc[T]{},
+		indices:  map[string]map[string][]string{},
+	}
+}
+
+// AddHandler adds a handler of the events. Handlers added after Run has started are not called.
+func (inf *Informer[T]) AddHandler(h Handler[T]) {
+	inf.mu.Lock()
+	defer inf.mu.Unlock()
+	inf.handlers = append(inf.handlers, h)
+}
+
+// AddIndex adds an index looked up by ByIndex
+func (inf *Informer[T]) AddIndex(name string, fn IndexFunc[T]) {
+	inf.mu.Lock()
+	defer inf.mu.Unlock()
+	inf.indexers[name] = fn
+	inf.reindex()
+}
+
+// HasSynced reports whether the store has been filled by a list
+func (inf *Informer[T]) HasSynced() bool {
+	inf.mu.RLock()
+	defer inf.mu.RUnlock()
+	return inf.synced
+}
+
+// Get returns the object of the key in the store
+func (inf *Informer[T]) Get(key string) (T, bool) {
+	inf.mu.RLock()
+	defer inf.mu.RUnlock()
+	it, ok := inf.items[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return it.obj, true
+}
+
+// Keys returns the keys of the objects in the store in order
+func (inf *Informer[T]) Keys() []string {
+	inf.mu.RLock()
+	defer inf.mu.RUnlock()
+	keys := make([]string, 0, len(inf.items))
+	for key := range inf.items {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// List returns the objects in the store in the order of their keys
+func (inf *Informer[T]) List() []T {
+	keys := inf.Keys()
+	inf.mu.RLock()
+	defer inf.mu.RUnlock()
+	ret := make([]T, 0, len(keys))
+	for _, key := range keys {
+		if it, ok := inf.items[key]; ok {
+			ret = append(ret, it.obj)
+		}
+	}
+	return ret
+}
+
+// ByIndex returns the objects the index has under value, in the order of their keys
+func (inf *Informer[T]) ByIndex(name, value string) ([]T, error) {
+	inf.mu.RLock()
+	defer inf.mu.RUnlock()
+	index, ok := inf.indices[name]
+	if !ok {
+		return nil, fmt.Errorf("informer: no index %q", name)
+	}
+	var ret []T
+	for _, key := range index[value] {
+		ret = append(ret, inf.items[key].obj)
+	}
+	return ret, nil
+}
+
+// reindex rebuilds the indices from the items. The lock must be held.
+func (inf *Informer[T]) reindex() {
+	inf.indices = map[string]map[string][]string{}
+	for name := range inf.indexers {
+		inf.indices[name] = map[string][]string{}
+	}
+	for key, it := range inf.items {
+		for name, fn := range inf.indexers {
+			for _, value := range fn(&it.obj) {
+				if !slices.Contains(inf.indices[name][value], key) {
+					inf.indices[name][value] = append(inf.indices[name][value], key)
+				}
+			}
+		}
+	}
+	for _, index := range inf.indices {
+		for _, keys := range index {
+			slices.Sort(keys)
+		}
+	}
+}
+
+// Poll lists the objects once, replaces the store with them and returns the changes in the order of their keys.
+// The events are not delivered to the handlers; Run does that.
+func (inf *Informer[T]) Poll(ctx context.Context) ([]Event[T], error) {
+	objs, err := inf.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	next := make(map[string]*item[T], len(objs))
+	for _, obj := range objs {
+		data, err := json.Marshal(&obj)
+		if err != nil {
+			return nil, fmt.Errorf("informer: %w", err)
+		}
+		next[inf.key(&obj)] = &item[T]{obj: obj, data: data}
+	}
+
+	inf.mu.Lock()
+	defer inf.mu.Unlock()
+	var events []Event[T]
+	for key, it := range next {
+		old, ok := inf.items[key]
+		switch {
+		case !ok:
+			events = append(events, Event[T]{Type: Added, Key: key, New: &it.obj})
+		case !bytes.Equal(old.data, it.data):
+			events = append(events, Event[T]{Type: Updated, Key: key, Old: &old.obj, New: &it.obj})
+		}
+	}
+	for key, old := range inf.items {
+		if _, ok := next[key]; !ok {
+			events = append(events, Event[T]{Type: Deleted, Key: key, Old: &old.obj})
+		}
+	}
+	slices.SortFunc(events, func(a, b Event[T]) int { return strings.Compare(a.Key, b.Key) })
+	inf.items = next
+	inf.synced = true
+	inf.reindex()
+	return events, nil
+}
+
+// Resync returns a Synced event of every object in the store, in the order of their keys
+func (inf *Informer[T]) Resync() []Event[T] {
+	keys := inf.Keys()
+	inf.mu.RLock()
+	defer inf.mu.RUnlock()
+	events := make([]Event[T], 0, len(keys))
+	for _, key := range keys {
+		if it, ok := inf.items[key]; ok {
+			events = append(events, Event[T]{Type: Synced, Key: key, Old: &it.obj, New: &it.obj})
+		}
+	}
+	return events
+}
+
+// Run polls the list every interval and delivers the changes to the handlers until ctx is canceled.
+// The first list delivers every object as Added. A failed list is logged and retried at the next interval.
+// Run returns after the running handlers have returned; events still queued then are dropped.
+func (inf *Informer[T]) Run(ctx context.Context) error {
+	inf.mu.RLock()
+	handlers := slices.Clone(inf.handlers)
+	inf.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	queues := make([]chan Event[T], inf.cfg.concurrency)
+	for n := range queues {
+		queues[n] = make(chan Event[T], 64)
+		wg.Add(1)
+		go func(queue <-chan Event[T]) {
+			defer wg.Done()
+			for ev := range queue {
+				if ctx.Err() != nil {
+					continue
+				}
+				for _, h := range handlers {
+					h(ctx, ev)
+				}
+			}
+		}(queues[n])
+	}
+	defer func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+		wg.Wait()
+	}()
+
+	dispatch := func(events []Event[T]) {
+		for _, ev := range events {
+			h := fnv.New32a()
+			h.Write([]byte(ev.Key)) //nolint:errcheck
+			select {
+			case queues[h.Sum32()%uint32(len(queues))] <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	lastResync := time.Now()
+	for {
+		events, err := inf.Poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			inf.cfg.logger.ErrorContext(ctx, "informer list failed", slog.Any("error", err))
+		} else {
+			dispatch(events)
+		}
+		if inf.cfg.resync > 0 && time.Since(lastResync) >= inf.cfg.resync {
+			dispatch(inf.Resync())
+			lastResync = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(inf.cfg.interval):
+		}
+	}
+}
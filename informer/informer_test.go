@@ -0,0 +1,159 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/informer"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// eventSummary テストで比較しやすい形にしたイベント
+type eventSummary struct {
+	Type informer.EventType
+	Key  string
+	Old  string
+	New  string
+}
+
+func summarize(events []informer.Event[v1.ListWorkflowOKWorkflowsItem]) []eventSummary {
+	var ret []eventSummary
+	for _, ev := range events {
+		s := eventSummary{Type: ev.Type, Key: ev.Key}
+		if ev.Old != nil {
+			s.Old = ev.Old.Name
+		}
+		if ev.New != nil {
+			s.New = ev.New.Name
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+func TestWorkflowInformer_Poll(t *testing.T) {
+	api := workflowsmock.NewWorkflowAPI(t)
+	inf := informer.NewWorkflowInformer(&workflows.Client{Workflows: api})
+	assert.False(t, inf.HasSynced())
+
+	api.OnList(mock.Anything, mock.Anything).Return(workflowsmock.WorkflowList(
+		workflowsmock.Workflow{ID: "a", Name: "a", Tags: []string{"env=prod"}},
+		workflowsmock.Workflow{ID: "b", Name: "b", Tags: []string{"env=dev"}},
+	), nil).Once()
+	events, err := inf.Poll(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []eventSummary{{Type: informer.Added, Key: "a", New: "a"}, {Type: informer.Added, Key: "b", New: "b"}}, summarize(events))
+	assert.True(t, inf.HasSynced())
+
+	api.OnList(mock.Anything, mock.Anything).Return(workflowsmock.WorkflowList(
+		workflowsmock.Workflow{ID: "a", Name: "a", Tags: []string{"env=prod"}},
+		workflowsmock.Workflow{ID: "b", Name: "b2", Tags: []string{"env=prod"}},
+		workflowsmock.Workflow{ID: "c", Name: "c"},
+	), nil).Once()
+	events, err = inf.Poll(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []eventSummary{{Type: informer.Updated, Key: "b", Old: "b", New: "b2"}, {Type: informer.Added, Key: "c", New: "c"}}, summarize(events))
+
+	prod, err := inf.ByIndex(informer.IndexTag, "env=prod")
+	require.NoError(t, err)
+	require.Len(t, prod, 2)
+	assert.Equal(t, "b2", prod[1].Name)
+	_, err = inf.ByIndex("missing", "")
+	assert.Error(t, err)
+
+	api.OnList(mock.Anything, mock.Anything).Return(workflowsmock.WorkflowList(workflowsmock.Workflow{ID: "c", Name: "c"}), nil).Once()
+	events, err = inf.Poll(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []eventSummary{{Type: informer.Deleted, Key: "a", Old: "a"}, {Type: informer.Deleted, Key: "b", Old: "b2"}}, summarize(events))
+	assert.Equal(t, []string{"c"}, inf.Keys())
+	assert.Equal(t, []eventSummary{{Type: informer.Synced, Key: "c", Old: "c", New: "c"}}, summarize(inf.Resync()))
+}
+
+func TestExecutionInformer_Poll(t *testing.T) {
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnList(mock.Anything, mock.Anything).Return(workflowsmock.WorkflowList(
+		workflowsmock.Workflow{ID: "a", Tags: []string{"team=x"}}, workflowsmock.Workflow{ID: "b"},
+	), nil).Once()
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnList(mock.Anything, mock.MatchedBy(func(p v1.ListExecutionParams) bool { return p.ID == "a" })).Return(workflowsmock.ExecutionList(
+		workflowsmock.Execution{ID: "e1", Workflow: workflowsmock.Workflow{ID: "a"}, Status: "Running"},
+		workflowsmock.Execution{ID: "e2", Workflow: workflowsmock.Workflow{ID: "a"}, Status: "Succeeded"},
+	), nil).Once()
+
+	inf := informer.NewExecutionInformer(&workflows.Client{Workflows: wfs, Executions: executions}, nil,
+		informer.WithSelector(workflows.MustParseSelector("team=x")))
+	_, err := inf.Poll(t.Context())
+	require.NoError(t, err)
+
+	running, err := inf.ByIndex(informer.IndexStatus, "Running")
+	require.NoError(t, err)
+	require.Len(t, running, 1)
+	assert.Equal(t, "e1", running[0].ExecutionId)
+	ofA, err := inf.ByIndex(informer.IndexWorkflow, "a")
+	require.NoError(t, err)
+	assert.Len(t, ofA, 2)
+}
+
+func TestInformer_Run(t *testing.T) {
+	var mu sync.Mutex
+	polls := 0
+	list := func(context.Context) ([]int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		polls++
+		// 1 and 2 are added, then 2 is updated, then everything stays as is
+		switch polls {
+		case 1:
+			return []int{10, 20}, nil
+		default:
+			return []int{10, 21}, nil
+		}
+	}
+	inf := informer.New(list, func(n *int) string { return string(rune('0' + *n/10)) },
+		informer.WithInterval(time.Millisecond), informer.WithResync(time.Millisecond), informer.WithConcurrency(2))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	var events []informer.Event[int]
+	inf.AddHandler(func(_ context.Context, ev informer.Event[int]) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, ev)
+		if ev.Type == informer.Synced && polls > 2 {
+			cancel()
+		}
+	})
+	require.ErrorIs(t, inf.Run(ctx), context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	var ofTwo []informer.EventType
+	for _, ev := range events {
+		if ev.Key == "2" {
+			ofTwo = append(ofTwo, ev.Type)
+		}
+	}
+	require.GreaterOrEqual(t, len(ofTwo), 2)
+	assert.Equal(t, []informer.EventType{informer.Added, informer.Updated}, ofTwo[:2], "in order for the same key")
+	assert.Equal(t, []int{10, 21}, inf.List())
+}
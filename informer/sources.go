@@ -0,0 +1,113 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informer
+
+import (
+	"context"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+const (
+	// IndexTag ワークフローをタグで引く索引
+	IndexTag = "tag"
+	// IndexWorkflow 実行をワークフローIDで引く索引
+	IndexWorkflow = "workflow"
+	// IndexStatus 実行を状態で引く索引
+	IndexStatus = "status"
+)
+
+// pageLimit 一覧を取得する際の1ページの件数
+const pageLimit = 100
+
+// WorkflowInformer ワークフローのInformer
+type WorkflowInformer = Informer[v1.ListWorkflowOKWorkflowsItem]
+
+// ExecutionInformer 実行のInformer
+type ExecutionInformer = Informer[v1.ListExecutionOKExecutionsItem]
+
+// NewWorkflowInformer returns an informer of the workflows, keyed by ID and indexed by IndexTag
+func NewWorkflowInformer(client *workflows.Client, opts ...Option) *WorkflowInformer {
+	var inf *WorkflowInformer
+	inf = New(func(ctx context.Context) ([]v1.ListWorkflowOKWorkflowsItem, error) {
+		return client.SelectWorkflows(ctx, inf.cfg.selector)
+	}, func(wf *v1.ListWorkflowOKWorkflowsItem) string {
+		return wf.ID
+	}, opts...)
+	inf.AddIndex(IndexTag, func(wf *v1.ListWorkflowOKWorkflowsItem) []string {
+		tags := make([]string, 0, len(wf.Tags))
+		for _, tag := range wf.Tags {
+			tags = append(tags, tag.Name)
+		}
+		return tags
+	})
+	return inf
+}
+
+// NewExecutionInformer returns an informer of the executions of the workflows, keyed by execution ID and
+// indexed by IndexWorkflow and IndexStatus. With no workflow IDs, the executions of every workflow (or of
+// those WithSelector matches) are watched, and the workflows are listed again at each poll.
+//
+// Every poll lists all the executions of the workflows, so purge old ones or keep the interval long
+// when there are many.
+func NewExecutionInformer(client *workflows.Client, workflowIDs []string, opts ...Option) *ExecutionInformer {
+	var inf *ExecutionInformer
+	inf = New(func(ctx context.Context) ([]v1.ListExecutionOKExecutionsItem, error) {
+		ids := workflowIDs
+		if len(ids) == 0 {
+			wfs, err := client.SelectWorkflows(ctx, inf.cfg.selector)
+			if err != nil {
+				return nil, err
+			}
+			for _, wf := range wfs {
+				ids = append(ids, wf.ID)
+			}
+		}
+		var ret []v1.ListExecutionOKExecutionsItem
+		for _, id := range ids {
+			executions, err := listExecutions(ctx, client, id)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, executions...)
+		}
+		return ret, nil
+	}, func(e *v1.ListExecutionOKExecutionsItem) string {
+		return e.ExecutionId
+	}, opts...)
+	inf.AddIndex(IndexWorkflow, func(e *v1.ListExecutionOKExecutionsItem) []string {
+		return []string{e.Workflow.ID}
+	})
+	inf.AddIndex(IndexStatus, func(e *v1.ListExecutionOKExecutionsItem) []string {
+		return []string{string(e.Status)}
+	})
+	return inf
+}
+
+func listExecutions(ctx context.Context, client *workflows.Client, workflowID string) ([]v1.ListExecutionOKExecutionsItem, error) {
+	var ret []v1.ListExecutionOKExecutionsItem
+	for page, seen := 1, 0; ; page++ {
+		res, err := client.Executions.List(ctx, v1.ListExecutionParams{ID: workflowID, Page: v1.NewOptInt(page), PageLimit: v1.NewOptInt(pageLimit)})
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, res.Executions...)
+		seen += len(res.Executions)
+		if len(res.Executions) < pageLimit || seen >= res.Total {
+			return ret, nil
+		}
+	}
+}
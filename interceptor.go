@@ -0,0 +1,134 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// Request インターセプタに渡される型付きリクエスト
+//
+// Body/Paramsには生成された型へのポインタが入るため、インターセプタはその場で値を書き換えることができる。
+// 同じ型の別のポインタに差し替えることはできるが、別の型の値やnilに差し替えると操作はエラーになる。
+type Request struct {
+	// Operation "Workflow.Create" などの操作名
+	Operation string
+	// Body リクエストボディ(*v1.CreateWorkflowReq など)。ボディを持たない操作ではnil
+	Body any
+	// Params パス/クエリパラメータ(*v1.UpdateWorkflowParams など)。パラメータを持たない操作ではnil
+	Params any
+}
+
+// Handler インターセプタチェーンの次の処理
+//
+// 戻り値はOpの各メソッドの戻り値の型(*v1.CreateWorkflowCreatedWorkflow など)で、エラーのみを返す操作ではnil
+type Handler func(ctx context.Context, req *Request) (any, error)
+
+// Interceptor Opの各メソッド呼び出しに割り込む処理
+//
+// nextを呼ばずにエラーを返すことで呼び出しを中断できる。
+type Interceptor func(ctx context.Context, req *Request, next Handler) (any, error)
+
+// Conn 生成済みのv1.Clientと、その上で動作するインターセプタをまとめたもの
+//
+// 一つのConnから作られたOpは全て同じインターセプタを共有する。
+type Conn struct {
+	client       *v1.Client
+	interceptors []Interceptor
+}
+
+// NewConn creates a new Conn. Interceptors are called in the given order, the first one being the outermost.
+func NewConn(client *v1.Client, interceptors ...Interceptor) *Conn {
+	return &Conn{client: client, interceptors: interceptors}
+}
+
+// Client returns the underlying generated client
+func (c *Conn) Client() *v1.Client {
+	return c.client
+}
+
+// With returns a copy of the Conn with additional interceptors appended to the chain
+func (c *Conn) With(interceptors ...Interceptor) *Conn {
+	chain := make([]Interceptor, 0, len(c.interceptors)+len(interceptors))
+	chain = append(chain, c.interceptors...)
+	chain = append(chain, interceptors...)
+	return &Conn{client: c.client, interceptors: chain}
+}
+
+func intercept[T any](ctx context.Context, interceptors []Interceptor, req *Request, call func(context.Context, *Request) (T, error)) (T, error) {
+	var zero T
+
+	if len(interceptors) == 0 {
+		return call(ctx, req)
+	}
+
+	res, err := chain(interceptors, func(ctx context.Context, req *Request) (any, error) {
+		return call(ctx, req)
+	})(ctx, req)
+	if res == nil {
+		if err == nil {
+			return zero, NewError(fmt.Sprintf("%s: interceptor returned no response", req.Operation), nil)
+		}
+		return zero, err
+	}
+	typed, ok := res.(T)
+	if !ok {
+		return zero, NewError(fmt.Sprintf("%s: interceptor returned unexpected response type %T", req.Operation, res), err)
+	}
+	return typed, err
+}
+
+func interceptErr(ctx context.Context, interceptors []Interceptor, req *Request, call func(context.Context, *Request) error) error {
+	if len(interceptors) == 0 {
+		return call(ctx, req)
+	}
+
+	_, err := chain(interceptors, func(ctx context.Context, req *Request) (any, error) {
+		return nil, call(ctx, req)
+	})(ctx, req)
+	return err
+}
+
+func chain(interceptors []Interceptor, last Handler) Handler {
+	h := last
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		ic, next := interceptors[i], h
+		h = func(ctx context.Context, req *Request) (any, error) {
+			return ic(ctx, req, next)
+		}
+	}
+	return h
+}
+
+// requestBody returns the body of rq. It fails if an interceptor replaced it with a value of another type or nil.
+func requestBody[T any](rq *Request) (*T, error) {
+	body, ok := rq.Body.(*T)
+	if !ok || body == nil {
+		return nil, NewError(fmt.Sprintf("%s: unexpected request body type %T", rq.Operation, rq.Body), nil)
+	}
+	return body, nil
+}
+
+// requestParams returns the parameters of rq. It fails if an interceptor replaced them with a value of another type or nil.
+func requestParams[T any](rq *Request) (*T, error) {
+	params, ok := rq.Params.(*T)
+	if !ok || params == nil {
+		return nil, NewError(fmt.Sprintf("%s: unexpected request parameters type %T", rq.Operation, rq.Params), nil)
+	}
+	return params, nil
+}
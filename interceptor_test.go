@@ -0,0 +1,152 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptor_order(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"is_ok":true,"Workflow":`+testWorkflowJSON+`}`)
+	}))
+
+	var calls []string
+	record := func(name string) workflows.Interceptor {
+		return func(ctx context.Context, req *workflows.Request, next workflows.Handler) (any, error) {
+			calls = append(calls, name+":before:"+req.Operation)
+			res, err := next(ctx, req)
+			calls = append(calls, name+":after")
+			return res, err
+		}
+	}
+
+	conn := workflows.NewConn(client, record("outer")).With(record("inner"))
+	workflow, err := workflows.NewWorkflowOpWithConn(conn).Read(t.Context(), "123456789012")
+	require.NoError(t, err)
+	assert.Equal(t, "test-workflow", workflow.Name)
+	assert.Equal(t, []string{
+		"outer:before:Workflow.Read",
+		"inner:before:Workflow.Read",
+		"inner:after",
+		"outer:after",
+	}, calls)
+}
+
+func TestInterceptor_typedRequestAndResponse(t *testing.T) {
+	var received v1.CreateWorkflowReq
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		writeJSON(w, http.StatusCreated, `{"is_ok":true,"Workflow":`+testWorkflowJSON+`}`)
+	}))
+
+	var seen *v1.CreateWorkflowCreatedWorkflow
+	forceTags := func(ctx context.Context, req *workflows.Request, next workflows.Handler) (any, error) {
+		if body, ok := req.Body.(*v1.CreateWorkflowReq); ok {
+			body.Tags = append(body.Tags, v1.CreateWorkflowReqTagsItem{Name: "owner=platform"})
+		}
+		res, err := next(ctx, req)
+		seen, _ = res.(*v1.CreateWorkflowCreatedWorkflow)
+		return res, err
+	}
+
+	conn := workflows.NewConn(client, forceTags)
+	created, err := workflows.NewWorkflowOpWithConn(conn).Create(t.Context(), v1.CreateWorkflowReq{
+		Name:    "test-workflow",
+		Runbook: "steps: {}",
+		Tags:    []v1.CreateWorkflowReqTagsItem{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []v1.CreateWorkflowReqTagsItem{{Name: "owner=platform"}}, received.Tags)
+	assert.Same(t, created, seen)
+}
+
+func TestInterceptor_blockMutation(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+
+	errDryRun := errors.New("dry-run")
+	dryRun := func(ctx context.Context, req *workflows.Request, next workflows.Handler) (any, error) {
+		return nil, errDryRun
+	}
+
+	conn := workflows.NewConn(client, dryRun)
+	err := workflows.NewWorkflowOpWithConn(conn).Delete(t.Context(), "123456789012")
+	require.ErrorIs(t, err, errDryRun)
+
+	_, err = workflows.NewExecutionOpWithConn(conn).Cancel(t.Context(), "123456789012", "exec")
+	require.ErrorIs(t, err, errDryRun)
+}
+
+func TestInterceptor_unexpectedResponseType(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+
+	broken := func(ctx context.Context, req *workflows.Request, next workflows.Handler) (any, error) {
+		return "not a workflow", nil
+	}
+
+	conn := workflows.NewConn(client, broken)
+	_, err := workflows.NewWorkflowOpWithConn(conn).Read(t.Context(), "123456789012")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected response type")
+}
+
+func TestInterceptor_noResponse(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+
+	swallow := func(ctx context.Context, req *workflows.Request, next workflows.Handler) (any, error) {
+		return nil, nil
+	}
+
+	conn := workflows.NewConn(client, swallow)
+	wf, err := workflows.NewWorkflowOpWithConn(conn).Read(t.Context(), "123456789012")
+	assert.Nil(t, wf)
+	assert.EqualError(t, err, "workflows: Workflow.Read: interceptor returned no response")
+
+	// operations without a response may be swallowed
+	require.NoError(t, workflows.NewWorkflowOpWithConn(conn).Delete(t.Context(), "123456789012"))
+}
+
+func TestInterceptor_unexpectedRequestType(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+
+	replace := func(ctx context.Context, req *workflows.Request, next workflows.Handler) (any, error) {
+		if req.Body != nil {
+			req.Body = v1.CreateExecutionReq{}
+		} else {
+			req.Params = "123456789012"
+		}
+		return next(ctx, req)
+	}
+
+	conn := workflows.NewConn(client, replace)
+	_, err := workflows.NewExecutionOpWithConn(conn).Create(t.Context(), "123456789012", v1.OptCreateExecutionReq{})
+	assert.EqualError(t, err, "workflows: Execution.Create: unexpected request body type v1.CreateExecutionReq")
+
+	err = workflows.NewWorkflowOpWithConn(conn).Delete(t.Context(), "123456789012")
+	assert.EqualError(t, err, "workflows: Workflow.Delete: unexpected request parameters type string")
+}
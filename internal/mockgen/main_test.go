@@ -0,0 +1,32 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerate_upToDate fails when the interfaces changed without running `make mock`
+func TestGenerate_upToDate(t *testing.T) {
+	want, err := generate("../..")
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../workflowsmock/mock_gen.go")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got), "workflowsmock is outdated; run `make mock`")
+}
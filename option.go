@@ -0,0 +1,224 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	ht "github.com/ogen-go/ogen/http"
+	client "github.com/sacloud/api-client-go"
+	"github.com/sacloud/saclient-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// Option Newに渡す設定
+type Option func(*options)
+
+// Tracer Opの呼び出しごとにスパンを開始する
+//
+// Startが返した関数は呼び出しの完了時に、その結果のエラー(成功時はnil)を引数に呼ばれる。
+type Tracer interface {
+	Start(ctx context.Context, operation string) (context.Context, func(err error))
+}
+
+type options struct {
+	saclient     saclient.ClientAPI
+	httpClient   ht.Client
+	wrapHTTP     func(ht.Client) ht.Client
+	apiRootURL   string
+	zone         string
+	retry        *retryOptions
+	rateLimit    int
+	logger       *slog.Logger
+	tracer       Tracer
+	interceptors []Interceptor
+}
+
+type retryOptions struct {
+	max     int
+	waitMin time.Duration
+	waitMax time.Duration
+}
+
+// WithSaclient sets the saclient used for authentication and profiles. Defaults to a zero saclient.Client.
+func WithSaclient(c saclient.ClientAPI) Option {
+	return func(o *options) { o.saclient = c }
+}
+
+// WithHTTPClient sets the HTTP client passed to v1.WithClient as is.
+// The saclient and its settings (retry, rate limit, authentication) are bypassed in that case.
+func WithHTTPClient(c ht.Client) Option {
+	return func(o *options) { o.httpClient = c }
+}
+
+// WithHTTPClientWrapper wraps the HTTP client passed to v1.WithClient, e.g. to record or replay requests.
+// Unlike WithHTTPClient the saclient settings are kept.
+func WithHTTPClientWrapper(wrap func(ht.Client) ht.Client) Option {
+	return func(o *options) { o.wrapHTTP = wrap }
+}
+
+// WithAPIRootURL sets the API root URL. It takes precedence over WithZone.
+func WithAPIRootURL(url string) Option {
+	return func(o *options) { o.apiRootURL = url }
+}
+
+// WithZone sets the zone whose endpoint is used. New fails if the zone is not one of Zones.
+func WithZone(zone string) Option {
+	return func(o *options) { o.zone = zone }
+}
+
+// WithRetry sets the retry policy of the saclient. maxRetries <= 0 disables retries.
+// The saclient waits in whole seconds, so waitMin and waitMax are rounded up to the next second.
+func WithRetry(maxRetries int, waitMin, waitMax time.Duration) Option {
+	return func(o *options) { o.retry = &retryOptions{max: maxRetries, waitMin: waitMin, waitMax: waitMax} }
+}
+
+// WithRateLimit sets the maximum number of requests per second
+func WithRateLimit(perSecond int) Option {
+	return func(o *options) { o.rateLimit = perSecond }
+}
+
+// WithLogger logs every Op call to the logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithTracer starts a span for every Op call
+func WithTracer(tracer Tracer) Option {
+	return func(o *options) { o.tracer = tracer }
+}
+
+// WithInterceptors appends interceptors to the chain shared by all APIs
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(o *options) { o.interceptors = append(o.interceptors, interceptors...) }
+}
+
+func (o *options) newClient() (*v1.Client, error) {
+	base := o.saclient
+	if base == nil {
+		base = &saclient.Client{}
+	}
+
+	apiURL, err := o.rootURL(base)
+	if err != nil {
+		return nil, err
+	}
+
+	var doer ht.Client
+	if o.httpClient != nil {
+		doer = o.httpClient
+	} else if doer, err = o.augmentedSaclient(base); err != nil {
+		return nil, err
+	}
+	if o.wrapHTTP != nil {
+		doer = o.wrapHTTP(doer)
+	}
+	return v1.NewClient(apiURL, voidSecuritySource{}, v1.WithClient(doer))
+}
+
+func (o *options) augmentedSaclient(base saclient.ClientAPI) (saclient.ClientAPI, error) {
+	if o.retry != nil || o.rateLimit > 0 {
+		base = base.Dup()
+		compat := &client.Options{HttpRequestRateLimit: o.rateLimit}
+		if o.retry != nil && o.retry.max > 0 {
+			compat.RetryMax = o.retry.max
+			compat.RetryWaitMin = waitSeconds(o.retry.waitMin)
+			compat.RetryWaitMax = waitSeconds(o.retry.waitMax)
+		}
+		if err := base.CompatSettingsFromAPIClientOptions(compat); err != nil {
+			return nil, NewError("unable to apply client options", err)
+		}
+		if o.retry != nil && o.retry.max <= 0 {
+			if settable, ok := base.(saclient.ClientOptionAPI); !ok {
+				return nil, NewError("client does not implement saclient.ClientOptionAPI", nil)
+			} else if err := settable.SetWith(saclient.WithoutRetry()); err != nil {
+				return nil, NewError("unable to disable retries", err)
+			}
+		}
+	}
+
+	return augment(base)
+}
+
+func (o *options) rootURL(base saclient.ClientAPI) (string, error) {
+	switch {
+	case o.apiRootURL != "":
+		return o.apiRootURL, nil
+	case o.zone != "":
+		return APIRootURLForZone(o.zone)
+	case o.httpClient != nil:
+		return DefaultAPIRootURL, nil
+	}
+
+	endpointConfig, err := base.EndpointConfig()
+	if err != nil {
+		return "", NewError("unable to load endpoint configuration", err)
+	}
+	if ep, ok := endpointConfig.Endpoints[serviceKey]; ok && ep != "" {
+		return ep, nil
+	}
+	return DefaultAPIRootURL, nil
+}
+
+func (o *options) interceptorChain() []Interceptor {
+	var chain []Interceptor
+	if o.tracer != nil {
+		chain = append(chain, tracingInterceptor(o.tracer))
+	}
+	if o.logger != nil {
+		chain = append(chain, loggingInterceptor(o.logger))
+	}
+	return append(chain, o.interceptors...)
+}
+
+func tracingInterceptor(tracer Tracer) Interceptor {
+	return func(ctx context.Context, req *Request, next Handler) (any, error) {
+		ctx, end := tracer.Start(ctx, req.Operation)
+		res, err := next(ctx, req)
+		end(err)
+		return res, err
+	}
+}
+
+func loggingInterceptor(logger *slog.Logger) Interceptor {
+	return func(ctx context.Context, req *Request, next Handler) (any, error) {
+		start := time.Now()
+		res, err := next(ctx, req)
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelError, "workflows API call failed",
+				slog.String("operation", req.Operation),
+				slog.Duration("elapsed", time.Since(start)),
+				slog.Any("error", err),
+			)
+		} else {
+			logger.LogAttrs(ctx, slog.LevelDebug, "workflows API call",
+				slog.String("operation", req.Operation),
+				slog.Duration("elapsed", time.Since(start)),
+			)
+		}
+		return res, err
+	}
+}
+
+// waitSeconds converts a retry wait to the whole seconds taken by the saclient, rounding up
+// so that a sub-second wait does not turn into no wait at all
+func waitSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,30 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitSeconds(t *testing.T) {
+	assert.Equal(t, 0, waitSeconds(0))
+	assert.Equal(t, 0, waitSeconds(-time.Second))
+	assert.Equal(t, 1, waitSeconds(time.Millisecond))
+	assert.Equal(t, 1, waitSeconds(time.Second))
+	assert.Equal(t, 2, waitSeconds(1500*time.Millisecond))
+}
@@ -0,0 +1,177 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// ErrSubscriptionInUse ワークフローが残っているためにUnsubscribeが解約を拒否したことを表す
+var ErrSubscriptionInUse = errors.New("subscription is in use")
+
+// PlanChange EnsureSubscription・ChangePlanの結果
+type PlanChange struct {
+	// From 変更前のプラン。契約していなかった場合はnil
+	From *v1.ListPlansOKPlansItem
+	To   v1.ListPlansOKPlansItem
+	// Changed Subscription.Createを呼び出した
+	Changed bool
+	// Before/After 変更前後の今月適用されるプラン。ない場合はnil
+	Before *v1.GetSubscriptionOKMonthAppliedPlan
+	After  *v1.GetSubscriptionOKMonthAppliedPlan
+	// ActivateFrom 新しいプランが有効になる日時
+	ActivateFrom time.Time
+}
+
+// AppliesThisMonth reports whether the new plan is billed from this month.
+// A change not reflected in the month applied plan, e.g. a downgrade, takes effect next month.
+func (c *PlanChange) AppliesThisMonth() bool {
+	return c.After != nil && c.After.PlanId == c.To.ID
+}
+
+// FindPlan returns the plan whose name matches plan case-insensitively, or whose grade is plan if it is a number
+func (c *Client) FindPlan(ctx context.Context, plan string) (*v1.ListPlansOKPlansItem, error) {
+	res, err := c.Subscription.ListPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	grade, gradeErr := strconv.Atoi(plan)
+	for _, p := range res.Plans {
+		if strings.EqualFold(p.Name, plan) || (gradeErr == nil && p.Grade == grade) {
+			return &p, nil
+		}
+	}
+	return nil, NewError(fmt.Sprintf("plan %q not found", plan), nil)
+}
+
+// EnsureSubscription subscribes to the plan given by name or grade unless the current plan is already it.
+// Calling it again with the same plan does nothing.
+func (c *Client) EnsureSubscription(ctx context.Context, plan string) (*PlanChange, error) {
+	return c.changePlan(ctx, plan, false)
+}
+
+// ChangePlan switches the subscription to the plan given by name or grade and reports how the
+// plan applied this month is affected. It fails if there is no subscription yet.
+func (c *Client) ChangePlan(ctx context.Context, plan string) (*PlanChange, error) {
+	return c.changePlan(ctx, plan, true)
+}
+
+func (c *Client) changePlan(ctx context.Context, plan string, mustExist bool) (*PlanChange, error) {
+	to, err := c.FindPlan(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+	before, err := c.readSubscription(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &PlanChange{To: *to}
+	if cur, ok := before.CurrentPlan.Get(); ok {
+		if cur.PlanId == to.ID {
+			ret.From = to
+			ret.ActivateFrom = cur.ActivateFrom
+			if p, ok := before.MonthAppliedPlan.Get(); ok {
+				ret.Before, ret.After = &p, &p
+			}
+			return ret, nil
+		}
+		if from, err := c.planByID(ctx, cur.PlanId); err == nil {
+			ret.From = from
+		}
+	} else if mustExist {
+		return nil, NewError("unable to change the plan", errors.New("not subscribed"))
+	}
+	if p, ok := before.MonthAppliedPlan.Get(); ok {
+		ret.Before = &p
+	}
+
+	if err := c.Subscription.Create(ctx, v1.CreateSubscriptionReq{PlanId: to.ID}); err != nil {
+		return nil, err
+	}
+	ret.Changed = true
+
+	after, err := c.readSubscription(ctx)
+	if err != nil {
+		return ret, err
+	}
+	if cur, ok := after.CurrentPlan.Get(); ok {
+		ret.ActivateFrom = cur.ActivateFrom
+	}
+	if p, ok := after.MonthAppliedPlan.Get(); ok {
+		ret.After = &p
+	}
+	return ret, nil
+}
+
+// readSubscription reads the subscription, treating 404 as no subscription
+func (c *Client) readSubscription(ctx context.Context) (*v1.GetSubscriptionOK, error) {
+	res, err := c.Subscription.Read(ctx)
+	if StatusCode(err) == http.StatusNotFound {
+		return &v1.GetSubscriptionOK{}, nil
+	}
+	return res, err
+}
+
+func (c *Client) planByID(ctx context.Context, id int) (*v1.ListPlansOKPlansItem, error) {
+	res, err := c.Subscription.ListPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range res.Plans {
+		if p.ID == id {
+			return &p, nil
+		}
+	}
+	return nil, NewError(fmt.Sprintf("plan %d not found", id), nil)
+}
+
+// UnsubscribeOptions Unsubscribeの設定
+type UnsubscribeOptions struct {
+	// Force ワークフローが残っていても解約する
+	Force bool
+}
+
+// Unsubscribe deletes the subscription. Unless forced, it fails with ErrSubscriptionInUse while
+// any workflow exists. Executions belong to workflows, so none is left once the workflows are deleted.
+func (c *Client) Unsubscribe(ctx context.Context, opts UnsubscribeOptions) error {
+	if !opts.Force {
+		workflows, err := c.countWorkflows(ctx)
+		if err != nil {
+			return err
+		}
+		if workflows > 0 {
+			return NewError("unable to unsubscribe", fmt.Errorf("%w: %d workflows", ErrSubscriptionInUse, workflows))
+		}
+	}
+	return c.Subscription.Delete(ctx)
+}
+
+// countWorkflows returns the number of workflows from the total of a one-item page
+func (c *Client) countWorkflows(ctx context.Context) (int, error) {
+	res, err := c.Workflows.List(ctx, v1.ListWorkflowParams{Page: v1.NewOptInt(1), PageLimit: v1.NewOptInt(1)})
+	if err != nil {
+		return 0, err
+	}
+	return res.Total, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"testing"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testPlans = workflowsmock.Plans(10,
+	v1.ListPlansOKPlansItem{ID: 1, Name: "Basic", Grade: 1},
+	v1.ListPlansOKPlansItem{ID: 2, Name: "Standard", Grade: 2},
+)
+
+func subscribedTo(planID int, applied int) *v1.GetSubscriptionOK {
+	return &v1.GetSubscriptionOK{
+		IsOk:             true,
+		CurrentPlan:      v1.NewOptNilGetSubscriptionOKCurrentPlan(v1.GetSubscriptionOKCurrentPlan{PlanId: planID}),
+		MonthAppliedPlan: v1.NewOptGetSubscriptionOKMonthAppliedPlan(v1.GetSubscriptionOKMonthAppliedPlan{PlanId: applied}),
+	}
+}
+
+func TestClient_EnsureSubscription(t *testing.T) {
+	t.Run("already subscribed", func(t *testing.T) {
+		subscription := workflowsmock.NewSubscriptionAPI(t)
+		subscription.OnListPlans(mock.Anything).Return(testPlans, nil)
+		subscription.OnRead(mock.Anything).Return(subscribedTo(2, 2), nil).Once()
+
+		client := &workflows.Client{Subscription: subscription}
+		change, err := client.EnsureSubscription(t.Context(), "standard")
+		require.NoError(t, err)
+		assert.False(t, change.Changed)
+		assert.True(t, change.AppliesThisMonth())
+	})
+
+	t.Run("not subscribed", func(t *testing.T) {
+		subscription := workflowsmock.NewSubscriptionAPI(t)
+		subscription.OnListPlans(mock.Anything).Return(testPlans, nil)
+		subscription.OnRead(mock.Anything).Return(&v1.GetSubscriptionOK{IsOk: true}, nil).Once()
+		subscription.OnCreate(mock.Anything, v1.CreateSubscriptionReq{PlanId: 1}).Return(nil).Once()
+		subscription.OnRead(mock.Anything).Return(subscribedTo(1, 1), nil).Once()
+
+		client := &workflows.Client{Subscription: subscription}
+		change, err := client.EnsureSubscription(t.Context(), "1")
+		require.NoError(t, err)
+		assert.True(t, change.Changed)
+		assert.Nil(t, change.From)
+		assert.True(t, change.AppliesThisMonth())
+	})
+
+	t.Run("unknown plan", func(t *testing.T) {
+		subscription := workflowsmock.NewSubscriptionAPI(t)
+		subscription.OnListPlans(mock.Anything).Return(testPlans, nil)
+
+		client := &workflows.Client{Subscription: subscription}
+		_, err := client.EnsureSubscription(t.Context(), "premium")
+		assert.EqualError(t, err, `workflows: plan "premium" not found`)
+	})
+}
+
+func TestClient_ChangePlan(t *testing.T) {
+	subscription := workflowsmock.NewSubscriptionAPI(t)
+	subscription.OnListPlans(mock.Anything).Return(testPlans, nil)
+	subscription.OnRead(mock.Anything).Return(subscribedTo(2, 2), nil).Once()
+	subscription.OnCreate(mock.Anything, v1.CreateSubscriptionReq{PlanId: 1}).Return(nil).Once()
+	// a downgrade is applied from the next month
+	subscription.OnRead(mock.Anything).Return(subscribedTo(1, 2), nil).Once()
+
+	client := &workflows.Client{Subscription: subscription}
+	change, err := client.ChangePlan(t.Context(), "basic")
+	require.NoError(t, err)
+	assert.True(t, change.Changed)
+	assert.Equal(t, "Standard", change.From.Name)
+	assert.Equal(t, 2, change.Before.PlanId)
+	assert.False(t, change.AppliesThisMonth())
+
+	subscription = workflowsmock.NewSubscriptionAPI(t)
+	subscription.OnListPlans(mock.Anything).Return(testPlans, nil)
+	subscription.OnRead(mock.Anything).Return(&v1.GetSubscriptionOK{IsOk: true}, nil).Once()
+	client = &workflows.Client{Subscription: subscription}
+	_, err = client.ChangePlan(t.Context(), "basic")
+	assert.EqualError(t, err, "workflows: unable to change the plan: not subscribed")
+}
+
+func TestClient_Unsubscribe(t *testing.T) {
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	list := workflowsmock.WorkflowList(workflowsmock.Workflow{ID: "wf"})
+	list.Total = 250
+	wfs.OnList(mock.Anything, v1.ListWorkflowParams{Page: v1.NewOptInt(1), PageLimit: v1.NewOptInt(1)}).Return(list, nil).Once()
+	subscription := workflowsmock.NewSubscriptionAPI(t)
+	subscription.OnDelete(mock.Anything).Return(nil).Once()
+
+	client := &workflows.Client{Workflows: wfs, Subscription: subscription}
+	err := client.Unsubscribe(t.Context(), workflows.UnsubscribeOptions{})
+	require.ErrorIs(t, err, workflows.ErrSubscriptionInUse)
+	assert.EqualError(t, err, "workflows: unable to unsubscribe: subscription is in use: 250 workflows")
+
+	require.NoError(t, client.Unsubscribe(t.Context(), workflows.UnsubscribeOptions{Force: true}))
+}
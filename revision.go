@@ -33,162 +33,197 @@ type RevisionAPI interface {
 var _ RevisionAPI = (*revisionOp)(nil)
 
 type revisionOp struct {
-	client *v1.Client
+	client       *v1.Client
+	interceptors []Interceptor
 }
 
 func NewRevisionOp(client *v1.Client) RevisionAPI {
-	return &revisionOp{client: client}
+	return NewRevisionOpWithConn(NewConn(client))
+}
+
+// NewRevisionOpWithConn creates a new RevisionAPI sharing the interceptors installed on conn
+func NewRevisionOpWithConn(conn *Conn) RevisionAPI {
+	return &revisionOp{client: conn.client, interceptors: conn.interceptors}
 }
 
 func (op *revisionOp) Create(ctx context.Context, workflowID string, req v1.CreateWorkflowRevisionReq) (*v1.CreateWorkflowRevisionCreatedRevision, error) {
 	const methodName = "Revision.Create"
 
-	res, err := op.client.CreateWorkflowRevision(ctx, &req, v1.CreateWorkflowRevisionParams{ID: workflowID})
-	if err != nil {
-		return nil, NewAPIError(methodName, 0, err)
-	}
-
-	switch r := res.(type) {
-	case *v1.CreateWorkflowRevisionCreated:
-		return &r.Revision, nil
-	case *v1.CreateWorkflowRevisionBadRequest:
-		return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.CreateWorkflowRevisionUnauthorized:
-		return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.CreateWorkflowRevisionPaymentRequired:
-		return nil, NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
-	case *v1.CreateWorkflowRevisionForbidden:
-		return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.CreateWorkflowRevisionNotFound:
-		return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.CreateWorkflowRevisionConflict:
-		return nil, NewAPIError(methodName, http.StatusConflict, errors.New(r.Message))
-	case *v1.CreateWorkflowRevisionInternalServerError:
-		return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return nil, NewAPIError(methodName, 0, err)
-	}
+	return intercept(ctx, op.interceptors, &Request{Operation: methodName, Body: &req, Params: &v1.CreateWorkflowRevisionParams{ID: workflowID}}, func(ctx context.Context, rq *Request) (*v1.CreateWorkflowRevisionCreatedRevision, error) {
+		body, err := requestBody[v1.CreateWorkflowRevisionReq](rq)
+		if err != nil {
+			return nil, err
+		}
+		params, err := requestParams[v1.CreateWorkflowRevisionParams](rq)
+		if err != nil {
+			return nil, err
+		}
+		res, err := op.client.CreateWorkflowRevision(ctx, body, *params)
+		if err != nil {
+			return nil, NewAPIError(methodName, 0, err)
+		}
+
+		switch r := res.(type) {
+		case *v1.CreateWorkflowRevisionCreated:
+			return &r.Revision, nil
+		case *v1.CreateWorkflowRevisionBadRequest:
+			return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.CreateWorkflowRevisionUnauthorized:
+			return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.CreateWorkflowRevisionPaymentRequired:
+			return nil, NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
+		case *v1.CreateWorkflowRevisionForbidden:
+			return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.CreateWorkflowRevisionNotFound:
+			return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.CreateWorkflowRevisionConflict:
+			return nil, NewAPIError(methodName, http.StatusConflict, errors.New(r.Message))
+		case *v1.CreateWorkflowRevisionInternalServerError:
+			return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return nil, NewAPIError(methodName, 0, err)
+		}
+	})
 }
 
 func (op *revisionOp) List(ctx context.Context, params v1.ListWorkflowRevisionsParams) (*v1.ListWorkflowRevisionsOK, error) {
 	const methodName = "Revision.List"
 
-	res, err := op.client.ListWorkflowRevisions(ctx, params)
-	if err != nil {
-		return nil, NewAPIError(methodName, 0, err)
-	}
-
-	switch r := res.(type) {
-	case *v1.ListWorkflowRevisionsOK:
-		return r, nil
-	case *v1.ListWorkflowRevisionsBadRequest:
-		return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.ListWorkflowRevisionsUnauthorized:
-		return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.ListWorkflowRevisionsPaymentRequired:
-		return nil, NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
-	case *v1.ListWorkflowRevisionsForbidden:
-		return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.ListWorkflowRevisionsNotFound:
-		return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.ListWorkflowRevisionsInternalServerError:
-		return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return nil, NewAPIError(methodName, 0, err)
-	}
+	return intercept(ctx, op.interceptors, &Request{Operation: methodName, Params: &params}, func(ctx context.Context, rq *Request) (*v1.ListWorkflowRevisionsOK, error) {
+		params, err := requestParams[v1.ListWorkflowRevisionsParams](rq)
+		if err != nil {
+			return nil, err
+		}
+		res, err := op.client.ListWorkflowRevisions(ctx, *params)
+		if err != nil {
+			return nil, NewAPIError(methodName, 0, err)
+		}
+
+		switch r := res.(type) {
+		case *v1.ListWorkflowRevisionsOK:
+			return r, nil
+		case *v1.ListWorkflowRevisionsBadRequest:
+			return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.ListWorkflowRevisionsUnauthorized:
+			return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.ListWorkflowRevisionsPaymentRequired:
+			return nil, NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
+		case *v1.ListWorkflowRevisionsForbidden:
+			return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.ListWorkflowRevisionsNotFound:
+			return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.ListWorkflowRevisionsInternalServerError:
+			return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return nil, NewAPIError(methodName, 0, err)
+		}
+	})
 }
 
 func (op *revisionOp) Read(ctx context.Context, workflowID string, revisionNumber int) (*v1.GetWorkflowRevisionsOKRevision, error) {
 	const methodName = "Revision.Read"
 
-	res, err := op.client.GetWorkflowRevisions(ctx, v1.GetWorkflowRevisionsParams{
-		ID:         workflowID,
-		RevisionId: revisionNumber,
+	return intercept(ctx, op.interceptors, &Request{Operation: methodName, Params: &v1.GetWorkflowRevisionsParams{ID: workflowID, RevisionId: revisionNumber}}, func(ctx context.Context, rq *Request) (*v1.GetWorkflowRevisionsOKRevision, error) {
+		params, err := requestParams[v1.GetWorkflowRevisionsParams](rq)
+		if err != nil {
+			return nil, err
+		}
+		res, err := op.client.GetWorkflowRevisions(ctx, *params)
+		if err != nil {
+			return nil, NewAPIError(methodName, 0, err)
+		}
+
+		switch r := res.(type) {
+		case *v1.GetWorkflowRevisionsOK:
+			return &r.Revision, nil
+		case *v1.GetWorkflowRevisionsBadRequest:
+			return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.GetWorkflowRevisionsUnauthorized:
+			return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.GetWorkflowRevisionsPaymentRequired:
+			return nil, NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
+		case *v1.GetWorkflowRevisionsForbidden:
+			return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.GetWorkflowRevisionsNotFound:
+			return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.GetWorkflowRevisionsInternalServerError:
+			return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return nil, NewAPIError(methodName, 0, err)
+		}
 	})
-	if err != nil {
-		return nil, NewAPIError(methodName, 0, err)
-	}
-
-	switch r := res.(type) {
-	case *v1.GetWorkflowRevisionsOK:
-		return &r.Revision, nil
-	case *v1.GetWorkflowRevisionsBadRequest:
-		return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.GetWorkflowRevisionsUnauthorized:
-		return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.GetWorkflowRevisionsPaymentRequired:
-		return nil, NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
-	case *v1.GetWorkflowRevisionsForbidden:
-		return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.GetWorkflowRevisionsNotFound:
-		return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.GetWorkflowRevisionsInternalServerError:
-		return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return nil, NewAPIError(methodName, 0, err)
-	}
 }
 
 func (op *revisionOp) UpdateAlias(ctx context.Context, workflowID string, revisionNumber int, req v1.UpdateWorkflowRevisionAliasReq) (*v1.UpdateWorkflowRevisionAliasOKRevision, error) {
 	const methodName = "Revision.UpdateAlias"
 
-	res, err := op.client.UpdateWorkflowRevisionAlias(ctx, &req, v1.UpdateWorkflowRevisionAliasParams{
-		ID:         workflowID,
-		RevisionId: revisionNumber,
+	return intercept(ctx, op.interceptors, &Request{Operation: methodName, Body: &req, Params: &v1.UpdateWorkflowRevisionAliasParams{ID: workflowID, RevisionId: revisionNumber}}, func(ctx context.Context, rq *Request) (*v1.UpdateWorkflowRevisionAliasOKRevision, error) {
+		body, err := requestBody[v1.UpdateWorkflowRevisionAliasReq](rq)
+		if err != nil {
+			return nil, err
+		}
+		params, err := requestParams[v1.UpdateWorkflowRevisionAliasParams](rq)
+		if err != nil {
+			return nil, err
+		}
+		res, err := op.client.UpdateWorkflowRevisionAlias(ctx, body, *params)
+		if err != nil {
+			return nil, NewAPIError(methodName, 0, err)
+		}
+
+		switch r := res.(type) {
+		case *v1.UpdateWorkflowRevisionAliasOK:
+			return &r.Revision, nil
+		case *v1.UpdateWorkflowRevisionAliasBadRequest:
+			return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.UpdateWorkflowRevisionAliasUnauthorized:
+			return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.UpdateWorkflowRevisionAliasPaymentRequired:
+			return nil, NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
+		case *v1.UpdateWorkflowRevisionAliasForbidden:
+			return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.UpdateWorkflowRevisionAliasNotFound:
+			return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.UpdateWorkflowRevisionAliasConflict:
+			return nil, NewAPIError(methodName, http.StatusConflict, errors.New(r.Message))
+		case *v1.UpdateWorkflowRevisionAliasInternalServerError:
+			return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return nil, NewAPIError(methodName, 0, err)
+		}
 	})
-	if err != nil {
-		return nil, NewAPIError(methodName, 0, err)
-	}
-
-	switch r := res.(type) {
-	case *v1.UpdateWorkflowRevisionAliasOK:
-		return &r.Revision, nil
-	case *v1.UpdateWorkflowRevisionAliasBadRequest:
-		return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.UpdateWorkflowRevisionAliasUnauthorized:
-		return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.UpdateWorkflowRevisionAliasPaymentRequired:
-		return nil, NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
-	case *v1.UpdateWorkflowRevisionAliasForbidden:
-		return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.UpdateWorkflowRevisionAliasNotFound:
-		return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.UpdateWorkflowRevisionAliasConflict:
-		return nil, NewAPIError(methodName, http.StatusConflict, errors.New(r.Message))
-	case *v1.UpdateWorkflowRevisionAliasInternalServerError:
-		return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return nil, NewAPIError(methodName, 0, err)
-	}
 }
 
 func (op *revisionOp) DeleteAlias(ctx context.Context, workflowID string, revisionNumber int) error {
 	const methodName = "Revision.DeleteAlias"
 
-	res, err := op.client.DeleteWorkflowRevisionAlias(ctx, v1.DeleteWorkflowRevisionAliasParams{
-		ID:         workflowID,
-		RevisionId: revisionNumber,
+	return interceptErr(ctx, op.interceptors, &Request{Operation: methodName, Params: &v1.DeleteWorkflowRevisionAliasParams{ID: workflowID, RevisionId: revisionNumber}}, func(ctx context.Context, rq *Request) error {
+		params, err := requestParams[v1.DeleteWorkflowRevisionAliasParams](rq)
+		if err != nil {
+			return err
+		}
+		res, err := op.client.DeleteWorkflowRevisionAlias(ctx, *params)
+		if err != nil {
+			return NewAPIError(methodName, 0, err)
+		}
+
+		switch r := res.(type) {
+		case *v1.DeleteWorkflowRevisionAliasOK:
+			return nil
+		case *v1.DeleteWorkflowRevisionAliasBadRequest:
+			return NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.DeleteWorkflowRevisionAliasUnauthorized:
+			return NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.DeleteWorkflowRevisionAliasPaymentRequired:
+			return NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
+		case *v1.DeleteWorkflowRevisionAliasForbidden:
+			return NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.DeleteWorkflowRevisionAliasNotFound:
+			return NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.DeleteWorkflowRevisionAliasInternalServerError:
+			return NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return NewAPIError(methodName, 0, err)
+		}
 	})
-	if err != nil {
-		return NewAPIError(methodName, 0, err)
-	}
-
-	switch r := res.(type) {
-	case *v1.DeleteWorkflowRevisionAliasOK:
-		return nil
-	case *v1.DeleteWorkflowRevisionAliasBadRequest:
-		return NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.DeleteWorkflowRevisionAliasUnauthorized:
-		return NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.DeleteWorkflowRevisionAliasPaymentRequired:
-		return NewAPIError(methodName, http.StatusPaymentRequired, errors.New(r.Message))
-	case *v1.DeleteWorkflowRevisionAliasForbidden:
-		return NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.DeleteWorkflowRevisionAliasNotFound:
-		return NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.DeleteWorkflowRevisionAliasInternalServerError:
-		return NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return NewAPIError(methodName, 0, err)
-	}
 }
@@ -0,0 +1,67 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCron_Next(t *testing.T) {
+	// 2025-01-01 is a Wednesday
+	from := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, 1, 1, 10, 45, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2025, 1, 2, 10, 30, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 * 1", time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"5,10 11-12 * jan *", time.Date(2025, 1, 1, 11, 5, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := scheduler.ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.Next(from))
+		})
+	}
+}
+
+func TestCron_Next_location(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	c, err := scheduler.ParseCron("0 3 * * *")
+	require.NoError(t, err)
+	next := c.Next(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).In(tokyo))
+	assert.Equal(t, time.Date(2025, 1, 1, 18, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestParseCron_invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		_, err := scheduler.ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
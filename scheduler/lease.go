@@ -0,0 +1,151 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// Lease 複数のスケジューラのうち1つだけが実行を開始するための排他
+type Lease interface {
+	// Acquire acquires the lease or renews it if already held, and reports whether it is held.
+	// It is called before every evaluation of the schedule.
+	Acquire(ctx context.Context) (bool, error)
+	// Release gives up the lease if held
+	Release(ctx context.Context) error
+}
+
+// FileLease 共有ファイルシステム上のファイルによるLease
+//
+// ファイルには保持者と期限を記録する。保持者が期限までに更新しなければ、他のスケジューラが取得できる。
+type FileLease struct {
+	path   string
+	holder string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+var _ Lease = (*FileLease)(nil)
+
+// fileLeaseState リースファイルの内容
+type fileLeaseState struct {
+	Holder  string    `json:"holder"`
+	Expires time.Time `json:"expires"`
+}
+
+// lockRetryInterval リースファイルの読み書きを排他するロックファイルの再試行間隔
+const lockRetryInterval = 10 * time.Millisecond
+
+// NewFileLease returns a lease held by holder, e.g. the host name, for ttl after each Acquire.
+// ttl must be longer than the poll interval of the scheduler.
+func NewFileLease(path, holder string, ttl time.Duration) *FileLease {
+	return &FileLease{path: path, holder: holder, ttl: ttl, now: time.Now}
+}
+
+// Acquire implements Lease
+func (l *FileLease) Acquire(ctx context.Context) (bool, error) {
+	unlock, err := l.lock(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	now := l.now()
+	state, err := l.read()
+	if err != nil {
+		return false, err
+	}
+	if state.Holder != "" && state.Holder != l.holder && now.Before(state.Expires) {
+		return false, nil
+	}
+	return true, l.write(fileLeaseState{Holder: l.holder, Expires: now.Add(l.ttl)})
+}
+
+// Release implements Lease
+func (l *FileLease) Release(ctx context.Context) error {
+	unlock, err := l.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := l.read()
+	if err != nil || state.Holder != l.holder {
+		return err
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("scheduler: %w", err)
+	}
+	return nil
+}
+
+// lock creates the lock file exclusively, removing one left by a crashed process
+func (l *FileLease) lock(ctx context.Context) (func(), error) {
+	name := l.path + ".lock"
+	for {
+		f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) //nolint:gosec
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(name) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("scheduler: %w", err)
+		}
+		if info, err := os.Stat(name); err == nil && l.now().Sub(info.ModTime()) > l.ttl {
+			_ = os.Remove(name)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+func (l *FileLease) read() (fileLeaseState, error) {
+	var state fileLeaseState
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return state, fmt.Errorf("scheduler: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("scheduler: %s: %w", l.path, err)
+	}
+	return state, nil
+}
+
+func (l *FileLease) write(state fileLeaseState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("scheduler: %w", err)
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("scheduler: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("scheduler: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,427 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scheduler starts executions on cron schedules from the client side, as the Workflows
+// API has no scheduling endpoint.
+//
+// A schedule table lists the entries, each with a cron expression, the workflow to start and a
+// text/template producing its arguments. Scheduler.Run evaluates the table until the context is
+// canceled, records every fire time in a Store, and starts an execution only while holding the
+// Lease, so that several schedulers can run for availability without double starts.
+//
+//	entries, err := scheduler.LoadTable(data)
+//	s, err := scheduler.New(client, entries,
+//		scheduler.WithStore(scheduler.NewFileStore("/var/lib/workflows/schedule.log")),
+//		scheduler.WithLease(scheduler.NewFileLease("/shared/workflows.lease", hostname, time.Minute)))
+//	err = s.Run(ctx)
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"text/template"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// MisfirePolicy 予定時刻を猶予以上過ぎてから評価された(スケジューラが停止していた等)場合の振る舞い
+type MisfirePolicy string
+
+const (
+	// MisfireRunOnce 逃した予定時刻のうち最後の1回だけ実行する。既定値
+	MisfireRunOnce MisfirePolicy = "run-once"
+	// MisfireSkip 逃した予定時刻は実行せず、次の予定時刻を待つ
+	MisfireSkip MisfirePolicy = "skip"
+	// MisfireRunAll 逃した予定時刻を全て実行する。ただし古いものはWithMaxCatchUpの件数を超えた分が捨てられる
+	MisfireRunAll MisfirePolicy = "run-all"
+)
+
+// Entry スケジュール表の1行
+type Entry struct {
+	// Name エントリの名前。表の中で一意である必要がある
+	Name string `json:"name"`
+	// Schedule ParseCronが受け付けるcron式
+	Schedule string `json:"schedule"`
+	// Timezone Scheduleを解釈するタイムゾーン。空の場合はtime.Local
+	Timezone   string `json:"timezone,omitempty"`
+	WorkflowID string `json:"workflowId"`
+	// Alias 実行するリビジョンのエイリアス。RevisionとAliasが空の場合は最新のリビジョン
+	Alias    string `json:"alias,omitempty"`
+	Revision int    `json:"revision,omitempty"`
+	// Args 実行の引数となるJSONを生成するtext/template。TemplateDataを受け取る
+	Args    string        `json:"args,omitempty"`
+	Misfire MisfirePolicy `json:"misfire,omitempty"`
+}
+
+// TemplateData Entry.Argsのテンプレートに渡す値
+type TemplateData struct {
+	Name        string
+	WorkflowID  string
+	ScheduledAt time.Time
+	Now         time.Time
+}
+
+// LoadTable reads a schedule table in YAML or JSON: a list of entries, or an object whose
+// entries key holds the list.
+func LoadTable(data []byte) ([]Entry, error) {
+	var entries []Entry
+	if err := yaml.Unmarshal(data, &entries); err == nil {
+		return entries, nil
+	}
+	var table struct {
+		Entries []Entry `json:"entries"`
+	}
+	if err := yaml.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("scheduler: %w", err)
+	}
+	return table.Entries, nil
+}
+
+// entry 検証済みのEntry
+type entry struct {
+	Entry
+	cron *Cron
+	loc  *time.Location
+	args *template.Template
+}
+
+// Scheduler スケジュール表に従って実行を開始する
+type Scheduler struct {
+	client  *workflows.Client
+	entries []*entry
+	store   Store
+	lease   Lease
+	logger  *slog.Logger
+	now     func() time.Time
+	grace   time.Duration
+	catchUp int
+	poll    time.Duration
+	// started 記録がないエントリの起点。起動前の予定時刻は実行しない
+	started time.Time
+}
+
+// Option Schedulerの設定
+type Option func(*Scheduler)
+
+// WithStore sets where the fire times are recorded. Defaults to a MemoryStore.
+func WithStore(store Store) Option {
+	return func(s *Scheduler) { s.store = store }
+}
+
+// WithLease sets the lease to hold before starting executions. By default no lease is taken.
+func WithLease(lease Lease) Option {
+	return func(s *Scheduler) { s.lease = lease }
+}
+
+// WithLogger sets the logger. Defaults to slog.Default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Scheduler) { s.logger = logger }
+}
+
+// WithClock sets the function giving the current time. Defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(s *Scheduler) { s.now = now }
+}
+
+// WithMisfireGrace sets how late a fire time may be evaluated and still count as on time. Defaults to a minute.
+func WithMisfireGrace(d time.Duration) Option {
+	return func(s *Scheduler) { s.grace = d }
+}
+
+// WithMaxCatchUp sets how many missed fire times MisfireRunAll starts at most. Defaults to 10.
+func WithMaxCatchUp(n int) Option {
+	return func(s *Scheduler) { s.catchUp = n }
+}
+
+// WithPollInterval sets the longest sleep between evaluations of Run. Defaults to 30 seconds.
+func WithPollInterval(d time.Duration) Option {
+	return func(s *Scheduler) { s.poll = d }
+}
+
+var entryNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+
+// New validates the entries and returns a scheduler for them
+func New(client *workflows.Client, entries []Entry, opts ...Option) (*Scheduler, error) {
+	s := &Scheduler{
+		client:  client,
+		store:   &MemoryStore{},
+		logger:  slog.Default(),
+		now:     time.Now,
+		grace:   time.Minute,
+		catchUp: 10,
+		poll:    30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.started = s.now()
+
+	names := map[string]bool{}
+	for _, e := range entries {
+		if !entryNamePattern.MatchString(e.Name) || len(e.Name) > 48 {
+			return nil, fmt.Errorf("scheduler: invalid entry name %q (up to 48 letters, digits, _ and -)", e.Name)
+		}
+		if names[e.Name] {
+			return nil, fmt.Errorf("scheduler: duplicate entry %q", e.Name)
+		}
+		names[e.Name] = true
+		if e.WorkflowID == "" {
+			return nil, fmt.Errorf("scheduler: entry %q: no workflow ID", e.Name)
+		}
+		switch e.Misfire {
+		case "":
+			e.Misfire = MisfireRunOnce
+		case MisfireRunOnce, MisfireSkip, MisfireRunAll:
+		default:
+			return nil, fmt.Errorf("scheduler: entry %q: unknown misfire policy %q", e.Name, e.Misfire)
+		}
+
+		ent := &entry{Entry: e, loc: time.Local}
+		var err error
+		if ent.cron, err = parseCron(e.Schedule); err != nil {
+			return nil, fmt.Errorf("scheduler: entry %q: %w", e.Name, err)
+		}
+		if e.Timezone != "" {
+			if ent.loc, err = time.LoadLocation(e.Timezone); err != nil {
+				return nil, fmt.Errorf("scheduler: entry %q: %w", e.Name, err)
+			}
+		}
+		if e.Args != "" {
+			ent.args, err = template.New(e.Name).Funcs(template.FuncMap{"json": toJSON}).Option("missingkey=error").Parse(e.Args)
+			if err != nil {
+				return nil, fmt.Errorf("scheduler: entry %q: %w", e.Name, err)
+			}
+		}
+		s.entries = append(s.entries, ent)
+	}
+	return s, nil
+}
+
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// Run evaluates the schedule until ctx is canceled, sleeping until the next fire time or
+// the poll interval in between. The lease, if any, is released on return.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.lease != nil {
+		defer func() { _ = s.lease.Release(context.WithoutCancel(ctx)) }()
+	}
+	for {
+		if _, err := s.RunPending(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.ErrorContext(ctx, "scheduler evaluation failed", slog.Any("error", err))
+		}
+
+		wait := s.poll
+		if next := s.Next(); !next.IsZero() {
+			wait = min(wait, max(time.Second, next.Sub(s.now())))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Next returns the earliest fire time after now over all the entries
+func (s *Scheduler) Next() time.Time {
+	now := s.now()
+	var next time.Time
+	for _, e := range s.entries {
+		if t := e.cron.Next(now.In(e.loc)); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
+
+// RunPending evaluates the schedule once: for every fire time passed since the last record of
+// each entry, it starts an execution or records why not, following the misfire policy.
+// Nothing happens while another scheduler holds the lease.
+func (s *Scheduler) RunPending(ctx context.Context) ([]Record, error) {
+	if s.lease != nil {
+		held, err := s.lease.Acquire(ctx)
+		if err != nil || !held {
+			return nil, err
+		}
+	}
+
+	now := s.now()
+	var ret []Record
+	for _, e := range s.entries {
+		last, err := s.store.LastRun(ctx, e.Name)
+		if err != nil {
+			return ret, err
+		}
+		if last.IsZero() {
+			last = s.started
+		}
+
+		var due []time.Time
+		var missed, dropped int
+		for t := e.cron.Next(last.In(e.loc)); !t.IsZero() && !t.After(now); t = e.cron.Next(t) {
+			due = append(due, t)
+			if now.Sub(t) <= s.grace {
+				continue
+			}
+			// keep the memory bounded after a long outage; only the latest missed ones can be started.
+			// The missed ones come first, and those within the grace are never dropped
+			if missed++; missed > s.catchUp+1 {
+				due, missed, dropped = due[1:], missed-1, dropped+1
+			}
+		}
+		for _, r := range s.plan(e, due, dropped, now) {
+			if r.Skipped == "" {
+				s.trigger(ctx, e, &r)
+			}
+			if err := s.store.Record(ctx, r); err != nil {
+				return ret, err
+			}
+			ret = append(ret, r)
+		}
+	}
+	return ret, nil
+}
+
+// plan decides which of the due fire times to start following the misfire policy. dropped is
+// the number of fire times before due which were already given up on.
+// The returned records without Skipped are to be started.
+func (s *Scheduler) plan(e *entry, due []time.Time, dropped int, now time.Time) []Record {
+	var ret []Record
+	var missed []time.Time
+	for _, t := range due {
+		if now.Sub(t) <= s.grace {
+			ret = append(ret, Record{Entry: e.Name, WorkflowID: e.WorkflowID, ScheduledAt: t})
+		} else {
+			missed = append(missed, t)
+		}
+	}
+	if len(missed) == 0 {
+		return ret
+	}
+
+	var run []time.Time
+	switch e.Misfire {
+	case MisfireRunOnce:
+		run = missed[len(missed)-1:]
+	case MisfireRunAll:
+		run = missed[max(0, len(missed)-s.catchUp):]
+	}
+	var catchUp []Record
+	if skipped := len(missed) - len(run); skipped > 0 {
+		// a single record marks all the skipped fire times as handled
+		catchUp = append(catchUp, Record{
+			Entry:       e.Name,
+			WorkflowID:  e.WorkflowID,
+			ScheduledAt: missed[skipped-1],
+			Skipped:     fmt.Sprintf("misfire: %d fire times missed", dropped+skipped),
+		})
+	}
+	for _, t := range run {
+		catchUp = append(catchUp, Record{Entry: e.Name, WorkflowID: e.WorkflowID, ScheduledAt: t})
+	}
+	return append(catchUp, ret...)
+}
+
+// trigger starts the execution for the record unless the workflow is locked by a running one
+func (s *Scheduler) trigger(ctx context.Context, e *entry, r *Record) {
+	r.TriggeredAt = s.now()
+	logger := s.logger.With(slog.String("entry", e.Name), slog.String("workflow", e.WorkflowID), slog.Time("scheduled_at", r.ScheduledAt))
+
+	if active, err := s.lockedBy(ctx, e.WorkflowID); err != nil {
+		r.Error = err.Error()
+		logger.ErrorContext(ctx, "unable to check the concurrency of the workflow", slog.Any("error", err))
+		return
+	} else if active != "" {
+		r.Skipped = "workflow is locked by execution " + active
+		logger.InfoContext(ctx, "skipped", slog.String("reason", r.Skipped))
+		return
+	}
+
+	req := v1.CreateExecutionReq{Name: v1.NewOptString(executionName(e.Name, r.ScheduledAt))}
+	if e.Alias != "" {
+		req.RevisionAlias = v1.NewOptString(e.Alias)
+	}
+	if e.Revision > 0 {
+		req.RevisionId = v1.NewOptInt(e.Revision)
+	}
+	if e.args != nil {
+		var buf bytes.Buffer
+		data := TemplateData{Name: e.Name, WorkflowID: e.WorkflowID, ScheduledAt: r.ScheduledAt, Now: r.TriggeredAt}
+		if err := e.args.Execute(&buf, data); err != nil {
+			r.Error = err.Error()
+			logger.ErrorContext(ctx, "unable to render the arguments", slog.Any("error", err))
+			return
+		}
+		if !json.Valid(buf.Bytes()) {
+			r.Error = "arguments are not valid JSON: " + buf.String()
+			logger.ErrorContext(ctx, "unable to render the arguments", slog.String("error", r.Error))
+			return
+		}
+		req.Args = v1.NewOptString(buf.String())
+	}
+
+	exec, err := s.client.Executions.Create(ctx, e.WorkflowID, v1.NewOptCreateExecutionReq(req))
+	if err != nil {
+		r.Error = err.Error()
+		logger.ErrorContext(ctx, "unable to start the execution", slog.Any("error", err))
+		return
+	}
+	r.ExecutionID = exec.ExecutionId
+	logger.InfoContext(ctx, "started", slog.String("execution", exec.ExecutionId))
+}
+
+// lockedBy returns the ID of an active execution if the workflow runs in the lock mode and one exists
+func (s *Scheduler) lockedBy(ctx context.Context, workflowID string) (string, error) {
+	wf, err := s.client.Workflows.Read(ctx, workflowID)
+	if err != nil {
+		return "", err
+	}
+	if wf.ConcurrencyMode.Value != v1.GetWorkflowOKWorkflowConcurrencyModeLock {
+		return "", nil
+	}
+	res, err := s.client.Executions.List(ctx, v1.ListExecutionParams{
+		ID:        workflowID,
+		Page:      v1.NewOptInt(1),
+		PageLimit: v1.NewOptInt(100),
+		Order:     v1.NewOptListExecutionOrder(v1.ListExecutionOrderDesc),
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, ex := range res.Executions {
+		switch ex.Status {
+		case v1.ListExecutionOKExecutionsItemStatusQueued, v1.ListExecutionOKExecutionsItemStatusRunning, v1.ListExecutionOKExecutionsItemStatusCanceling:
+			return ex.ExecutionId, nil
+		}
+	}
+	return "", nil
+}
+
+// executionName names the execution after the entry and the fire time in UTC, e.g. nightly-20250101-0300
+func executionName(entry string, scheduledAt time.Time) string {
+	return entry + "-" + scheduledAt.UTC().Format("20060102-1504")
+}
@@ -0,0 +1,248 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler_test
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/scheduler"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var quiet = scheduler.WithLogger(slog.New(slog.DiscardHandler))
+
+// clock テストで進める時計
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+const testTable = `
+entries:
+- name: nightly
+  schedule: "0 3 * * *"
+  timezone: UTC
+  workflowId: wf
+  alias: stable
+  args: '{"date": {{ .ScheduledAt.Format "2006-01-02" | json }}}'
+`
+
+func TestScheduler_RunPending(t *testing.T) {
+	entries, err := scheduler.LoadTable([]byte(testTable))
+	require.NoError(t, err)
+
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", ConcurrencyMode: "parallel"}.Get(), nil)
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnCreate(mock.Anything, "wf", v1.NewOptCreateExecutionReq(v1.CreateExecutionReq{
+		Name:          v1.NewOptString("nightly-20250102-0300"),
+		RevisionAlias: v1.NewOptString("stable"),
+		Args:          v1.NewOptString(`{"date": "2025-01-02"}`),
+	})).Return(workflowsmock.Execution{ID: "e1"}.Created(), nil).Once()
+
+	c := &clock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	store := &scheduler.MemoryStore{}
+	s, err := scheduler.New(&workflows.Client{Workflows: wfs, Executions: executions}, entries, quiet, scheduler.WithClock(c.Now), scheduler.WithStore(store))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC), s.Next())
+
+	records, err := s.RunPending(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	c.now = time.Date(2025, 1, 2, 3, 0, 20, 0, time.UTC)
+	records, err = s.RunPending(t.Context())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "e1", records[0].ExecutionID)
+	assert.Equal(t, c.now, records[0].TriggeredAt)
+
+	// already recorded
+	records, err = s.RunPending(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	assert.Len(t, store.Records(), 1)
+}
+
+func TestScheduler_RunPending_misfire(t *testing.T) {
+	last := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 1, 4, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		policy    scheduler.MisfirePolicy
+		started   int
+		skipped   string
+		scheduled []time.Time
+	}{
+		{scheduler.MisfireSkip, 0, "misfire: 3 fire times missed", []time.Time{last.AddDate(0, 0, 3)}},
+		{scheduler.MisfireRunOnce, 1, "misfire: 2 fire times missed", []time.Time{last.AddDate(0, 0, 2), last.AddDate(0, 0, 3)}},
+		{scheduler.MisfireRunAll, 3, "", []time.Time{last.AddDate(0, 0, 1), last.AddDate(0, 0, 2), last.AddDate(0, 0, 3)}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			wfs := workflowsmock.NewWorkflowAPI(t)
+			wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf"}.Get(), nil).Maybe()
+			executions := workflowsmock.NewExecutionAPI(t)
+			if tt.started > 0 {
+				executions.OnCreate(mock.Anything, "wf", mock.Anything).Return(workflowsmock.Execution{ID: "e"}.Created(), nil).Times(tt.started)
+			}
+
+			store := &scheduler.MemoryStore{}
+			require.NoError(t, store.Record(t.Context(), scheduler.Record{Entry: "daily", WorkflowID: "wf", ScheduledAt: last}))
+			entries := []scheduler.Entry{{Name: "daily", Schedule: "0 3 * * *", Timezone: "UTC", WorkflowID: "wf", Misfire: tt.policy}}
+			s, err := scheduler.New(&workflows.Client{Workflows: wfs, Executions: executions}, entries,
+				quiet, scheduler.WithClock(func() time.Time { return now }), scheduler.WithStore(store))
+			require.NoError(t, err)
+
+			records, err := s.RunPending(t.Context())
+			require.NoError(t, err)
+			var scheduled []time.Time
+			for _, r := range records {
+				scheduled = append(scheduled, r.ScheduledAt)
+			}
+			assert.Equal(t, tt.scheduled, scheduled)
+			if tt.skipped != "" {
+				assert.Equal(t, tt.skipped, records[0].Skipped)
+			}
+		})
+	}
+}
+
+func TestScheduler_RunPending_catchUpLimit(t *testing.T) {
+	last := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := last.Add(3 * time.Hour)
+
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", ConcurrencyMode: "parallel"}.Get(), nil)
+	executions := workflowsmock.NewExecutionAPI(t)
+	// 2 caught up and the 31 fire times within the grace
+	executions.OnCreate(mock.Anything, "wf", mock.Anything).Return(workflowsmock.Execution{ID: "e"}.Created(), nil).Times(33)
+
+	store := &scheduler.MemoryStore{}
+	require.NoError(t, store.Record(t.Context(), scheduler.Record{Entry: "minutely", WorkflowID: "wf", ScheduledAt: last}))
+	entries := []scheduler.Entry{{Name: "minutely", Schedule: "* * * * *", Timezone: "UTC", WorkflowID: "wf", Misfire: scheduler.MisfireRunAll}}
+	s, err := scheduler.New(&workflows.Client{Workflows: wfs, Executions: executions}, entries, quiet,
+		scheduler.WithClock(func() time.Time { return now }), scheduler.WithStore(store),
+		scheduler.WithMisfireGrace(30*time.Minute), scheduler.WithMaxCatchUp(2))
+	require.NoError(t, err)
+
+	records, err := s.RunPending(t.Context())
+	require.NoError(t, err)
+	require.Len(t, records, 34)
+	assert.Equal(t, "misfire: 147 fire times missed", records[0].Skipped)
+	assert.Equal(t, now.Add(-33*time.Minute), records[0].ScheduledAt)
+	assert.Equal(t, now.Add(-32*time.Minute), records[1].ScheduledAt)
+	assert.Equal(t, now.Add(-31*time.Minute), records[2].ScheduledAt)
+	assert.Equal(t, now.Add(-30*time.Minute), records[3].ScheduledAt)
+	assert.Equal(t, now, records[33].ScheduledAt)
+	for _, r := range records[1:] {
+		assert.Empty(t, r.Skipped)
+	}
+}
+
+func TestScheduler_RunPending_lock(t *testing.T) {
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", ConcurrencyMode: "lock"}.Get(), nil)
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.ExecutionList(
+		workflowsmock.Execution{ID: "e0", Status: "Succeeded"},
+		workflowsmock.Execution{ID: "e1", Status: "Running"},
+	), nil)
+
+	c := &clock{now: time.Date(2025, 1, 1, 2, 59, 0, 0, time.UTC)}
+	entries := []scheduler.Entry{{Name: "daily", Schedule: "0 3 * * *", Timezone: "UTC", WorkflowID: "wf"}}
+	s, err := scheduler.New(&workflows.Client{Workflows: wfs, Executions: executions}, entries, quiet, scheduler.WithClock(c.Now))
+	require.NoError(t, err)
+
+	c.now = c.now.Add(time.Minute)
+	records, err := s.RunPending(t.Context())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "workflow is locked by execution e1", records[0].Skipped)
+	assert.Empty(t, records[0].ExecutionID)
+}
+
+func TestNew_invalid(t *testing.T) {
+	tests := map[string]scheduler.Entry{
+		"invalid entry name":     {Name: "a b", Schedule: "@daily", WorkflowID: "wf"},
+		"no workflow ID":         {Name: "a", Schedule: "@daily"},
+		"must have 5 fields":     {Name: "a", Schedule: "* *", WorkflowID: "wf"},
+		"unknown misfire policy": {Name: "a", Schedule: "@daily", WorkflowID: "wf", Misfire: "later"},
+		"unknown time zone":      {Name: "a", Schedule: "@daily", WorkflowID: "wf", Timezone: "Nowhere/Nothing"},
+	}
+	for want, e := range tests {
+		_, err := scheduler.New(&workflows.Client{}, []scheduler.Entry{e})
+		assert.ErrorContains(t, err, want)
+	}
+}
+
+func TestFileLease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lease")
+	a := scheduler.NewFileLease(path, "a", time.Minute)
+	b := scheduler.NewFileLease(path, "b", time.Minute)
+
+	held, err := a.Acquire(t.Context())
+	require.NoError(t, err)
+	assert.True(t, held)
+	held, err = b.Acquire(t.Context())
+	require.NoError(t, err)
+	assert.False(t, held)
+	held, err = a.Acquire(t.Context())
+	require.NoError(t, err)
+	assert.True(t, held, "renewal")
+
+	require.NoError(t, b.Release(t.Context()))
+	require.NoError(t, a.Release(t.Context()))
+	held, err = b.Acquire(t.Context())
+	require.NoError(t, err)
+	assert.True(t, held)
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.log")
+	store := scheduler.NewFileStore(path)
+	last, err := store.LastRun(t.Context(), "daily")
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	at := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Record(t.Context(), scheduler.Record{Entry: "daily", ScheduledAt: at, ExecutionID: "e1"}))
+	require.NoError(t, store.Record(t.Context(), scheduler.Record{Entry: "hourly", ScheduledAt: at.Add(time.Hour)}))
+	last, err = store.LastRun(t.Context(), "daily")
+	require.NoError(t, err)
+	assert.True(t, at.Equal(last))
+	records, err := store.Records()
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	// another store loads the file once and then keeps the last runs in memory
+	reopened := scheduler.NewFileStore(path)
+	last, err = reopened.LastRun(t.Context(), "hourly")
+	require.NoError(t, err)
+	assert.True(t, at.Add(time.Hour).Equal(last))
+	require.NoError(t, os.WriteFile(path, []byte("not json\n"), 0o600))
+	require.NoError(t, reopened.Record(t.Context(), scheduler.Record{Entry: "daily", ScheduledAt: at.Add(24 * time.Hour)}))
+	last, err = reopened.LastRun(t.Context(), "daily")
+	require.NoError(t, err)
+	assert.True(t, at.Add(24*time.Hour).Equal(last))
+}
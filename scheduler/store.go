@@ -0,0 +1,191 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Record スケジューラが1つの予定時刻に対して行ったこと
+type Record struct {
+	Entry       string    `json:"entry"`
+	WorkflowID  string    `json:"workflowId"`
+	ScheduledAt time.Time `json:"scheduledAt"`
+	TriggeredAt time.Time `json:"triggeredAt"`
+	// ExecutionID 開始した実行のID
+	ExecutionID string `json:"executionId,omitempty"`
+	// Skipped 実行を開始しなかった理由
+	Skipped string `json:"skipped,omitempty"`
+	// Error 実行の開始に失敗した場合のエラー
+	Error string `json:"error,omitempty"`
+}
+
+// Store 記録の保存先。HA構成では全てのスケジューラから同じ内容が見える必要がある
+type Store interface {
+	// LastRun returns the latest ScheduledAt recorded for the entry, or the zero time if none
+	LastRun(ctx context.Context, entry string) (time.Time, error)
+	Record(ctx context.Context, r Record) error
+}
+
+// MemoryStore プロセス内にだけ記録を保持するStore
+type MemoryStore struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// LastRun implements Store
+func (s *MemoryStore) LastRun(_ context.Context, entry string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return lastRun(s.records, entry), nil
+}
+
+// Record implements Store
+func (s *MemoryStore) Record(_ context.Context, r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, r)
+	return nil
+}
+
+// Records returns the records in the order they were made
+func (s *MemoryStore) Records() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.records)
+}
+
+// FileStore 記録を1行1レコードのJSONとしてファイルに追記するStore
+//
+// 最終実行時刻は最初のLastRunでファイルから読み込んだ後はメモリ上で更新するため、
+// 同じファイルに他のプロセスが追記した記録は反映されない。
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+	// lastRuns エントリごとの最新のScheduledAt。読み込むまではnil
+	lastRuns map[string]time.Time
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore returns a Store appending to the file, which is created on the first record
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// LastRun implements Store. The file is read only on the first call.
+func (s *FileStore) LastRun(_ context.Context, entry string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastRuns == nil {
+		records, err := s.readRecords()
+		if err != nil {
+			return time.Time{}, err
+		}
+		s.lastRuns = map[string]time.Time{}
+		for _, r := range records {
+			s.updateLastRun(r)
+		}
+	}
+	return s.lastRuns[entry], nil
+}
+
+// Record implements Store
+func (s *FileStore) Record(_ context.Context, r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("scheduler: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("scheduler: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("scheduler: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("scheduler: %w", err)
+	}
+	if s.lastRuns != nil {
+		s.updateLastRun(r)
+	}
+	return nil
+}
+
+// updateLastRun records r in lastRuns. The lock must be held.
+func (s *FileStore) updateLastRun(r Record) {
+	if r.ScheduledAt.After(s.lastRuns[r.Entry]) {
+		s.lastRuns[r.Entry] = r.ScheduledAt
+	}
+}
+
+// Records reads all the records in the file
+func (s *FileStore) Records() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readRecords()
+}
+
+// readRecords reads all the records in the file. The lock must be held.
+func (s *FileStore) readRecords() ([]Record, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("scheduler: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	var ret []Record
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("scheduler: %s:%d: %w", s.path, line, err)
+		}
+		ret = append(ret, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scheduler: %w", err)
+	}
+	return ret, nil
+}
+
+func lastRun(records []Record, entry string) time.Time {
+	var last time.Time
+	for _, r := range records {
+		if r.Entry == entry && r.ScheduledAt.After(last) {
+			last = r.ScheduledAt
+		}
+	}
+	return last
+}
@@ -0,0 +1,162 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// ServicePrincipalID サービスプリンシパルのID
+//
+// APIは文字列と数値のどちらの形でも受け付け・返すため、どちらも正確な10進の文字列として保持する。
+// 数値の形は53ビットを超えると精度が落ちるので、APIへ送る際は常に文字列の形を使う。
+// ゼロ値は「設定しない」を表す。
+// UpdateWorkflowReqにはServicePrincipalIdがないため、作成後のワークフローのサービスプリンシパルは変更できない。
+type ServicePrincipalID struct {
+	id string
+}
+
+// ServicePrincipalIDValue 生成された...ServicePrincipalId型に共通するメソッド
+type ServicePrincipalIDValue interface {
+	GetString() (string, bool)
+	GetFloat64() (float64, bool)
+}
+
+// ServicePrincipalIDVariant 生成された...ServicePrincipalId型へのポインタ
+type ServicePrincipalIDVariant[T any] interface {
+	*T
+	SetString(v string)
+}
+
+// maxExactFloat float64で整数を正確に表せる上限(2^53)
+const maxExactFloat = 1 << 53
+
+// ParseServicePrincipalID parses an ID given as a string. A numeric ID is normalized, e.g. "0123" becomes "123".
+func ParseServicePrincipalID(s string) (ServicePrincipalID, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ServicePrincipalID{}, NewError("empty service principal ID", nil)
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ServicePrincipalIDFromInt64(n), nil
+	}
+	if strings.ContainsFunc(s, func(r rune) bool { return r < '!' || r > '~' }) {
+		return ServicePrincipalID{}, NewError(fmt.Sprintf("invalid service principal ID %q", s), nil)
+	}
+	return ServicePrincipalID{id: s}, nil
+}
+
+// ServicePrincipalIDFromInt64 returns the ID of the number
+func ServicePrincipalIDFromInt64(n int64) ServicePrincipalID {
+	return ServicePrincipalID{id: strconv.FormatInt(n, 10)}
+}
+
+// ServicePrincipalIDOf converts any of the generated ...ServicePrincipalId values.
+// It fails for a number that is not an integer or too large to be exact in float64.
+func ServicePrincipalIDOf(v ServicePrincipalIDValue) (ServicePrincipalID, error) {
+	if s, ok := v.GetString(); ok {
+		return ParseServicePrincipalID(s)
+	}
+	if f, ok := v.GetFloat64(); ok {
+		if f != math.Trunc(f) || math.Abs(f) > maxExactFloat {
+			return ServicePrincipalID{}, NewError(fmt.Sprintf("service principal ID %s is not exact", strconv.FormatFloat(f, 'g', -1, 64)), nil)
+		}
+		return ServicePrincipalIDFromInt64(int64(f)), nil
+	}
+	return ServicePrincipalID{}, nil
+}
+
+// ConvertServicePrincipalID converts the ID to one of the generated ...ServicePrincipalId types, always in the string form
+//
+//	sp := workflows.ConvertServicePrincipalID[v1.CreateWorkflowReqServicePrincipalId](id)
+func ConvertServicePrincipalID[T any, P ServicePrincipalIDVariant[T]](id ServicePrincipalID) T {
+	var ret T
+	P(&ret).SetString(id.id)
+	return ret
+}
+
+// IsZero reports whether the ID is unset
+func (id ServicePrincipalID) IsZero() bool { return id.id == "" }
+
+// String returns the ID as a decimal string, or "" if unset
+func (id ServicePrincipalID) String() string { return id.id }
+
+// Int64 returns the ID as a number if it is one
+func (id ServicePrincipalID) Int64() (int64, bool) {
+	n, err := strconv.ParseInt(id.id, 10, 64)
+	return n, err == nil
+}
+
+// CreateWorkflowReq returns the value of CreateWorkflowReq.ServicePrincipalId, which is unset for the zero ID
+func (id ServicePrincipalID) CreateWorkflowReq() v1.OptCreateWorkflowReqServicePrincipalId {
+	if id.IsZero() {
+		return v1.OptCreateWorkflowReqServicePrincipalId{}
+	}
+	return v1.NewOptCreateWorkflowReqServicePrincipalId(ConvertServicePrincipalID[v1.CreateWorkflowReqServicePrincipalId](id))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (id ServicePrincipalID) MarshalText() ([]byte, error) { return []byte(id.id), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text is the zero ID.
+func (id *ServicePrincipalID) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*id = ServicePrincipalID{}
+		return nil
+	}
+	v, err := ParseServicePrincipalID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = v
+	return nil
+}
+
+// UnmarshalJSON accepts a string, a number or null. A number is read from its digits, so no precision is lost.
+func (id *ServicePrincipalID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*id = ServicePrincipalID{}
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return id.UnmarshalText([]byte(s))
+	}
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return NewError(fmt.Sprintf("invalid service principal ID %s", data), nil)
+	}
+	*id = ServicePrincipalIDFromInt64(n)
+	return nil
+}
+
+// WorkflowServicePrincipalID returns the service principal of the workflow, the zero ID if it has none
+func WorkflowServicePrincipalID(wf *v1.GetWorkflowOKWorkflow) (ServicePrincipalID, error) {
+	if sp, ok := wf.ServicePrincipalId.Get(); ok {
+		return ServicePrincipalIDOf(sp)
+	}
+	return ServicePrincipalID{}, nil
+}
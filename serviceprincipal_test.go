@@ -0,0 +1,92 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServicePrincipalID(t *testing.T) {
+	id, err := workflows.ParseServicePrincipalID(" 0113000000001 ")
+	require.NoError(t, err)
+	assert.Equal(t, "113000000001", id.String())
+	n, ok := id.Int64()
+	assert.True(t, ok)
+	assert.Equal(t, int64(113000000001), n)
+
+	id, err = workflows.ParseServicePrincipalID("sp-abc")
+	require.NoError(t, err)
+	_, ok = id.Int64()
+	assert.False(t, ok)
+
+	for _, s := range []string{"", "a b"} {
+		_, err := workflows.ParseServicePrincipalID(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestServicePrincipalIDOf(t *testing.T) {
+	id, err := workflows.ServicePrincipalIDOf(v1.NewFloat64GetWorkflowOKWorkflowServicePrincipalId(113000000001))
+	require.NoError(t, err)
+	assert.Equal(t, "113000000001", id.String())
+
+	id, err = workflows.ServicePrincipalIDOf(v1.NewStringListWorkflowOKWorkflowsItemServicePrincipalId("113000000001"))
+	require.NoError(t, err)
+	assert.Equal(t, "113000000001", id.String())
+
+	_, err = workflows.ServicePrincipalIDOf(v1.NewFloat64UpdateWorkflowOKWorkflowServicePrincipalId(1 << 60))
+	assert.EqualError(t, err, "workflows: service principal ID 1.152921504606847e+18 is not exact")
+	_, err = workflows.ServicePrincipalIDOf(v1.NewFloat64UpdateWorkflowOKWorkflowServicePrincipalId(1.5))
+	assert.Error(t, err)
+
+	id, err = workflows.ServicePrincipalIDOf(v1.GetWorkflowOKWorkflowServicePrincipalId{})
+	require.NoError(t, err)
+	assert.True(t, id.IsZero())
+}
+
+func TestConvertServicePrincipalID(t *testing.T) {
+	id := workflows.ServicePrincipalIDFromInt64(1152921504606846977)
+	sp := workflows.ConvertServicePrincipalID[v1.CreateWorkflowReqServicePrincipalId](id)
+	assert.Equal(t, v1.NewStringCreateWorkflowReqServicePrincipalId("1152921504606846977"), sp)
+
+	req := id.CreateWorkflowReq()
+	assert.True(t, req.IsSet())
+	assert.False(t, workflows.ServicePrincipalID{}.CreateWorkflowReq().IsSet())
+}
+
+func TestServicePrincipalID_JSON(t *testing.T) {
+	var v struct {
+		ID workflows.ServicePrincipalID `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"id": 1152921504606846977}`), &v))
+	assert.Equal(t, "1152921504606846977", v.ID.String(), "no precision lost")
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "sp-abc"}`), &v))
+	assert.Equal(t, "sp-abc", v.ID.String())
+	require.NoError(t, json.Unmarshal([]byte(`{"id": null}`), &v))
+	assert.True(t, v.ID.IsZero())
+	assert.Error(t, json.Unmarshal([]byte(`{"id": 1.5}`), &v))
+
+	data, err := json.Marshal(struct {
+		ID workflows.ServicePrincipalID `json:"id"`
+	}{workflows.ServicePrincipalIDFromInt64(42)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "42"}`, string(data))
+}
@@ -0,0 +1,436 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package submit starts executions according to the ConcurrencyMode of the workflow.
+//
+// ExecutionAPI.Create behaves differently by mode: a parallel workflow runs every execution at once
+// up to the concurrent-execution limit (Q-2002), a lock workflow refuses an execution while another
+// is active, and a queue workflow keeps it Queued until the earlier ones finish. A Submitter reads
+// the mode and applies a Policy instead: wait for the lock, reject fast, or hold the execution in a
+// local queue and retry while the API answers 409 Conflict or Q-2002. Submissions through the same
+// Submitter are started in the order they were made, one workflow at a time.
+//
+//	s := submit.New(client, submit.WithProgress(func(_ context.Context, p submit.Progress) {
+//		log.Printf("waiting: %d ahead locally, %d active", p.LocalPosition, p.Active)
+//	}))
+//	res, err := s.Submit(ctx, workflowID, v1.NewOptCreateExecutionReq(req))
+//	if res.Queue != nil {
+//		log.Printf("%d executions ahead, about %s", res.Queue.Position, res.Queue.EstimatedWait)
+//	}
+package submit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// Policy 実行をすぐに作成できない場合の振る舞い
+type Policy string
+
+const (
+	// PolicyAuto ワークフローのConcurrencyModeに応じて選ぶ。lockはPolicyWait、それ以外はPolicyEnqueue
+	PolicyAuto Policy = ""
+	// PolicyWait 作動中の実行がなくなるのを待ってから作成する。lockのワークフロー向け
+	PolicyWait Policy = "wait"
+	// PolicyReject 待たずにエラーを返す。lockのワークフローで作動中の実行があればAPIを呼ばずにErrBusyを返す
+	PolicyReject Policy = "reject"
+	// PolicyEnqueue ローカルのキューで順番を待ち、APIが409やQ-2002で拒否する間は再試行する
+	PolicyEnqueue Policy = "enqueue"
+)
+
+// ParsePolicy parses the name of a policy; "auto" and "" are PolicyAuto
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case PolicyAuto, PolicyWait, PolicyReject, PolicyEnqueue:
+		return p, nil
+	case "auto":
+		return PolicyAuto, nil
+	}
+	return "", fmt.Errorf("submit: unknown policy %q (auto, wait, reject or enqueue)", s)
+}
+
+const (
+	// DefaultInterval 作動中の実行の確認や再試行の既定の間隔
+	DefaultInterval = 5 * time.Second
+	// pageLimit 実行を列挙する際の1ページの件数
+	pageLimit = 100
+	// estimateSamples 待ち時間の見積もりに使う終了した実行の数
+	estimateSamples = 20
+)
+
+// ErrBusy lockのワークフローに作動中の実行があることを表す
+var ErrBusy = errors.New("submit: workflow has an active execution")
+
+// activeStatuses 作動中とみなす実行の状態
+var activeStatuses = []v1.ListExecutionOKExecutionsItemStatus{
+	v1.ListExecutionOKExecutionsItemStatusQueued,
+	v1.ListExecutionOKExecutionsItemStatusRunning,
+	v1.ListExecutionOKExecutionsItemStatusCanceling,
+}
+
+// Progress 待っている間の状況。WithProgressの関数に渡される
+type Progress struct {
+	WorkflowID string
+	Mode       v1.GetWorkflowOKWorkflowConcurrencyMode
+	Policy     Policy
+	// LocalPosition このSubmitterで先に待っている提出の数
+	LocalPosition int
+	// Active ワークフローで作動中の実行の数。確認していない場合は-1
+	Active int
+	// Attempts これまでに実行の作成を試みた回数
+	Attempts int
+	Waited   time.Duration
+	// Err 直前の作成が拒否された理由。Executionが設定されている場合は待ち状況を取得できなかった理由
+	Err error
+	// Execution 作成した実行。作成後にqueueの待ち状況を取得できなかったことを伝える場合のみ設定される
+	Execution *v1.CreateExecutionCreatedExecution
+}
+
+// QueueStatus queueのワークフローで実行が待っている状況
+type QueueStatus struct {
+	ExecutionID string
+	// Position 先に作成され、作動中の実行の数。0の場合は次に実行される(または実行中)
+	Position int
+	// Ahead 先に作成され、作動中の実行のID。作成の古い順
+	Ahead []string
+	// EstimatedWait 直近に終了した実行の所要時間から見積もった、実行が始まるまでの時間。見積もれない場合は0
+	EstimatedWait time.Duration
+}
+
+// Result Submitの結果
+type Result struct {
+	Execution *v1.CreateExecutionCreatedExecution
+	Mode      v1.GetWorkflowOKWorkflowConcurrencyMode
+	// Policy 実際に適用したPolicy。PolicyAutoは解決済み
+	Policy   Policy
+	Attempts int
+	Waited   time.Duration
+	// Queue queueのワークフローの場合の、作成した時点の待ち状況。取得できなかった場合はnil
+	Queue *QueueStatus
+}
+
+// Option Submitterの設定
+type Option func(*Submitter)
+
+// WithPolicy sets the policy. Defaults to PolicyAuto.
+func WithPolicy(p Policy) Option {
+	return func(s *Submitter) { s.policy = p }
+}
+
+// WithInterval sets how often the active executions are checked and a rejected creation is retried. Defaults to DefaultInterval.
+func WithInterval(d time.Duration) Option {
+	return func(s *Submitter) { s.interval = d }
+}
+
+// WithMaxWait sets how long a submission waits at most. By default it waits until the context is done.
+func WithMaxWait(d time.Duration) Option {
+	return func(s *Submitter) { s.maxWait = d }
+}
+
+// WithProgress sets a function called every interval while a submission waits
+func WithProgress(fn func(context.Context, Progress)) Option {
+	return func(s *Submitter) { s.progress = fn }
+}
+
+// WithClock sets the function giving the current time. Defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(s *Submitter) { s.now = now }
+}
+
+// ticket ローカルのキューで順番を待つ1件の提出
+type ticket struct {
+	// ready 順番が来たら閉じられる
+	ready chan struct{}
+}
+
+// Submitter ConcurrencyModeに応じて実行を作成する
+type Submitter struct {
+	client   *workflows.Client
+	policy   Policy
+	interval time.Duration
+	maxWait  time.Duration
+	progress func(context.Context, Progress)
+	now      func() time.Time
+
+	mu     sync.Mutex
+	queues map[string][]*ticket
+}
+
+// New returns a submitter creating executions through the client
+func New(client *workflows.Client, opts ...Option) *Submitter {
+	s := &Submitter{client: client, interval: DefaultInterval, now: time.Now, queues: map[string][]*ticket{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Submit creates an execution of the workflow following the policy. It returns ErrBusy or the rejection
+// of the API under PolicyReject, and the last rejection once WithMaxWait has passed.
+//
+// When err is nil, res.Execution is always set. Once the execution is created Submit does not fail:
+// if the queue status cannot be read afterwards, res.Queue is left nil and the error is reported to the
+// WithProgress function, so that a caller never retries a submission which was in fact created.
+func (s *Submitter) Submit(ctx context.Context, workflowID string, req v1.OptCreateExecutionReq) (*Result, error) {
+	wf, err := s.client.Workflows.Read(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Mode: ModeOf(wf), Policy: s.policy}
+	if res.Policy == PolicyAuto {
+		res.Policy = PolicyEnqueue
+		if res.Mode == v1.GetWorkflowOKWorkflowConcurrencyModeLock {
+			res.Policy = PolicyWait
+		}
+	}
+	if s.maxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.maxWait)
+		defer cancel()
+	}
+
+	started := s.now()
+	progress := Progress{WorkflowID: workflowID, Mode: res.Mode, Policy: res.Policy, Active: -1}
+	if res.Policy != PolicyReject {
+		t := s.enqueue(workflowID)
+		defer s.leave(workflowID, t)
+		if err := s.await(ctx, t, &progress, started); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		lock := res.Mode == v1.GetWorkflowOKWorkflowConcurrencyModeLock
+		if lock && res.Policy != PolicyEnqueue {
+			active, err := s.active(ctx, workflowID)
+			if err != nil {
+				return nil, err
+			}
+			progress.Active = len(active)
+			if len(active) > 0 && res.Policy == PolicyReject {
+				return nil, fmt.Errorf("%w: %s is %s", ErrBusy, active[0].ExecutionId, active[0].Status)
+			}
+		}
+
+		// Active is only checked for lock workflows waiting for the lock
+		if progress.Active <= 0 {
+			res.Attempts++
+			e, err := s.client.Executions.Create(ctx, workflowID, req)
+			if err == nil {
+				res.Execution, res.Waited = e, s.now().Sub(started)
+				if res.Mode == v1.GetWorkflowOKWorkflowConcurrencyModeQueue {
+					if res.Queue, err = s.QueueStatus(ctx, workflowID, e.ExecutionId); err != nil && s.progress != nil {
+						progress.Attempts, progress.Waited = res.Attempts, res.Waited
+						progress.Err, progress.Execution = err, e
+						s.progress(ctx, progress)
+					}
+				}
+				return res, nil
+			}
+			if ctx.Err() != nil && progress.Err != nil {
+				// the deadline ran out during the request; report the last rejection instead
+				return nil, giveUp(ctx, workflowID, progress)
+			}
+			if !IsRejection(err) || res.Policy == PolicyReject {
+				return nil, err
+			}
+			progress.Err = err
+		}
+
+		progress.Attempts, progress.Waited = res.Attempts, s.now().Sub(started)
+		if s.progress != nil {
+			s.progress(ctx, progress)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, giveUp(ctx, workflowID, progress)
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+// IsRejection reports whether the API refused to create the execution for now: 409 Conflict or Q-2002
+func IsRejection(err error) bool {
+	return workflows.IsConflictError(err) || errors.Is(err, workflows.ErrConcurrentExecutionsExceeded)
+}
+
+// ModeOf returns the concurrency mode of the workflow, which is parallel if not set
+func ModeOf(wf *v1.GetWorkflowOKWorkflow) v1.GetWorkflowOKWorkflowConcurrencyMode {
+	if mode, ok := wf.ConcurrencyMode.Get(); ok {
+		return mode
+	}
+	return v1.GetWorkflowOKWorkflowConcurrencyModeParallel
+}
+
+func giveUp(ctx context.Context, workflowID string, p Progress) error {
+	err := ctx.Err()
+	if p.Err != nil {
+		err = fmt.Errorf("%w: %w", err, p.Err)
+	}
+	return fmt.Errorf("submit: workflow %s: gave up after %d attempts: %w", workflowID, p.Attempts, err)
+}
+
+// enqueue puts a ticket at the tail of the local queue of the workflow
+func (s *Submitter) enqueue(workflowID string) *ticket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := &ticket{ready: make(chan struct{})}
+	s.queues[workflowID] = append(s.queues[workflowID], t)
+	if len(s.queues[workflowID]) == 1 {
+		close(t.ready)
+	}
+	return t
+}
+
+// leave removes the ticket and lets the next one go
+func (s *Submitter) leave(workflowID string, t *ticket) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := s.queues[workflowID]
+	i := slices.Index(q, t)
+	if i < 0 {
+		return
+	}
+	q = slices.Delete(q, i, i+1)
+	if len(q) == 0 {
+		delete(s.queues, workflowID)
+		return
+	}
+	s.queues[workflowID] = q
+	if i == 0 {
+		close(q[0].ready)
+	}
+}
+
+// position returns the number of tickets ahead of t
+func (s *Submitter) position(workflowID string, t *ticket) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return max(slices.Index(s.queues[workflowID], t), 0)
+}
+
+// await waits until the ticket is at the head of the local queue, reporting the progress every interval
+func (s *Submitter) await(ctx context.Context, t *ticket, p *Progress, started time.Time) error {
+	for {
+		select {
+		case <-t.ready:
+			p.LocalPosition = 0
+			return nil
+		case <-ctx.Done():
+			return giveUp(ctx, p.WorkflowID, *p)
+		case <-time.After(s.interval):
+			p.LocalPosition, p.Waited = s.position(p.WorkflowID, t), s.now().Sub(started)
+			if s.progress != nil {
+				s.progress(ctx, *p)
+			}
+		}
+	}
+}
+
+// Waiting returns the number of submissions in the local queue of the workflow, including the one being created
+func (s *Submitter) Waiting(workflowID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queues[workflowID])
+}
+
+// QueueStatus tells how many active executions of the workflow were created before the execution and
+// estimates when it starts from the durations of recently finished executions
+func (s *Submitter) QueueStatus(ctx context.Context, workflowID, executionID string) (*QueueStatus, error) {
+	executions, err := s.list(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	idx := slices.IndexFunc(executions, func(e v1.ListExecutionOKExecutionsItem) bool { return e.ExecutionId == executionID })
+	if idx < 0 {
+		return nil, workflows.NewError(fmt.Sprintf("execution %s not found in workflow %s", executionID, workflowID), nil)
+	}
+
+	now := s.now()
+	avg := averageDuration(executions)
+	ret := &QueueStatus{ExecutionID: executionID}
+	for _, e := range executions[:idx] {
+		if !slices.Contains(activeStatuses, e.Status) {
+			continue
+		}
+		ret.Ahead = append(ret.Ahead, e.ExecutionId)
+		if avg > 0 {
+			remaining := avg
+			if run, ok := e.RunAt.Get(); ok {
+				remaining = max(avg-now.Sub(run), 0)
+			}
+			ret.EstimatedWait += remaining
+		}
+	}
+	ret.Position = len(ret.Ahead)
+	return ret, nil
+}
+
+// averageDuration returns the mean running time of the latest finished executions, or 0 if there are none
+func averageDuration(executions []v1.ListExecutionOKExecutionsItem) time.Duration {
+	var total time.Duration
+	var n int
+	for i := len(executions) - 1; i >= 0 && n < estimateSamples; i-- {
+		e := executions[i]
+		run, ok := e.RunAt.Get()
+		if !ok || slices.Contains(activeStatuses, e.Status) {
+			continue
+		}
+		total += e.UpdatedAt.Sub(run)
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return total / time.Duration(n)
+}
+
+// active returns the active executions of the workflow in ascending order of creation
+func (s *Submitter) active(ctx context.Context, workflowID string) ([]v1.ListExecutionOKExecutionsItem, error) {
+	executions, err := s.list(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(executions, func(e v1.ListExecutionOKExecutionsItem) bool {
+		return !slices.Contains(activeStatuses, e.Status)
+	}), nil
+}
+
+// list returns all the executions of the workflow in ascending order of creation
+func (s *Submitter) list(ctx context.Context, workflowID string) ([]v1.ListExecutionOKExecutionsItem, error) {
+	var ret []v1.ListExecutionOKExecutionsItem
+	for page, seen := 1, 0; ; page++ {
+		res, err := s.client.Executions.List(ctx, v1.ListExecutionParams{
+			ID: workflowID, Page: v1.NewOptInt(page), PageLimit: v1.NewOptInt(pageLimit),
+			Order: v1.NewOptListExecutionOrder(v1.ListExecutionOrderAsc),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, res.Executions...)
+		seen += len(res.Executions)
+		if len(res.Executions) < pageLimit || seen >= res.Total {
+			break
+		}
+	}
+	slices.SortStableFunc(ret, func(a, b v1.ListExecutionOKExecutionsItem) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return ret, nil
+}
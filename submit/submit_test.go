@@ -0,0 +1,244 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package submit_test
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/submit"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+
+// quotaBody 同時実行数の上限を超えた場合のExecution.Createの応答
+const quotaBody = `{"is_ok":false,"Message":"Q-2002 The number of concurrent executions has exceeded."}`
+
+// submitClient modeのワークフローwfを持つClient
+func submitClient(t *testing.T, mode string) (*workflows.Client, *workflowsmock.ExecutionAPI) {
+	executions := workflowsmock.NewExecutionAPI(t)
+	return &workflows.Client{Workflows: workflowMock(t, mode), Executions: executions}, executions
+}
+
+func workflowMock(t *testing.T, mode string) *workflowsmock.WorkflowAPI {
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Name: "wf", ConcurrencyMode: mode}.Get(), nil).Maybe()
+	return wfs
+}
+
+// quotaClient Execution.Createの最初のrejections回に402(Q-2002)を返し、その後は実行e1を作成するサーバーにつないだClient。
+// 戻り値はCreateが呼ばれた回数
+func quotaClient(t *testing.T, mode string, rejections int) (*workflows.Client, *atomic.Int32) {
+	var calls atomic.Int32
+	created, err := (&v1.CreateExecutionCreated{IsOk: true, Execution: *workflowsmock.Execution{
+		ID: "e1", Name: "e1", Workflow: workflowsmock.Workflow{ID: "wf", Name: "wf"}, Args: "{}", Result: "{}", Error: "-",
+	}.Created()}).MarshalJSON()
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/workflows/wf/executions" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if int(calls.Add(1)) <= rejections {
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(quotaBody))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(created)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := workflows.New(workflows.WithHTTPClient(server.Client()), workflows.WithAPIRootURL(server.URL+"/"), workflows.WithRetry(0, 0, 0))
+	require.NoError(t, err)
+	client.Workflows = workflowMock(t, mode)
+	return client, &calls
+}
+
+func options(opts ...submit.Option) []submit.Option {
+	return append([]submit.Option{submit.WithInterval(time.Millisecond), submit.WithClock(func() time.Time { return now })}, opts...)
+}
+
+func TestSubmitter_lock(t *testing.T) {
+	running := workflowsmock.Execution{ID: "e0", Status: "Running", CreatedAt: now.Add(-time.Hour)}
+
+	t.Run("wait", func(t *testing.T) {
+		client, executions := submitClient(t, "lock")
+		executions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.ExecutionList(running), nil).Once()
+		running := running
+		running.Status = "Succeeded"
+		executions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.ExecutionList(running), nil).Once()
+		executions.OnCreate(mock.Anything, "wf", mock.Anything).Return(workflowsmock.Execution{ID: "e1"}.Created(), nil).Once()
+
+		var progress []submit.Progress
+		res, err := submit.New(client, options(submit.WithProgress(func(_ context.Context, p submit.Progress) {
+			progress = append(progress, p)
+		}))...).Submit(t.Context(), "wf", v1.OptCreateExecutionReq{})
+		require.NoError(t, err)
+		assert.Equal(t, "e1", res.Execution.ExecutionId)
+		assert.Equal(t, submit.PolicyWait, res.Policy)
+		assert.Equal(t, 1, res.Attempts)
+		require.Len(t, progress, 1)
+		assert.Equal(t, 1, progress[0].Active)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		client, executions := submitClient(t, "lock")
+		executions.OnList(mock.Anything, mock.Anything).Return(workflowsmock.ExecutionList(running), nil).Once()
+
+		_, err := submit.New(client, options(submit.WithPolicy(submit.PolicyReject))...).Submit(t.Context(), "wf", v1.OptCreateExecutionReq{})
+		assert.ErrorIs(t, err, submit.ErrBusy)
+		assert.EqualError(t, err, "submit: workflow has an active execution: e0 is Running")
+	})
+}
+
+func TestSubmitter_enqueue(t *testing.T) {
+	t.Run("retries while over the limit", func(t *testing.T) {
+		client, calls := quotaClient(t, "parallel", 2)
+
+		res, err := submit.New(client, options()...).Submit(t.Context(), "wf", v1.OptCreateExecutionReq{})
+		require.NoError(t, err)
+		assert.Equal(t, "e1", res.Execution.ExecutionId)
+		assert.Equal(t, submit.PolicyEnqueue, res.Policy)
+		assert.Equal(t, 3, res.Attempts)
+		assert.EqualValues(t, 3, calls.Load())
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		client, calls := quotaClient(t, "parallel", 1)
+
+		_, err := submit.New(client, options(submit.WithPolicy(submit.PolicyReject))...).Submit(t.Context(), "wf", v1.OptCreateExecutionReq{})
+		assert.ErrorIs(t, err, workflows.ErrConcurrentExecutionsExceeded)
+		assert.True(t, submit.IsRejection(err))
+		assert.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		client, _ := quotaClient(t, "", math.MaxInt)
+
+		_, err := submit.New(client, options(submit.WithMaxWait(20*time.Millisecond))...).Submit(t.Context(), "wf", v1.OptCreateExecutionReq{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, workflows.ErrConcurrentExecutionsExceeded)
+	})
+
+	t.Run("in order", func(t *testing.T) {
+		client, executions := submitClient(t, "parallel")
+		release := make(chan struct{})
+		executions.OnCreate(mock.Anything, "wf", mock.Anything).RunAndReturn(func(context.Context, string, v1.OptCreateExecutionReq) (*v1.CreateExecutionCreatedExecution, error) {
+			<-release
+			return workflowsmock.Execution{ID: "e1"}.Created(), nil
+		}).Once()
+		executions.OnCreate(mock.Anything, "wf", mock.Anything).Return(workflowsmock.Execution{ID: "e2"}.Created(), nil).Once()
+
+		var mu sync.Mutex
+		var positions []int
+		s := submit.New(client, options(submit.WithProgress(func(_ context.Context, p submit.Progress) {
+			mu.Lock()
+			defer mu.Unlock()
+			positions = append(positions, p.LocalPosition)
+		}))...)
+
+		first := make(chan *submit.Result)
+		go func() {
+			res, _ := s.Submit(t.Context(), "wf", v1.OptCreateExecutionReq{})
+			first <- res
+		}()
+		require.Eventually(t, func() bool { return s.Waiting("wf") == 1 }, time.Second, time.Millisecond)
+		second := make(chan *submit.Result)
+		go func() {
+			res, _ := s.Submit(t.Context(), "wf", v1.OptCreateExecutionReq{})
+			second <- res
+		}()
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(positions) > 0
+		}, time.Second, time.Millisecond)
+		close(release)
+
+		assert.Equal(t, "e1", (<-first).Execution.ExecutionId)
+		assert.Equal(t, "e2", (<-second).Execution.ExecutionId)
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 1, positions[0])
+		assert.Equal(t, 0, s.Waiting("wf"))
+	})
+}
+
+func TestSubmitter_queue(t *testing.T) {
+	client, executions := submitClient(t, "queue")
+	executions.OnCreate(mock.Anything, "wf", mock.Anything).Return(workflowsmock.Execution{ID: "e4", Status: "Queued"}.Created(), nil).Once()
+
+	// two earlier executions took 10 minutes; e2 has been running for 4 minutes and e3 waits
+	finished := func(id string, created time.Time) v1.ListExecutionOKExecutionsItem {
+		e := workflowsmock.Execution{ID: id, Status: "Succeeded", CreatedAt: created, UpdatedAt: created.Add(10 * time.Minute)}.ListItem()
+		e.RunAt = v1.NewOptDateTime(created)
+		return e
+	}
+	running := workflowsmock.Execution{ID: "e2", Status: "Running", CreatedAt: now.Add(-5 * time.Minute)}.ListItem()
+	running.RunAt = v1.NewOptDateTime(now.Add(-4 * time.Minute))
+	executions.OnList(mock.Anything, mock.Anything).Return(&v1.ListExecutionOK{IsOk: true, Total: 5, Count: 5, Executions: []v1.ListExecutionOKExecutionsItem{
+		workflowsmock.Execution{ID: "e4", Status: "Queued", CreatedAt: now}.ListItem(),
+		workflowsmock.Execution{ID: "e3", Status: "Queued", CreatedAt: now.Add(-time.Minute)}.ListItem(),
+		running,
+		finished("e1", now.Add(-time.Hour)),
+		finished("e0", now.Add(-2*time.Hour)),
+	}}, nil).Once()
+
+	res, err := submit.New(client, options()...).Submit(t.Context(), "wf", v1.OptCreateExecutionReq{})
+	require.NoError(t, err)
+	assert.Equal(t, v1.GetWorkflowOKWorkflowConcurrencyModeQueue, res.Mode)
+	require.NotNil(t, res.Queue)
+	assert.Equal(t, 2, res.Queue.Position)
+	assert.Equal(t, []string{"e2", "e3"}, res.Queue.Ahead)
+	assert.Equal(t, 16*time.Minute, res.Queue.EstimatedWait)
+}
+
+func TestSubmitter_queueStatusFails(t *testing.T) {
+	client, executions := submitClient(t, "queue")
+	executions.OnCreate(mock.Anything, "wf", mock.Anything).Return(workflowsmock.Execution{ID: "e1", Status: "Queued"}.Created(), nil).Once()
+	executions.OnList(mock.Anything, mock.Anything).Return(nil, workflows.NewError("list failed", nil)).Once()
+
+	var progress []submit.Progress
+	res, err := submit.New(client, options(submit.WithProgress(func(_ context.Context, p submit.Progress) {
+		progress = append(progress, p)
+	}))...).Submit(t.Context(), "wf", v1.OptCreateExecutionReq{})
+	require.NoError(t, err, "the execution was created")
+	assert.Equal(t, "e1", res.Execution.ExecutionId)
+	assert.Nil(t, res.Queue)
+	require.Len(t, progress, 1)
+	assert.Equal(t, "e1", progress[0].Execution.ExecutionId)
+	assert.EqualError(t, progress[0].Err, "workflows: list failed")
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := submit.ParsePolicy("auto")
+	require.NoError(t, err)
+	assert.Equal(t, submit.PolicyAuto, p)
+	_, err = submit.ParsePolicy("later")
+	assert.Error(t, err)
+}
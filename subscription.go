@@ -32,113 +32,131 @@ type SubscriptionAPI interface {
 var _ SubscriptionAPI = (*subscriptionOp)(nil)
 
 type subscriptionOp struct {
-	client *v1.Client
+	client       *v1.Client
+	interceptors []Interceptor
 }
 
 func NewSubscriptionOp(client *v1.Client) SubscriptionAPI {
-	return &subscriptionOp{client: client}
+	return NewSubscriptionOpWithConn(NewConn(client))
+}
+
+// NewSubscriptionOpWithConn creates a new SubscriptionAPI sharing the interceptors installed on conn
+func NewSubscriptionOpWithConn(conn *Conn) SubscriptionAPI {
+	return &subscriptionOp{client: conn.client, interceptors: conn.interceptors}
 }
 
 func (op *subscriptionOp) ListPlans(ctx context.Context) (*v1.ListPlansOK, error) {
 	const methodName = "Subscription.ListPlans"
 
-	res, err := op.client.ListPlans(ctx)
-	if err != nil {
-		return nil, NewAPIError(methodName, 0, err)
-	}
-
-	switch r := res.(type) {
-	case *v1.ListPlansOK:
-		return r, nil
-	case *v1.ListPlansBadRequest:
-		return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.ListPlansUnauthorized:
-		return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.ListPlansForbidden:
-		return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.ListPlansNotFound:
-		return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.ListPlansInternalServerError:
-		return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return nil, NewAPIError(methodName, 0, errors.New("unknown error"))
-	}
+	return intercept(ctx, op.interceptors, &Request{Operation: methodName}, func(ctx context.Context, _ *Request) (*v1.ListPlansOK, error) {
+		res, err := op.client.ListPlans(ctx)
+		if err != nil {
+			return nil, NewAPIError(methodName, 0, err)
+		}
+
+		switch r := res.(type) {
+		case *v1.ListPlansOK:
+			return r, nil
+		case *v1.ListPlansBadRequest:
+			return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.ListPlansUnauthorized:
+			return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.ListPlansForbidden:
+			return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.ListPlansNotFound:
+			return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.ListPlansInternalServerError:
+			return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return nil, NewAPIError(methodName, 0, errors.New("unknown error"))
+		}
+	})
 }
 
 func (op *subscriptionOp) Read(ctx context.Context) (*v1.GetSubscriptionOK, error) {
 	const methodName = "Subscription.Read"
 
-	res, err := op.client.GetSubscription(ctx)
-	if err != nil {
-		return nil, NewAPIError(methodName, 0, err)
-	}
-
-	switch r := res.(type) {
-	case *v1.GetSubscriptionOK:
-		return r, nil
-	case *v1.GetSubscriptionBadRequest:
-		return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.GetSubscriptionUnauthorized:
-		return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.GetSubscriptionForbidden:
-		return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.GetSubscriptionNotFound:
-		return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.GetSubscriptionInternalServerError:
-		return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return nil, NewAPIError(methodName, 0, errors.New("unknown error"))
-	}
+	return intercept(ctx, op.interceptors, &Request{Operation: methodName}, func(ctx context.Context, _ *Request) (*v1.GetSubscriptionOK, error) {
+		res, err := op.client.GetSubscription(ctx)
+		if err != nil {
+			return nil, NewAPIError(methodName, 0, err)
+		}
+
+		switch r := res.(type) {
+		case *v1.GetSubscriptionOK:
+			return r, nil
+		case *v1.GetSubscriptionBadRequest:
+			return nil, NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.GetSubscriptionUnauthorized:
+			return nil, NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.GetSubscriptionForbidden:
+			return nil, NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.GetSubscriptionNotFound:
+			return nil, NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.GetSubscriptionInternalServerError:
+			return nil, NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return nil, NewAPIError(methodName, 0, errors.New("unknown error"))
+		}
+	})
 }
 
 func (op *subscriptionOp) Create(ctx context.Context, req v1.CreateSubscriptionReq) error {
 	const methodName = "Subscription.Create"
 
-	res, err := op.client.CreateSubscription(ctx, &req)
-	if err != nil {
-		return NewAPIError(methodName, 0, err)
-	}
-
-	switch r := res.(type) {
-	case *v1.CreateSubscriptionNoContent:
-		return nil
-	case *v1.CreateSubscriptionBadRequest:
-		return NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.CreateSubscriptionUnauthorized:
-		return NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.CreateSubscriptionForbidden:
-		return NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.CreateSubscriptionNotFound:
-		return NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.CreateSubscriptionInternalServerError:
-		return NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return NewAPIError(methodName, 0, errors.New("unknown error"))
-	}
+	return interceptErr(ctx, op.interceptors, &Request{Operation: methodName, Body: &req}, func(ctx context.Context, rq *Request) error {
+		body, err := requestBody[v1.CreateSubscriptionReq](rq)
+		if err != nil {
+			return err
+		}
+		res, err := op.client.CreateSubscription(ctx, body)
+		if err != nil {
+			return NewAPIError(methodName, 0, err)
+		}
+
+		switch r := res.(type) {
+		case *v1.CreateSubscriptionNoContent:
+			return nil
+		case *v1.CreateSubscriptionBadRequest:
+			return NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.CreateSubscriptionUnauthorized:
+			return NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.CreateSubscriptionForbidden:
+			return NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.CreateSubscriptionNotFound:
+			return NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.CreateSubscriptionInternalServerError:
+			return NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return NewAPIError(methodName, 0, errors.New("unknown error"))
+		}
+	})
 }
 
 func (op *subscriptionOp) Delete(ctx context.Context) error {
 	const methodName = "Subscription.Delete"
 
-	res, err := op.client.DeleteSubscription(ctx)
-	if err != nil {
-		return NewAPIError(methodName, 0, err)
-	}
-
-	switch r := res.(type) {
-	case *v1.DeleteSubscriptionNoContent:
-		return nil
-	case *v1.DeleteSubscriptionBadRequest:
-		return NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
-	case *v1.DeleteSubscriptionUnauthorized:
-		return NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
-	case *v1.DeleteSubscriptionForbidden:
-		return NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
-	case *v1.DeleteSubscriptionNotFound:
-		return NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
-	case *v1.DeleteSubscriptionInternalServerError:
-		return NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
-	default:
-		return NewAPIError(methodName, 0, errors.New("unknown error"))
-	}
+	return interceptErr(ctx, op.interceptors, &Request{Operation: methodName}, func(ctx context.Context, _ *Request) error {
+		res, err := op.client.DeleteSubscription(ctx)
+		if err != nil {
+			return NewAPIError(methodName, 0, err)
+		}
+
+		switch r := res.(type) {
+		case *v1.DeleteSubscriptionNoContent:
+			return nil
+		case *v1.DeleteSubscriptionBadRequest:
+			return NewAPIError(methodName, http.StatusBadRequest, errors.New(r.Message))
+		case *v1.DeleteSubscriptionUnauthorized:
+			return NewAPIError(methodName, http.StatusUnauthorized, errors.New(r.Message))
+		case *v1.DeleteSubscriptionForbidden:
+			return NewAPIError(methodName, http.StatusForbidden, errors.New(r.Message))
+		case *v1.DeleteSubscriptionNotFound:
+			return NewAPIError(methodName, http.StatusNotFound, errors.New(r.Message))
+		case *v1.DeleteSubscriptionInternalServerError:
+			return NewAPIError(methodName, http.StatusInternalServerError, errors.New(r.Message))
+		default:
+			return NewAPIError(methodName, 0, errors.New("unknown error"))
+		}
+	})
 }
@@ -20,7 +20,6 @@ import (
 	"os"
 	"testing"
 
-	"github.com/sacloud/packages-go/testutil"
 	"github.com/sacloud/saclient-go"
 	"github.com/sacloud/workflows-api-go"
 	v1 "github.com/sacloud/workflows-api-go/apis/v1"
@@ -30,8 +29,7 @@ import (
 
 func TestMain(m *testing.M) {
 	// NOTE: 課金プランが設定されていないと多くのAPIが402を返すため、E2Eテストの前に設定しておく。
-	isE2ETest := os.Getenv("SAKURA_ACCESS_TOKEN") != "" && os.Getenv("SAKURA_ACCESS_TOKEN_SECRET") != ""
-	if isE2ETest {
+	if isE2ETest() {
 		ctx := context.Background()
 
 		var theClient saclient.Client
@@ -59,14 +57,9 @@ func TestMain(m *testing.M) {
 }
 
 func TestSubscriptionAPI(t *testing.T) {
-	testutil.PreCheckEnvsFunc("SAKURA_ACCESS_TOKEN", "SAKURA_ACCESS_TOKEN_SECRET")(t)
-
+	client := newAcceptanceClient(t)
 	ctx := t.Context()
 
-	var theClient saclient.Client
-	client, err := workflows.NewClient(&theClient)
-	require.NoError(t, err)
-
 	subscriptionAPI := workflows.NewSubscriptionOp(client)
 
 	// ListPlans
@@ -0,0 +1,268 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// AddTags adds the tags the workflow does not have yet and returns the resulting tags.
+// Nothing is sent if the workflow already has all of them.
+func (c *Client) AddTags(ctx context.Context, workflowID string, tags ...string) ([]string, error) {
+	return c.modifyTags(ctx, workflowID, func(cur []string) []string {
+		for _, tag := range tags {
+			if !slices.Contains(cur, tag) {
+				cur = append(cur, tag)
+			}
+		}
+		return cur
+	})
+}
+
+// RemoveTags removes the tags from the workflow and returns the resulting tags.
+// Nothing is sent if the workflow has none of them.
+func (c *Client) RemoveTags(ctx context.Context, workflowID string, tags ...string) ([]string, error) {
+	return c.modifyTags(ctx, workflowID, func(cur []string) []string {
+		return slices.DeleteFunc(cur, func(tag string) bool { return slices.Contains(tags, tag) })
+	})
+}
+
+// SetTags replaces the tags of the workflow, dropping duplicates, and returns the resulting tags.
+// Nothing is sent if the workflow already has exactly these tags.
+func (c *Client) SetTags(ctx context.Context, workflowID string, tags ...string) ([]string, error) {
+	return c.modifyTags(ctx, workflowID, func([]string) []string {
+		var ret []string
+		for _, tag := range tags {
+			if !slices.Contains(ret, tag) {
+				ret = append(ret, tag)
+			}
+		}
+		return ret
+	})
+}
+
+// modifyTags applies modify to the tags of the workflow through UpdateIfUnchanged and sends only the tags,
+// so that neither the other attributes nor a concurrent change of the tags are overwritten
+func (c *Client) modifyTags(ctx context.Context, workflowID string, modify func([]string) []string) ([]string, error) {
+	updated, err := c.UpdateIfUnchanged(ctx, workflowID, time.Time{}, func(wf *v1.GetWorkflowOKWorkflow, req *v1.UpdateWorkflowReq) error {
+		var cur []string
+		for _, tag := range wf.Tags {
+			cur = append(cur, tag.Name)
+		}
+		tags := modify(slices.Clone(cur))
+		if slices.Contains(tags, "") {
+			return NewError("empty tag", nil)
+		}
+		if slices.Equal(tags, cur) {
+			return nil
+		}
+		req.Tags = []v1.UpdateWorkflowReqTagsItem{}
+		for _, tag := range tags {
+			req.Tags = append(req.Tags, v1.UpdateWorkflowReqTagsItem{Name: tag})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for _, tag := range updated.Tags {
+		ret = append(ret, tag.Name)
+	}
+	return ret, nil
+}
+
+// selectorOp セレクタの1つの条件の種類
+type selectorOp int
+
+const (
+	selectorEquals selectorOp = iota
+	selectorNotEquals
+	selectorExists
+	selectorNotExists
+)
+
+// selectorRequirement セレクタの1つの条件
+type selectorRequirement struct {
+	key   string
+	op    selectorOp
+	value string
+}
+
+// Selector タグを"key=value"の形のラベルとみなして、ワークフローを選ぶ条件
+//
+// "env=prod,team!=infra"のように条件をカンマで区切り、全ての条件を満たすワークフローを選ぶ。
+// 条件は次のいずれか。"="を含まないタグは値が空のラベルとみなす。
+//
+//	key=value  (key==value も可) keyの値がvalueのタグがある
+//	key!=value keyの値がvalueのタグがない(keyのタグがなくてもよい)
+//	key        keyのタグがある
+//	!key       keyのタグがない
+//
+// ゼロ値は全てのワークフローを選ぶ。
+type Selector struct {
+	reqs []selectorRequirement
+}
+
+// ParseSelector parses a selector such as "env=prod,team!=infra"
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	if strings.TrimSpace(s) == "" {
+		return sel, nil
+	}
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		var r selectorRequirement
+		switch {
+		case strings.Contains(term, "!="):
+			r.key, r.value, _ = strings.Cut(term, "!=")
+			r.op = selectorNotEquals
+		case strings.Contains(term, "=="):
+			r.key, r.value, _ = strings.Cut(term, "==")
+		case strings.Contains(term, "="):
+			r.key, r.value, _ = strings.Cut(term, "=")
+		case strings.HasPrefix(term, "!"):
+			r.key, r.op = term[1:], selectorNotExists
+		default:
+			r.key, r.op = term, selectorExists
+		}
+		r.key, r.value = strings.TrimSpace(r.key), strings.TrimSpace(r.value)
+		if r.key == "" || strings.ContainsAny(r.key, "!=") || strings.ContainsAny(r.value, "!=") {
+			return Selector{}, NewError(fmt.Sprintf("invalid selector term %q", term), nil)
+		}
+		sel.reqs = append(sel.reqs, r)
+	}
+	return sel, nil
+}
+
+// MustParseSelector is like ParseSelector but panics on an invalid selector
+func MustParseSelector(s string) Selector {
+	sel, err := ParseSelector(s)
+	if err != nil {
+		panic(err)
+	}
+	return sel
+}
+
+// Empty reports whether the selector selects every workflow
+func (s Selector) Empty() bool { return len(s.reqs) == 0 }
+
+// String returns the selector in the syntax of ParseSelector
+func (s Selector) String() string {
+	terms := make([]string, 0, len(s.reqs))
+	for _, r := range s.reqs {
+		switch r.op {
+		case selectorEquals:
+			terms = append(terms, r.key+"="+r.value)
+		case selectorNotEquals:
+			terms = append(terms, r.key+"!="+r.value)
+		case selectorExists:
+			terms = append(terms, r.key)
+		case selectorNotExists:
+			terms = append(terms, "!"+r.key)
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+// Matches reports whether the tags satisfy every term of the selector
+func (s Selector) Matches(tags []string) bool {
+	for _, r := range s.reqs {
+		var hasKey, hasValue bool
+		for _, tag := range tags {
+			key, value, _ := strings.Cut(tag, "=")
+			if key == r.key {
+				hasKey = true
+				hasValue = hasValue || value == r.value
+			}
+		}
+		var ok bool
+		switch r.op {
+		case selectorEquals:
+			ok = hasValue
+		case selectorNotEquals:
+			ok = !hasValue
+		case selectorExists:
+			ok = hasKey
+		case selectorNotExists:
+			ok = !hasKey
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// SelectWorkflows lists all the workflows page by page and returns those the selector matches
+func (c *Client) SelectWorkflows(ctx context.Context, sel Selector) ([]v1.ListWorkflowOKWorkflowsItem, error) {
+	var ret []v1.ListWorkflowOKWorkflowsItem
+	for page, seen := 1, 0; ; page++ {
+		res, err := c.Workflows.List(ctx, v1.ListWorkflowParams{Page: v1.NewOptInt(page), PageLimit: v1.NewOptInt(bulkPageLimit)})
+		if err != nil {
+			return nil, err
+		}
+		for _, wf := range res.Workflows {
+			tags := make([]string, 0, len(wf.Tags))
+			for _, tag := range wf.Tags {
+				tags = append(tags, tag.Name)
+			}
+			if sel.Matches(tags) {
+				ret = append(ret, wf)
+			}
+		}
+		seen += len(res.Workflows)
+		if len(res.Workflows) < bulkPageLimit || seen >= res.Total {
+			return ret, nil
+		}
+	}
+}
+
+// CancelAllSelected runs CancelAll on every workflow the selector matches.
+// On an error the reports of the workflows done so far are returned with it.
+func (c *Client) CancelAllSelected(ctx context.Context, sel Selector, filter ExecutionFilter, opts BulkOptions) ([]*BulkReport, error) {
+	return c.bulkSelected(ctx, sel, func(id string) (*BulkReport, error) {
+		return c.CancelAll(ctx, id, filter, opts)
+	})
+}
+
+// PurgeSelected runs PurgeExecutions on every workflow the selector matches
+func (c *Client) PurgeSelected(ctx context.Context, sel Selector, filter ExecutionFilter, opts BulkOptions) ([]*BulkReport, error) {
+	return c.bulkSelected(ctx, sel, func(id string) (*BulkReport, error) {
+		return c.PurgeExecutions(ctx, id, filter, opts)
+	})
+}
+
+func (c *Client) bulkSelected(ctx context.Context, sel Selector, run func(id string) (*BulkReport, error)) ([]*BulkReport, error) {
+	wfs, err := c.SelectWorkflows(ctx, sel)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*BulkReport
+	for _, wf := range wfs {
+		report, err := run(wf.ID)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, report)
+	}
+	return ret, nil
+}
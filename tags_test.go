@@ -0,0 +1,115 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_AddTags(t *testing.T) {
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Tags: []string{"env=prod"}}.Get(), nil)
+	wfs.OnUpdate(mock.Anything, "wf", v1.UpdateWorkflowReq{Tags: []v1.UpdateWorkflowReqTagsItem{{Name: "env=prod"}, {Name: "team=app"}}}).
+		Return(&v1.UpdateWorkflowOKWorkflow{ID: "wf", Tags: []v1.UpdateWorkflowOKWorkflowTagsItem{{Name: "env=prod"}, {Name: "team=app"}}}, nil).Once()
+
+	client := &workflows.Client{Workflows: wfs}
+	tags, err := client.AddTags(t.Context(), "wf", "team=app", "env=prod")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env=prod", "team=app"}, tags)
+
+	// nothing to change
+	tags, err = client.RemoveTags(t.Context(), "wf", "team=infra")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env=prod"}, tags)
+	tags, err = client.SetTags(t.Context(), "wf", "env=prod", "env=prod")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env=prod"}, tags)
+
+	_, err = client.AddTags(t.Context(), "wf", "")
+	assert.EqualError(t, err, "workflows: empty tag")
+}
+
+func TestSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		tags     []string
+		want     bool
+	}{
+		{"", nil, true},
+		{"env=prod", []string{"env=prod", "team=app"}, true},
+		{"env==prod", []string{"env=dev"}, false},
+		{"env=prod,team!=infra", []string{"env=prod", "team=app"}, true},
+		{"env=prod,team!=infra", []string{"env=prod", "team=infra"}, false},
+		{"team!=infra", nil, true},
+		{"critical", []string{"critical"}, true},
+		{"critical", []string{"critical=yes"}, true},
+		{"!critical", []string{"env=prod"}, true},
+		{"!critical", []string{"critical"}, false},
+		{"env=", []string{"env"}, true},
+	}
+	for _, tt := range tests {
+		sel, err := workflows.ParseSelector(tt.selector)
+		require.NoError(t, err, tt.selector)
+		assert.Equal(t, tt.want, sel.Matches(tt.tags), "%s %v", tt.selector, tt.tags)
+	}
+
+	assert.Equal(t, "env=prod,team!=infra,critical,!legacy", workflows.MustParseSelector(" env == prod , team!=infra,critical,!legacy").String())
+	for _, s := range []string{"=prod", "env=a=b", "!", "env,,team"} {
+		_, err := workflows.ParseSelector(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestClient_CancelAllSelected(t *testing.T) {
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnList(mock.Anything, mock.Anything).Return(workflowsmock.WorkflowList(
+		workflowsmock.Workflow{ID: "wf1", Tags: []string{"env=prod"}},
+		workflowsmock.Workflow{ID: "wf2", Tags: []string{"env=dev"}},
+	), nil).Once()
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnList(mock.Anything, mock.MatchedBy(func(p v1.ListExecutionParams) bool { return p.ID == "wf1" })).
+		Return(workflowsmock.ExecutionList(workflowsmock.Execution{ID: "e1", Status: "Running"}), nil).Once()
+
+	client := &workflows.Client{Workflows: wfs, Executions: executions}
+	reports, err := client.CancelAllSelected(t.Context(), workflows.MustParseSelector("env=prod"), workflows.ExecutionFilter{}, workflows.BulkOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, "wf1", reports[0].WorkflowID)
+	assert.Equal(t, 1, reports[0].Count(workflows.BulkDryRun))
+}
+
+func TestClient_AddTags_concurrentUpdate(t *testing.T) {
+	before := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	after := before.Add(time.Minute)
+
+	wfs := workflowsmock.NewWorkflowAPI(t)
+	wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Tags: []string{"env=prod"}, UpdatedAt: before}.Get(), nil).Once()
+	wfs.OnRead(mock.Anything, "wf").Return(workflowsmock.Workflow{ID: "wf", Tags: []string{"env=prod", "team=infra"}, UpdatedAt: after}.Get(), nil)
+	wfs.OnUpdate(mock.Anything, "wf", v1.UpdateWorkflowReq{Tags: []v1.UpdateWorkflowReqTagsItem{{Name: "env=prod"}, {Name: "team=infra"}, {Name: "team=app"}}}).
+		Return(&v1.UpdateWorkflowOKWorkflow{ID: "wf", Tags: []v1.UpdateWorkflowOKWorkflowTagsItem{{Name: "env=prod"}, {Name: "team=infra"}, {Name: "team=app"}}}, nil).Once()
+
+	client := &workflows.Client{Workflows: wfs}
+	tags, err := client.AddTags(t.Context(), "wf", "team=app")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env=prod", "team=infra", "team=app"}, tags)
+}
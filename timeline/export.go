@@ -0,0 +1,204 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// chromeEvent Trace Event Formatのイベント
+//
+// https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+type chromeEvent struct {
+	Name string            `json:"name"`
+	Cat  string            `json:"cat,omitempty"`
+	Ph   string            `json:"ph"`
+	Ts   int64             `json:"ts"`
+	Dur  *int64            `json:"dur,omitempty"`
+	Pid  int               `json:"pid"`
+	Tid  int               `json:"tid"`
+	Args map[string]string `json:"args,omitempty"`
+}
+
+// WriteChromeTrace writes the timeline in the Chrome trace-event JSON format,
+// which chrome://tracing and https://ui.perfetto.dev can open.
+// Each thread of the execution is shown as a thread; timestamps are relative to the first event.
+func (t *Timeline) WriteChromeTrace(w io.Writer) error {
+	tids := map[string]int{}
+	events := []chromeEvent{{
+		Name: "process_name", Ph: "M", Pid: 1,
+		Args: map[string]string{"name": "execution " + t.ExecutionID},
+	}}
+	for i, thread := range t.Threads {
+		tids[thread] = i + 1
+		events = append(events, chromeEvent{
+			Name: "thread_name", Ph: "M", Pid: 1, Tid: i + 1,
+			Args: map[string]string{"name": thread},
+		})
+	}
+
+	for _, s := range t.Spans {
+		dur := s.Duration().Microseconds()
+		events = append(events, chromeEvent{
+			Name: s.Name,
+			Cat:  string(s.Kind),
+			Ph:   "X",
+			Ts:   s.Start.Sub(t.Start).Microseconds(),
+			Dur:  &dur,
+			Pid:  1,
+			Tid:  tids[s.ThreadID],
+			Args: s.attributes(),
+		})
+	}
+	for _, in := range t.Suspensions {
+		dur := in.End.Sub(in.Start).Microseconds()
+		events = append(events, chromeEvent{Name: "suspended", Cat: "workflow", Ph: "X", Ts: in.Start.Sub(t.Start).Microseconds(), Dur: &dur, Pid: 1})
+	}
+
+	return json.NewEncoder(w).Encode(map[string]any{
+		"traceEvents":     events,
+		"displayTimeUnit": "ms",
+	})
+}
+
+// OTLP JSONエンコーディング(https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding)の型
+type (
+	otlpTraces struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpKeyValue struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue string `json:"stringValue"`
+	}
+	otlpStatus struct {
+		Code int `json:"code"`
+	}
+)
+
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusOK         = 1
+	otlpStatusError      = 2
+)
+
+// WriteOTLP writes the timeline as an OTLP/JSON ExportTraceServiceRequest, which can be posted
+// to the /v1/traces endpoint of an OpenTelemetry collector.
+//
+// The trace ID is derived from the execution ID, so exporting the same execution twice yields the same trace.
+func (t *Timeline) WriteOTLP(w io.Writer, serviceName string) error {
+	traceID := t.TraceID()
+	spans := make([]otlpSpan, 0, len(t.Spans))
+	for _, s := range t.Spans {
+		span := otlpSpan{
+			TraceID:           traceID,
+			SpanID:            t.spanID(s.ID),
+			Name:              s.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Status:            otlpStatus{Code: otlpStatusOK},
+		}
+		if s.Parent >= 0 {
+			span.ParentSpanID = t.spanID(s.Parent)
+		}
+		if s.Failed {
+			span.Status.Code = otlpStatusError
+		}
+		attrs := s.attributes()
+		for _, key := range slices.Sorted(maps.Keys(attrs)) {
+			span.Attributes = append(span.Attributes, otlpKeyValue{Key: "workflows." + key, Value: otlpValue{StringValue: attrs[key]}})
+		}
+		spans = append(spans, span)
+	}
+
+	return json.NewEncoder(w).Encode(otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{
+			{Key: "service.name", Value: otlpValue{StringValue: serviceName}},
+			{Key: "workflows.execution_id", Value: otlpValue{StringValue: t.ExecutionID}},
+		}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "github.com/sacloud/workflows-api-go/timeline"},
+			Spans: spans,
+		}},
+	}}})
+}
+
+// TraceID returns the 32 hex digit trace ID used by WriteOTLP.
+// An execution ID in the UUID format is used as is.
+func (t *Timeline) TraceID() string {
+	id := strings.ReplaceAll(t.ExecutionID, "-", "")
+	if _, err := hex.DecodeString(id); err == nil && len(id) == 32 {
+		return strings.ToLower(id)
+	}
+	sum := sha256.Sum256([]byte(t.ExecutionID))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (t *Timeline) spanID(id int) string {
+	sum := sha256.Sum256([]byte(t.ExecutionID + "/" + strconv.Itoa(id)))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (s Span) attributes() map[string]string {
+	attrs := map[string]string{"kind": string(s.Kind), "thread_id": s.ThreadID}
+	if s.JobID != "" {
+		attrs["job_id"] = s.JobID
+	}
+	if !s.RunAt.IsZero() {
+		attrs["wait"] = s.RunAt.Sub(s.Start).String()
+	}
+	if s.Open {
+		attrs["open"] = "true"
+	}
+	if s.Failed {
+		attrs["failed"] = "true"
+	}
+	return attrs
+}
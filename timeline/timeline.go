@@ -0,0 +1,369 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package timeline analyzes the history of an execution.
+//
+// Build pairs the begin and end events of the workflow, its steps and function calls into spans
+// per thread. The resulting Timeline gives per-step durations, the slowest steps and the critical
+// path through parallel branches, and can be exported as a Chrome trace (chrome://tracing,
+// Perfetto) or as OTLP spans for visual debugging.
+//
+//	res, err := client.Executions.ListHistory(ctx, params)
+//	tl := timeline.Build(res.Histories)
+//	for _, span := range tl.Slowest(5, timeline.KindStep) {
+//		fmt.Println(span.Name, span.Duration())
+//	}
+package timeline
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// Event 実行履歴の1イベント
+type Event = v1.ListExecutionHistoryOKHistoriesItem
+
+// Kind スパンの種類
+type Kind string
+
+const (
+	// KindWorkflow workflowWillStartから終了イベントまで
+	KindWorkflow Kind = "workflow"
+	// KindStep stepWillExecuteからstepDidExecutedまで
+	KindStep Kind = "step"
+	// KindFunction functionWillCallからfunctionDidRun/functionDidFailedまで
+	KindFunction Kind = "function"
+)
+
+// Span 対応する開始/終了イベントの組
+type Span struct {
+	// ID Timeline.Spans内の位置
+	ID int
+	// Parent 親スパンのID。ルートは-1
+	Parent   int
+	Kind     Kind
+	Name     string
+	JobID    string
+	ThreadID string
+	Start    time.Time
+	// End 終了イベントの時刻。Openの場合は履歴の最後のイベントの時刻
+	End time.Time
+	// RunAt functionWillRunの時刻。関数が呼び出されてから実行されるまでの待ち時間がわかる
+	RunAt time.Time
+	// Open 終了イベントがまだない
+	Open bool
+	// Failed functionDidFailedまたはworkflowDidFailed/workflowDidCanceledで終了した
+	Failed bool
+	Depth  int
+}
+
+// Duration returns the time between the begin and end events
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Timeline 実行履歴から組み立てたスパンの集まり
+type Timeline struct {
+	ExecutionID string
+	Start       time.Time
+	End         time.Time
+	// Status 最後に記録されたworkflow系イベントの種類
+	Status v1.ListExecutionHistoryOKHistoriesItemType
+	// Spans 開始時刻順のスパン
+	Spans []Span
+	// Threads 出現順のスレッドID
+	Threads []string
+	// Suspensions workflowDidSuspendedからworkflowWillResumeまでの区間
+	Suspensions []Interval
+}
+
+// Interval 時間の区間
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Option Buildの設定
+type Option func(*builder)
+
+// WithNamer sets the function naming a span from its begin event. Defaults to DefaultName.
+func WithNamer(name func(Event) string) Option {
+	return func(b *builder) { b.name = name }
+}
+
+// nameKeys Metaから名前を探すキー
+var nameKeys = []string{"name", "Name", "step", "stepName", "function", "functionName", "call"}
+
+// DefaultName names a span after the first of the well known keys found in the Meta of the event,
+// falling back to the Meta itself when it is not JSON, and to the job ID otherwise.
+func DefaultName(ev Event) string {
+	var meta map[string]any
+	if err := json.Unmarshal([]byte(ev.Meta), &meta); err == nil {
+		for _, key := range nameKeys {
+			if s, ok := meta[key].(string); ok && s != "" {
+				return s
+			}
+		}
+	} else if ev.Meta != "" && !json.Valid([]byte(ev.Meta)) {
+		return ev.Meta
+	}
+	return ev.JobId
+}
+
+type builder struct {
+	name func(Event) string
+}
+
+// Build creates the timeline of the events. The order of the events does not matter.
+func Build(events []Event, opts ...Option) *Timeline {
+	b := &builder{name: DefaultName}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	sorted := slices.Clone(events)
+	slices.SortStableFunc(sorted, func(a, b Event) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(rank(a.Type), rank(b.Type))
+	})
+
+	tl := &Timeline{}
+	root := -1
+	stacks := map[string][]int{}
+	var suspendedAt time.Time
+
+	open := func(kind Kind, ev Event) {
+		parent := root
+		if stack := stacks[ev.ThreadId]; len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+		depth := 0
+		if parent >= 0 {
+			depth = tl.Spans[parent].Depth + 1
+		}
+		id := len(tl.Spans)
+		tl.Spans = append(tl.Spans, Span{
+			ID:       id,
+			Parent:   parent,
+			Kind:     kind,
+			Name:     b.name(ev),
+			JobID:    ev.JobId,
+			ThreadID: ev.ThreadId,
+			Start:    ev.CreatedAt,
+			Open:     true,
+			Depth:    depth,
+		})
+		stacks[ev.ThreadId] = append(stacks[ev.ThreadId], id)
+	}
+	// find returns the innermost open span of the kind in the thread
+	find := func(kind Kind, ev Event) int {
+		stack := stacks[ev.ThreadId]
+		for i := len(stack) - 1; i >= 0; i-- {
+			if tl.Spans[stack[i]].Kind == kind {
+				return i
+			}
+		}
+		return -1
+	}
+	closeSpan := func(kind Kind, ev Event, failed bool) {
+		i := find(kind, ev)
+		if i < 0 {
+			return
+		}
+		stack := stacks[ev.ThreadId]
+		// spans left open inside the closed one end with it
+		for _, id := range stack[i:] {
+			tl.Spans[id].End = ev.CreatedAt
+			tl.Spans[id].Open = false
+		}
+		tl.Spans[stack[i]].Failed = failed
+		stacks[ev.ThreadId] = stack[:i]
+	}
+
+	for _, ev := range sorted {
+		if tl.ExecutionID == "" {
+			tl.ExecutionID = ev.WorkflowExecutionId
+		}
+		if tl.Start.IsZero() {
+			tl.Start = ev.CreatedAt
+		}
+		tl.End = ev.CreatedAt
+		if ev.ThreadId != "" && !slices.Contains(tl.Threads, ev.ThreadId) {
+			tl.Threads = append(tl.Threads, ev.ThreadId)
+		}
+
+		switch ev.Type {
+		case v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowWillStart:
+			tl.Status = ev.Type
+			if root < 0 {
+				root = len(tl.Spans)
+				tl.Spans = append(tl.Spans, Span{ID: root, Parent: -1, Kind: KindWorkflow, Name: b.name(ev), JobID: ev.JobId, ThreadID: ev.ThreadId, Start: ev.CreatedAt, Open: true})
+			}
+		case v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidSuspended:
+			tl.Status = ev.Type
+			suspendedAt = ev.CreatedAt
+		case v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowWillResume:
+			tl.Status = ev.Type
+			if !suspendedAt.IsZero() {
+				tl.Suspensions = append(tl.Suspensions, Interval{Start: suspendedAt, End: ev.CreatedAt})
+				suspendedAt = time.Time{}
+			}
+		case v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCompleted,
+			v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidFailed,
+			v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCanceled:
+			tl.Status = ev.Type
+			if root >= 0 {
+				tl.Spans[root].End = ev.CreatedAt
+				tl.Spans[root].Open = false
+				tl.Spans[root].Failed = ev.Type != v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCompleted
+			}
+		case v1.ListExecutionHistoryOKHistoriesItemTypeStepWillExecute:
+			open(KindStep, ev)
+		case v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted:
+			closeSpan(KindStep, ev, false)
+		case v1.ListExecutionHistoryOKHistoriesItemTypeFunctionWillCall:
+			open(KindFunction, ev)
+		case v1.ListExecutionHistoryOKHistoriesItemTypeFunctionWillRun:
+			if i := find(KindFunction, ev); i >= 0 {
+				tl.Spans[stacks[ev.ThreadId][i]].RunAt = ev.CreatedAt
+			}
+		case v1.ListExecutionHistoryOKHistoriesItemTypeFunctionDidRun:
+			closeSpan(KindFunction, ev, false)
+		case v1.ListExecutionHistoryOKHistoriesItemTypeFunctionDidFailed:
+			closeSpan(KindFunction, ev, true)
+		}
+	}
+
+	for i := range tl.Spans {
+		if tl.Spans[i].Open {
+			tl.Spans[i].End = tl.End
+		}
+	}
+	if !suspendedAt.IsZero() {
+		tl.Suspensions = append(tl.Suspensions, Interval{Start: suspendedAt, End: tl.End})
+	}
+	return tl
+}
+
+// rank orders events recorded at the same time so that the workflow encloses its steps
+func rank(t v1.ListExecutionHistoryOKHistoriesItemType) int {
+	switch t {
+	case v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowWillStart:
+		return 0
+	case v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCompleted,
+		v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidFailed,
+		v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCanceled:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Duration returns the time between the first and the last event
+func (t *Timeline) Duration() time.Duration {
+	return t.End.Sub(t.Start)
+}
+
+// Thread returns the spans of the thread in order of start
+func (t *Timeline) Thread(id string) []Span {
+	var ret []Span
+	for _, s := range t.Spans {
+		if s.ThreadID == id && s.Kind != KindWorkflow {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// Children returns the spans directly under the span
+func (t *Timeline) Children(id int) []Span {
+	var ret []Span
+	for _, s := range t.Spans {
+		if s.Parent == id {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// Slowest returns the n longest spans of the kind, longest first.
+// An empty kind matches steps and function calls.
+func (t *Timeline) Slowest(n int, kind Kind) []Span {
+	var ret []Span
+	for _, s := range t.Spans {
+		if s.Kind == KindWorkflow || (kind != "" && s.Kind != kind) {
+			continue
+		}
+		ret = append(ret, s)
+	}
+	slices.SortStableFunc(ret, func(a, b Span) int { return cmp.Compare(b.Duration(), a.Duration()) })
+	if n >= 0 && len(ret) > n {
+		ret = ret[:n]
+	}
+	return ret
+}
+
+// CriticalPath returns the chain of top-level spans that determined the duration of the execution,
+// in chronological order.
+//
+// It walks back from the end of the execution, each time choosing the span that finished last
+// before the start of the span chosen previously, so with parallel branches the branch that
+// finished last is on the path and the others are not.
+func (t *Timeline) CriticalPath() []Span {
+	var candidates []Span
+	for _, s := range t.Spans {
+		if s.Kind == KindWorkflow {
+			continue
+		}
+		if s.Parent < 0 || t.Spans[s.Parent].Kind == KindWorkflow {
+			candidates = append(candidates, s)
+		}
+	}
+
+	var path []Span
+	until := t.End
+	used := map[int]bool{}
+	for {
+		best := -1
+		for i, s := range candidates {
+			if used[s.ID] || s.End.After(until) {
+				continue
+			}
+			if best < 0 || s.End.After(candidates[best].End) ||
+				(s.End.Equal(candidates[best].End) && s.Duration() > candidates[best].Duration()) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		s := candidates[best]
+		used[s.ID] = true
+		path = append(path, s)
+		until = s.Start
+	}
+	slices.Reverse(path)
+	return path
+}
+
+func (s Span) String() string {
+	return fmt.Sprintf("%s %s [%s] %s", s.Kind, s.Name, s.ThreadID, s.Duration())
+}
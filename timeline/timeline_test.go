@@ -0,0 +1,175 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeline_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/timeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const executionID = "4d2b1c9e-1234-4000-8000-000000000001"
+
+var t0 = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func event(typ v1.ListExecutionHistoryOKHistoriesItemType, thread, name string, ms int) timeline.Event {
+	return timeline.Event{
+		WorkflowExecutionId: executionID,
+		JobId:               "job",
+		ThreadId:            thread,
+		Type:                typ,
+		CreatedAt:           t0.Add(time.Duration(ms) * time.Millisecond),
+		Meta:                `{"name":"` + name + `"}`,
+		StackTrace:          "-",
+		Variables:           "{}",
+	}
+}
+
+// parallelHistory main runs init, then branches a (fetch calling http.get) and b (short) in parallel, then final
+func parallelHistory() []timeline.Event {
+	return []timeline.Event{
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowWillStart, "main", "wf", 0),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeStepWillExecute, "main", "init", 0),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted, "main", "init", 1000),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeStepWillExecute, "a", "fetch", 1000),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeStepWillExecute, "b", "short", 1000),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeFunctionWillCall, "a", "http.get", 2000),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted, "b", "short", 2000),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeFunctionWillRun, "a", "http.get", 2500),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeFunctionDidRun, "a", "http.get", 4000),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted, "a", "fetch", 5000),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeStepWillExecute, "main", "final", 5000),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted, "main", "final", 6000),
+		event(v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCompleted, "main", "wf", 6000),
+	}
+}
+
+func names(spans []timeline.Span) []string {
+	var ret []string
+	for _, s := range spans {
+		ret = append(ret, s.Name)
+	}
+	return ret
+}
+
+func TestBuild(t *testing.T) {
+	events := parallelHistory()
+	// the API may return the events newest first
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	tl := timeline.Build(events)
+
+	assert.Equal(t, executionID, tl.ExecutionID)
+	assert.Equal(t, 6*time.Second, tl.Duration())
+	assert.Equal(t, v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCompleted, tl.Status)
+	assert.ElementsMatch(t, []string{"main", "a", "b"}, tl.Threads)
+	assert.Equal(t, []string{"fetch", "http.get"}, names(tl.Thread("a")))
+
+	fetch := tl.Thread("a")[0]
+	assert.Equal(t, 4*time.Second, fetch.Duration())
+	call := tl.Children(fetch.ID)
+	require.Len(t, call, 1)
+	assert.Equal(t, 2*time.Second, call[0].Duration())
+	assert.Equal(t, 2, call[0].Depth)
+	assert.Equal(t, t0.Add(2500*time.Millisecond), call[0].RunAt)
+
+	assert.Equal(t, []string{"fetch", "init"}, names(tl.Slowest(2, timeline.KindStep)))
+	assert.Equal(t, []string{"init", "fetch", "final"}, names(tl.CriticalPath()))
+	for _, s := range tl.Spans[1:] {
+		assert.NotEqual(t, -1, s.Parent, s.Name)
+	}
+}
+
+func TestBuild_open(t *testing.T) {
+	events := parallelHistory()[:6]
+	tl := timeline.Build(events)
+
+	fetch := tl.Thread("a")[0]
+	assert.True(t, fetch.Open)
+	assert.Equal(t, tl.End, fetch.End)
+	assert.Equal(t, v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowWillStart, tl.Status)
+}
+
+func TestTimeline_WriteChromeTrace(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, timeline.Build(parallelHistory()).WriteChromeTrace(&buf))
+
+	var trace struct {
+		TraceEvents []struct {
+			Name string
+			Ph   string
+			Ts   int64
+			Dur  int64
+			Tid  int
+		}
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &trace))
+	var found bool
+	for _, ev := range trace.TraceEvents {
+		if ev.Name == "fetch" {
+			found = true
+			assert.Equal(t, "X", ev.Ph)
+			assert.Equal(t, int64(1_000_000), ev.Ts)
+			assert.Equal(t, int64(4_000_000), ev.Dur)
+			assert.Equal(t, 2, ev.Tid)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestTimeline_WriteOTLP(t *testing.T) {
+	tl := timeline.Build(parallelHistory())
+	var buf bytes.Buffer
+	require.NoError(t, tl.WriteOTLP(&buf, "workflows"))
+
+	var req struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID      string `json:"traceId"`
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Name         string `json:"name"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &req))
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, len(tl.Spans))
+
+	assert.Equal(t, "4d2b1c9e123440008000000000000001", spans[0].TraceID)
+	assert.Empty(t, spans[0].ParentSpanID)
+	ids := map[string]string{}
+	for _, s := range spans {
+		assert.Len(t, s.SpanID, 16)
+		ids[s.Name] = s.SpanID
+	}
+	for _, s := range spans {
+		switch s.Name {
+		case "http.get":
+			assert.Equal(t, ids["fetch"], s.ParentSpanID)
+		case "init", "fetch", "short", "final":
+			assert.Equal(t, ids["wf"], s.ParentSpanID)
+		}
+	}
+}
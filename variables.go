@@ -0,0 +1,184 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// VariableHistory 実行履歴から再構成した変数の状態の移り変わり
+//
+// 各イベントのVariablesを古い順に重ねていき、i番目のイベントの時点での変数の状態を求める。
+// Variablesに含まれない変数は直前の値を保つ。
+//
+// スレッドやステップのスコープは区別せず、全イベントを1つの名前空間に重ねる。
+// そのため並列実行の別スレッドやサブワークフローで同名の変数がある場合、状態は最後に記録された値になり、
+// スコープを抜けて消えた変数も残り続ける。
+type VariableHistory struct {
+	events []v1.ListExecutionHistoryOKHistoriesItem
+	// states i番目のイベントの後の状態
+	states []map[string]any
+}
+
+// VariableChange ある変数の値が変わったイベント
+type VariableChange struct {
+	// Index 変化を記録したイベントの位置
+	Index int
+	At    time.Time
+	Type  v1.ListExecutionHistoryOKHistoriesItemType
+	// Old 変化前の値。変数が存在しなかった場合はnil
+	Old any
+	New any
+}
+
+// DiffKind VariableDiffの種類
+type DiffKind string
+
+const (
+	// DiffAdded toの時点で初めて現れた
+	DiffAdded DiffKind = "added"
+	// DiffRemoved fromの時点にはあったがtoの時点にはない。fromがtoより後の場合に起こる
+	DiffRemoved DiffKind = "removed"
+	// DiffChanged 値が変わった
+	DiffChanged DiffKind = "changed"
+)
+
+// VariableDiff 2時点間での1変数の違い
+type VariableDiff struct {
+	Name string
+	Kind DiffKind
+	Old  any
+	New  any
+}
+
+// LoadVariableHistory reads the whole history of the execution, page by page, and reconstructs its variables
+func (c *Client) LoadVariableHistory(ctx context.Context, workflowID, executionID string) (*VariableHistory, error) {
+	var events []v1.ListExecutionHistoryOKHistoriesItem
+	for ev, err := range StreamHistory(ctx, c.Executions, workflowID, executionID, StreamOptions{}) {
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	return NewVariableHistory(events), nil
+}
+
+// NewVariableHistory reconstructs the variables from history events in any order.
+// Variables that are not a JSON object are ignored.
+func NewVariableHistory(events []v1.ListExecutionHistoryOKHistoriesItem) *VariableHistory {
+	sorted := slices.Clone(events)
+	slices.SortStableFunc(sorted, func(a, b v1.ListExecutionHistoryOKHistoriesItem) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	h := &VariableHistory{events: sorted, states: make([]map[string]any, len(sorted))}
+	state := map[string]any{}
+	for i, ev := range sorted {
+		if vars, ok := DecodeHistoryField(ev.Variables).(map[string]any); ok && len(vars) > 0 {
+			state = maps.Clone(state)
+			maps.Copy(state, vars)
+		}
+		h.states[i] = state
+	}
+	return h
+}
+
+// Len returns the number of history events
+func (h *VariableHistory) Len() int {
+	return len(h.events)
+}
+
+// Event returns the i-th history event in chronological order
+func (h *VariableHistory) Event(i int) v1.ListExecutionHistoryOKHistoriesItem {
+	return h.events[i]
+}
+
+// StateAt returns the variables right after the i-th event. The returned map may be modified.
+func (h *VariableHistory) StateAt(i int) (map[string]any, error) {
+	if i < 0 || i >= len(h.states) {
+		return nil, NewError(fmt.Sprintf("history index %d out of range [0, %d)", i, len(h.states)), nil)
+	}
+	return maps.Clone(h.states[i]), nil
+}
+
+// Final returns the variables after the last event
+func (h *VariableHistory) Final() map[string]any {
+	if len(h.states) == 0 {
+		return map[string]any{}
+	}
+	return maps.Clone(h.states[len(h.states)-1])
+}
+
+// Names returns the names of all variables that appeared during the execution, sorted
+func (h *VariableHistory) Names() []string {
+	return slices.Sorted(maps.Keys(h.Final()))
+}
+
+// Changes returns the events that changed the variable, in chronological order
+func (h *VariableHistory) Changes(name string) []VariableChange {
+	var ret []VariableChange
+	var prev any
+	var existed bool
+	for i, state := range h.states {
+		v, ok := state[name]
+		if !ok || (existed && reflect.DeepEqual(prev, v)) {
+			continue
+		}
+		ret = append(ret, VariableChange{Index: i, At: h.events[i].CreatedAt, Type: h.events[i].Type, Old: prev, New: v})
+		prev, existed = v, true
+	}
+	return ret
+}
+
+// Diff returns the differences of the variables between the states after the from-th and the to-th events, sorted by name.
+// As the states merge every thread and step into one namespace, a variable of the same name in two threads
+// shows up as a change, and a variable that went out of scope is never reported as removed going forward.
+func (h *VariableHistory) Diff(from, to int) ([]VariableDiff, error) {
+	before, err := h.StateAt(from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := h.StateAt(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []VariableDiff
+	names := slices.Sorted(maps.Keys(before))
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		old, inBefore := before[name]
+		cur, inAfter := after[name]
+		switch {
+		case !inBefore:
+			ret = append(ret, VariableDiff{Name: name, Kind: DiffAdded, New: cur})
+		case !inAfter:
+			ret = append(ret, VariableDiff{Name: name, Kind: DiffRemoved, Old: old})
+		case !reflect.DeepEqual(old, cur):
+			ret = append(ret, VariableDiff{Name: name, Kind: DiffChanged, Old: old, New: cur})
+		}
+	}
+	return ret, nil
+}
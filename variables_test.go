@@ -0,0 +1,83 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_LoadVariableHistory(t *testing.T) {
+	withVars := func(typ v1.ListExecutionHistoryOKHistoriesItemType, sec int, variables string) v1.ListExecutionHistoryOKHistoriesItem {
+		ev := historyEvent(typ, sec)
+		ev.Variables = variables
+		return ev
+	}
+	events := []v1.ListExecutionHistoryOKHistoriesItem{
+		withVars(v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowWillStart, 0, `{"url":"https://example.com"}`),
+		withVars(v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted, 1, `{"count":1}`),
+		withVars(v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted, 2, `{}`),
+		withVars(v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted, 3, `{"count":2,"res":{"status":200}}`),
+		withVars(v1.ListExecutionHistoryOKHistoriesItemTypeWorkflowDidCompleted, 4, `{"count":2}`),
+	}
+
+	// the history is served page by page
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnListHistory(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, params v1.ListExecutionHistoryParams) (*v1.ListExecutionHistoryOK, error) {
+		limit := params.PageLimit.Value
+		from := (params.Page.Value - 1) * limit
+		return workflowsmock.History(events[min(from, len(events)):min(from+limit, len(events))]...), nil
+	})
+
+	client := &workflows.Client{Executions: executions}
+	h, err := client.LoadVariableHistory(t.Context(), "wf", "exec")
+	require.NoError(t, err)
+	require.Equal(t, 5, h.Len())
+
+	state, err := h.StateAt(1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"url": "https://example.com", "count": float64(1)}, state)
+	_, err = h.StateAt(5)
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"count", "res", "url"}, h.Names())
+
+	changes := h.Changes("count")
+	require.Len(t, changes, 2)
+	assert.Equal(t, 1, changes[0].Index)
+	assert.Nil(t, changes[0].Old)
+	assert.Equal(t, float64(1), changes[1].Old)
+	assert.Equal(t, float64(2), changes[1].New)
+	assert.Equal(t, v1.ListExecutionHistoryOKHistoriesItemTypeStepDidExecuted, changes[1].Type)
+
+	diff, err := h.Diff(1, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []workflows.VariableDiff{
+		{Name: "count", Kind: workflows.DiffChanged, Old: float64(1), New: float64(2)},
+		{Name: "res", Kind: workflows.DiffAdded, New: map[string]any{"status": float64(200)}},
+	}, diff)
+
+	diff, err = h.Diff(4, 0)
+	require.NoError(t, err)
+	assert.Len(t, diff, 2)
+	assert.Equal(t, workflows.DiffRemoved, diff[0].Kind)
+}
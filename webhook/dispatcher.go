@@ -0,0 +1,446 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook notifies HTTP endpoints when executions finish.
+//
+// A Dispatcher watches the executions with an informer and, whenever one reaches Succeeded, Failed
+// or Canceled, POSTs an Event as JSON to every endpoint interested in it. The body is signed with
+// HMAC-SHA256 in SignatureHeader, which receivers check with Verify. Failed deliveries are retried
+// with exponential backoff on network errors, 429 and 5xx responses; what still fails is appended
+// to the dead-letter queue, from which it can be redelivered later.
+//
+//	endpoints, err := webhook.LoadEndpoints(data)
+//	d, err := webhook.New(client, endpoints,
+//		webhook.WithDeadLetter(webhook.NewFileDeadLetter("/var/lib/workflows/dead-letter.jsonl")),
+//		webhook.WithInformerOptions(informer.WithInterval(15*time.Second), informer.WithConcurrency(4)))
+//	err = d.Run(ctx)
+//
+// Executions which had already finished when Run started are not notified.
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/informer"
+)
+
+const (
+	// DefaultRetries 配信に失敗した際に再試行する既定の回数
+	DefaultRetries = 5
+	// DefaultBackoff 再試行する前に待つ既定の時間。試行ごとに倍になる
+	DefaultBackoff = time.Second
+	// DefaultTimeout 1回の配信の既定のタイムアウト
+	DefaultTimeout = 30 * time.Second
+	// DefaultWorkers Runが1つのエンドポイントに同時に配信する既定の数
+	DefaultWorkers = 2
+	// DefaultQueueSize Runが1つのエンドポイントごとに配信を待たせておける既定のイベント数
+	DefaultQueueSize = 100
+)
+
+// errQueueFull エンドポイントの配信待ちがいっぱいでイベントを受け付けられなかったことを表す
+var errQueueFull = errors.New("delivery queue is full")
+
+// Endpoint イベントの送り先
+type Endpoint struct {
+	// Name エンドポイントの名前。ログとデッドレターに使い、一意である必要がある
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Secret 署名の鍵。空の場合は署名しない
+	Secret string `json:"secret,omitempty"`
+	// SecretEnv Secretを読む環境変数。Secretが空の場合に使う
+	SecretEnv string `json:"secretEnv,omitempty"`
+	// Statuses 送る実行の状態。空の場合は全て
+	Statuses []string `json:"statuses,omitempty"`
+	// Workflows 送るワークフローのID。空の場合は全て
+	Workflows []string `json:"workflows,omitempty"`
+	// Headers リクエストに追加するヘッダ
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// wants reports whether the endpoint is interested in the event
+func (ep *Endpoint) wants(ev *Event) bool {
+	return (len(ep.Statuses) == 0 || slices.Contains(ep.Statuses, ev.Status)) &&
+		(len(ep.Workflows) == 0 || slices.Contains(ep.Workflows, ev.WorkflowID))
+}
+
+// LoadEndpoints reads endpoints in YAML or JSON: a list of endpoints, or an object whose
+// endpoints key holds the list.
+func LoadEndpoints(data []byte) ([]Endpoint, error) {
+	var endpoints []Endpoint
+	if err := yaml.Unmarshal(data, &endpoints); err == nil {
+		return endpoints, nil
+	}
+	var config struct {
+		Endpoints []Endpoint `json:"endpoints"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+	return config.Endpoints, nil
+}
+
+// DeadLetter 再試行しても配信できなかったイベント
+type DeadLetter struct {
+	Endpoint string    `json:"endpoint"`
+	URL      string    `json:"url"`
+	Event    Event     `json:"event"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// DeadLetterQueue 配信できなかったイベントの保存先
+type DeadLetterQueue interface {
+	Put(ctx context.Context, dl DeadLetter) error
+}
+
+// FileDeadLetter 1行に1件のJSONとしてファイルに追記するDeadLetterQueue
+type FileDeadLetter struct {
+	mu   sync.Mutex
+	path string
+}
+
+var _ DeadLetterQueue = (*FileDeadLetter)(nil)
+
+// NewFileDeadLetter returns a queue appending to the file at path
+func NewFileDeadLetter(path string) *FileDeadLetter {
+	return &FileDeadLetter{path: path}
+}
+
+func (q *FileDeadLetter) Put(_ context.Context, dl DeadLetter) error {
+	data, err := json.Marshal(dl)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	f, err := os.OpenFile(q.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close() //nolint:errcheck,gosec
+		return fmt.Errorf("webhook: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	return nil
+}
+
+// ReadDeadLetters reads what a FileDeadLetter has written
+func ReadDeadLetters(r io.Reader) ([]DeadLetter, error) {
+	var ret []DeadLetter
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 16<<20)
+	for sc.Scan() {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var dl DeadLetter
+		if err := json.Unmarshal(sc.Bytes(), &dl); err != nil {
+			return nil, fmt.Errorf("webhook: %w", err)
+		}
+		ret = append(ret, dl)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+	return ret, nil
+}
+
+// Option Dispatcherの設定
+type Option func(*Dispatcher)
+
+// WithHTTPClient sets the client posting the events. Defaults to a client with DefaultTimeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) { d.httpClient = client }
+}
+
+// WithRetries sets how many times a delivery is retried. Defaults to DefaultRetries; a negative value disables retries.
+func WithRetries(n int) Option {
+	return func(d *Dispatcher) { d.retries = n }
+}
+
+// WithBackoff sets the wait before the first retry, doubled for each further one. Defaults to DefaultBackoff.
+func WithBackoff(backoff time.Duration) Option {
+	return func(d *Dispatcher) { d.backoff = backoff }
+}
+
+// WithWorkers sets how many events Run delivers at once to each endpoint. Defaults to DefaultWorkers.
+func WithWorkers(n int) Option {
+	return func(d *Dispatcher) { d.workers = n }
+}
+
+// WithQueueSize sets how many events Run holds for each endpoint while its workers are busy.
+// Events for an endpoint whose queue is full are dead-lettered right away. Defaults to DefaultQueueSize.
+func WithQueueSize(n int) Option {
+	return func(d *Dispatcher) { d.queueSize = n }
+}
+
+// WithDeadLetter sets where undeliverable events go. By default they are only logged.
+func WithDeadLetter(q DeadLetterQueue) Option {
+	return func(d *Dispatcher) { d.deadLetter = q }
+}
+
+// WithWorkflows limits the executions watched to those of the workflows. See informer.NewExecutionInformer.
+func WithWorkflows(ids ...string) Option {
+	return func(d *Dispatcher) { d.workflowIDs = ids }
+}
+
+// WithInformerOptions sets the options of the informer watching the executions, e.g. the interval and
+// the number of events delivered at once
+func WithInformerOptions(opts ...informer.Option) Option {
+	return func(d *Dispatcher) { d.informerOpts = opts }
+}
+
+// WithLogger sets the logger. Defaults to slog.Default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(d *Dispatcher) { d.logger = logger }
+}
+
+// WithClock sets the function giving the current time. Defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(d *Dispatcher) { d.now = now }
+}
+
+// Dispatcher 終了した実行をエンドポイントに通知する
+type Dispatcher struct {
+	client       *workflows.Client
+	endpoints    []Endpoint
+	httpClient   *http.Client
+	retries      int
+	backoff      time.Duration
+	workers      int
+	queueSize    int
+	deadLetter   DeadLetterQueue
+	workflowIDs  []string
+	informerOpts []informer.Option
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+// New validates the endpoints and returns a dispatcher for them
+func New(client *workflows.Client, endpoints []Endpoint, opts ...Option) (*Dispatcher, error) {
+	d := &Dispatcher{
+		client:     client,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		retries:    DefaultRetries,
+		backoff:    DefaultBackoff,
+		workers:    DefaultWorkers,
+		queueSize:  DefaultQueueSize,
+		logger:     slog.Default(),
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.workers, d.queueSize = max(d.workers, 1), max(d.queueSize, 0)
+
+	names := map[string]bool{}
+	for _, ep := range endpoints {
+		if ep.Name == "" {
+			return nil, fmt.Errorf("webhook: endpoint %q: no name", ep.URL)
+		}
+		if names[ep.Name] {
+			return nil, fmt.Errorf("webhook: duplicate endpoint %q", ep.Name)
+		}
+		names[ep.Name] = true
+		if u, err := url.Parse(ep.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("webhook: endpoint %q: invalid URL %q", ep.Name, ep.URL)
+		}
+		if ep.Secret == "" && ep.SecretEnv != "" {
+			if ep.Secret = os.Getenv(ep.SecretEnv); ep.Secret == "" {
+				return nil, fmt.Errorf("webhook: endpoint %q: %s is not set", ep.Name, ep.SecretEnv)
+			}
+		}
+		d.endpoints = append(d.endpoints, ep)
+	}
+	return d, nil
+}
+
+// Run watches the executions and dispatches an event for each one finishing, until ctx is canceled.
+//
+// The informer only queues the events: each endpoint has its own queue, drained by WithWorkers workers,
+// so that an endpoint which is down and retried for minutes holds up neither the other endpoints nor
+// the polling. Head-of-line blocking remains within an endpoint: its events wait behind its own slow
+// deliveries, and once its queue is full they are dead-lettered without being tried. Events still queued
+// when ctx is canceled fail at once and are dead-lettered as well.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	started := d.now()
+	queues := make([]chan Event, len(d.endpoints))
+	var wg sync.WaitGroup
+	for i := range d.endpoints {
+		queues[i] = make(chan Event, d.queueSize)
+		for range d.workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for ev := range queues[i] {
+					// failures are logged and dead-lettered by deliver
+					_ = d.deliver(ctx, &d.endpoints[i], ev)
+				}
+			}()
+		}
+	}
+
+	inf := informer.NewExecutionInformer(d.client, d.workflowIDs, d.informerOpts...)
+	inf.AddHandler(func(ctx context.Context, ev informer.Event[v1.ListExecutionOKExecutionsItem]) {
+		e, ok := completion(ev, started)
+		if !ok {
+			return
+		}
+		for i := range d.endpoints {
+			ep := &d.endpoints[i]
+			if !ep.wants(&e) {
+				continue
+			}
+			select {
+			case queues[i] <- e:
+			default:
+				d.fail(ctx, ep, e, 0, fmt.Errorf("webhook: endpoint %q: event %s: %w", ep.Name, e.ID, errQueueFull))
+			}
+		}
+	})
+	err := inf.Run(ctx)
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+	return err
+}
+
+// completion returns the event to dispatch for a change of an execution: one that was seen running
+// has finished, or one created since started was first seen already finished
+func completion(ev informer.Event[v1.ListExecutionOKExecutionsItem], started time.Time) (Event, bool) {
+	switch ev.Type {
+	case informer.Added:
+		if ev.New.CreatedAt.Before(started) {
+			return Event{}, false
+		}
+	case informer.Updated:
+		if _, finished := EventOf(ev.Old); finished {
+			return Event{}, false
+		}
+	default:
+		return Event{}, false
+	}
+	return EventOf(ev.New)
+}
+
+// Dispatch delivers the event to every endpoint interested in it, retrying each as configured.
+// Deliveries which still fail are dead-lettered and their errors returned joined.
+func (d *Dispatcher) Dispatch(ctx context.Context, ev Event) error {
+	var errs []error
+	for i := range d.endpoints {
+		ep := &d.endpoints[i]
+		if !ep.wants(&ev) {
+			continue
+		}
+		if err := d.deliver(ctx, ep, ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Redeliver delivers a dead letter again to its endpoint, dead-lettering it anew if it fails again
+func (d *Dispatcher) Redeliver(ctx context.Context, dl DeadLetter) error {
+	for i := range d.endpoints {
+		if d.endpoints[i].Name == dl.Endpoint {
+			return d.deliver(ctx, &d.endpoints[i], dl.Event)
+		}
+	}
+	return fmt.Errorf("webhook: unknown endpoint %q", dl.Endpoint)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, ep *Endpoint, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	for attempt := 1; ; attempt++ {
+		retryable, err := d.post(ctx, ep, ev, body)
+		if err == nil {
+			return nil
+		}
+		err = fmt.Errorf("webhook: endpoint %q: event %s: %w", ep.Name, ev.ID, err)
+		if !retryable || attempt > d.retries || ctx.Err() != nil {
+			d.fail(ctx, ep, ev, attempt, err)
+			return err
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(d.backoff << (attempt - 1)):
+		}
+	}
+}
+
+// fail logs a delivery given up on after attempts and dead-letters it
+func (d *Dispatcher) fail(ctx context.Context, ep *Endpoint, ev Event, attempts int, err error) {
+	d.logger.ErrorContext(ctx, "webhook delivery failed", slog.String("endpoint", ep.Name),
+		slog.String("event", ev.ID), slog.Int("attempts", attempts), slog.Any("error", err))
+	if d.deadLetter != nil {
+		dl := DeadLetter{Endpoint: ep.Name, URL: ep.URL, Event: ev, Attempts: attempts, Error: err.Error(), FailedAt: d.now()}
+		// keep the event even when shutting down
+		if err := d.deadLetter.Put(context.WithoutCancel(ctx), dl); err != nil {
+			d.logger.ErrorContext(ctx, "webhook dead letter failed", slog.String("event", ev.ID), slog.Any("error", err))
+		}
+	}
+}
+
+// post sends the event once and reports whether a failure is worth retrying
+func (d *Dispatcher) post(ctx context.Context, ep *Endpoint, ev Event, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for k, v := range ep.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, ev.ID)
+	req.Header.Set(EventTypeHeader, ev.Type)
+	if ep.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(ep.Secret, d.now(), body))
+	}
+
+	res, err := d.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close() //nolint:errcheck
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+	return retryable, fmt.Errorf("unexpected status %s", res.Status)
+}
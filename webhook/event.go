@@ -0,0 +1,162 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// EventExecutionCompleted 実行が終了したことを表すイベントの種類
+const EventExecutionCompleted = "execution.completed"
+
+const (
+	// SignatureHeader 署名を送るヘッダ。"t=<UNIX時刻>,v1=<署名>"の形
+	SignatureHeader = "X-Workflows-Signature"
+	// EventIDHeader イベントIDを送るヘッダ。再送されたイベントの重複を除くのに使う
+	EventIDHeader = "X-Workflows-Event-Id"
+	// EventTypeHeader イベントの種類を送るヘッダ
+	EventTypeHeader = "X-Workflows-Event-Type"
+)
+
+// DefaultSignatureTolerance Verifyが受け付ける署名の時刻のずれの既定値
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// Event エンドポイントに送るJSON
+type Event struct {
+	// ID イベントID。同じ実行の同じ終了状態には同じIDが付く
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	ExecutionID   string    `json:"executionId"`
+	ExecutionName string    `json:"executionName"`
+	WorkflowID    string    `json:"workflowId"`
+	WorkflowName  string    `json:"workflowName"`
+	Revision      int       `json:"revision"`
+	RevisionAlias string    `json:"revisionAlias,omitempty"`
+	Status        string    `json:"status"`
+	StepCount     int       `json:"stepCount"`
+	Result        string    `json:"result,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	// StartedAt 実行が始まった時刻。開始前にキャンセルされた場合はnil
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt time.Time  `json:"finishedAt"`
+	// QueuedSeconds 作成から開始までの秒数
+	QueuedSeconds float64 `json:"queuedSeconds"`
+	// DurationSeconds 開始から終了までの秒数。開始前にキャンセルされた場合は0
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// EventOf returns the event of the execution, or false if it has not finished
+func EventOf(e *v1.ListExecutionOKExecutionsItem) (Event, bool) {
+	var finished v1.OptDateTime
+	switch e.Status {
+	case v1.ListExecutionOKExecutionsItemStatusSucceeded:
+		finished = e.SucceededAt
+	case v1.ListExecutionOKExecutionsItemStatusFailed:
+		finished = e.FailedAt
+	case v1.ListExecutionOKExecutionsItemStatusCanceled:
+		finished = e.CanceledAt
+	default:
+		return Event{}, false
+	}
+	ev := Event{
+		ID:            e.ExecutionId + "/" + string(e.Status),
+		Type:          EventExecutionCompleted,
+		ExecutionID:   e.ExecutionId,
+		ExecutionName: e.Name,
+		WorkflowID:    e.Workflow.ID,
+		WorkflowName:  e.Workflow.Name,
+		Revision:      e.Revision,
+		RevisionAlias: e.RevisionAlias,
+		Status:        string(e.Status),
+		StepCount:     e.StepCount,
+		Result:        e.Result,
+		Error:         e.Error,
+		CreatedAt:     e.CreatedAt,
+		FinishedAt:    e.UpdatedAt,
+	}
+	if finished.Set {
+		ev.FinishedAt = finished.Value
+	}
+	if e.RunAt.Set {
+		started := e.RunAt.Value
+		ev.StartedAt = &started
+		ev.QueuedSeconds = started.Sub(e.CreatedAt).Seconds()
+		ev.DurationSeconds = ev.FinishedAt.Sub(started).Seconds()
+	} else {
+		ev.QueuedSeconds = ev.FinishedAt.Sub(e.CreatedAt).Seconds()
+	}
+	return ev, true
+}
+
+// Sign returns the value of SignatureHeader for the body sent at t: the hex HMAC-SHA256 of
+// "<unix time>.<body>" keyed by the secret
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + signature(secret, ts, body)
+}
+
+func signature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))  //nolint:errcheck
+	mac.Write([]byte(".")) //nolint:errcheck
+	mac.Write(body)        //nolint:errcheck
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrInvalidSignature Verifyが署名を受け付けなかったことを表す
+var ErrInvalidSignature = errors.New("webhook: invalid signature")
+
+// Verify checks the value of SignatureHeader a receiver got with the body, rejecting signatures
+// made more than tolerance away from now. A tolerance of 0 means DefaultSignatureTolerance.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+		return fmt.Errorf("%w: timestamp out of tolerance", ErrInvalidSignature)
+	}
+	want := signature(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(want)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,278 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/informer"
+	"github.com/sacloud/workflows-api-go/webhook"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var created = time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+
+// finished 1分待って2分実行され、statusで終了した実行
+func finished(id, status string) v1.ListExecutionOKExecutionsItem {
+	e := workflowsmock.Execution{ID: id, Workflow: workflowsmock.Workflow{ID: "wf", Name: "nightly"}, Status: status,
+		StepCount: 3, Result: `{"ok":true}`, CreatedAt: created, UpdatedAt: created.Add(3 * time.Minute)}.ListItem()
+	e.RunAt = v1.NewOptDateTime(created.Add(time.Minute))
+	switch status {
+	case "Succeeded":
+		e.SucceededAt = v1.NewOptDateTime(created.Add(3 * time.Minute))
+	case "Failed":
+		e.FailedAt = v1.NewOptDateTime(created.Add(3 * time.Minute))
+	}
+	return e
+}
+
+func TestEventOf(t *testing.T) {
+	e := finished("e1", "Succeeded")
+	ev, ok := webhook.EventOf(&e)
+	require.True(t, ok)
+	assert.Equal(t, "e1/Succeeded", ev.ID)
+	assert.Equal(t, webhook.EventExecutionCompleted, ev.Type)
+	assert.Equal(t, "nightly", ev.WorkflowName)
+	assert.Equal(t, 3, ev.StepCount)
+	assert.Equal(t, 60.0, ev.QueuedSeconds)
+	assert.Equal(t, 120.0, ev.DurationSeconds)
+
+	running := workflowsmock.Execution{ID: "e2", Status: "Running"}.ListItem()
+	_, ok = webhook.EventOf(&running)
+	assert.False(t, ok)
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"e1/Succeeded"}`)
+	header := webhook.Sign("secret", created, body)
+	require.NoError(t, webhook.Verify("secret", header, body, 0, created.Add(time.Minute)))
+	assert.ErrorIs(t, webhook.Verify("other", header, body, 0, created), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify("secret", header, []byte(`{}`), 0, created), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify("secret", header, body, 0, created.Add(time.Hour)), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify("secret", "", body, 0, created), webhook.ErrInvalidSignature)
+}
+
+// receiver 受け取ったイベントを記録し、statusesの順に応答するhttptestのサーバー。statusesを使い切ると200を返す
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	events   []webhook.Event
+	attempts int
+}
+
+func newReceiver(t *testing.T, secret string, statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		assert.NoError(t, webhook.Verify(secret, req.Header.Get(webhook.SignatureHeader), body, 0, time.Now()))
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.attempts++
+		if len(r.statuses) > 0 {
+			status := r.statuses[0]
+			r.statuses = r.statuses[1:]
+			w.WriteHeader(status)
+			return
+		}
+		var ev webhook.Event
+		assert.NoError(t, json.Unmarshal(body, &ev))
+		assert.Equal(t, ev.ID, req.Header.Get(webhook.EventIDHeader))
+		r.events = append(r.events, ev)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for _, ev := range r.events {
+		ids = append(ids, ev.ID)
+	}
+	return ids
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	flaky := newReceiver(t, "s1", http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	rejecting := newReceiver(t, "s2", http.StatusBadRequest, http.StatusBadRequest)
+	failedOnly := newReceiver(t, "s3")
+	deadLetter := filepath.Join(t.TempDir(), "dead.jsonl")
+
+	t.Setenv("WEBHOOK_SECRET", "s2")
+	d, err := webhook.New(&workflows.Client{}, []webhook.Endpoint{
+		{Name: "flaky", URL: flaky.URL, Secret: "s1"},
+		{Name: "rejecting", URL: rejecting.URL, SecretEnv: "WEBHOOK_SECRET"},
+		{Name: "failed-only", URL: failedOnly.URL, Secret: "s3", Statuses: []string{"Failed"}},
+	}, webhook.WithBackoff(time.Millisecond), webhook.WithDeadLetter(webhook.NewFileDeadLetter(deadLetter)))
+	require.NoError(t, err)
+
+	e := finished("e1", "Succeeded")
+	ev, _ := webhook.EventOf(&e)
+	err = d.Dispatch(t.Context(), ev)
+	assert.ErrorContains(t, err, `webhook: endpoint "rejecting": event e1/Succeeded: unexpected status 400 Bad Request`)
+	assert.Equal(t, []string{"e1/Succeeded"}, flaky.received())
+	assert.Equal(t, 3, flaky.attempts)
+	assert.Equal(t, 1, rejecting.attempts, "4xx is not retried")
+	assert.Equal(t, 0, failedOnly.attempts)
+
+	f, err := os.Open(deadLetter)
+	require.NoError(t, err)
+	defer f.Close() //nolint:errcheck
+	letters, err := webhook.ReadDeadLetters(f)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, "rejecting", letters[0].Endpoint)
+	assert.Equal(t, 1, letters[0].Attempts)
+	assert.Equal(t, ev, letters[0].Event)
+
+	// fails once more, then the receiver accepts it
+	assert.Error(t, d.Redeliver(t.Context(), letters[0]))
+	require.NoError(t, d.Redeliver(t.Context(), letters[0]))
+	assert.Equal(t, []string{"e1/Succeeded"}, rejecting.received())
+}
+
+func TestNew_invalid(t *testing.T) {
+	for name, ep := range map[string]webhook.Endpoint{
+		"no name":     {URL: "https://example.com"},
+		"invalid URL": {Name: "x", URL: "ftp://example.com"},
+		"no secret":   {Name: "x", URL: "https://example.com", SecretEnv: "WEBHOOK_TEST_UNSET"},
+	} {
+		_, err := webhook.New(&workflows.Client{}, []webhook.Endpoint{ep})
+		assert.Error(t, err, name)
+	}
+	_, err := webhook.New(&workflows.Client{}, []webhook.Endpoint{{Name: "x", URL: "https://a"}, {Name: "x", URL: "https://b"}})
+	assert.ErrorContains(t, err, "duplicate")
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	start := created.Add(30 * time.Minute)
+	running := finished("e1", "Succeeded")
+	running.Status, running.SucceededAt = "Running", v1.OptDateTime{}
+	quick := finished("e2", "Failed")
+	quick.CreatedAt = start.Add(time.Second)
+
+	executions := workflowsmock.NewExecutionAPI(t)
+	// e0 finished before the dispatcher started and e1 is running; then e1 finishes and e2 is created and fails
+	executions.OnList(mock.Anything, mock.Anything).Return(&v1.ListExecutionOK{IsOk: true, Total: 2, Count: 2,
+		Executions: []v1.ListExecutionOKExecutionsItem{finished("e0", "Succeeded"), running}}, nil).Once()
+	executions.OnList(mock.Anything, mock.Anything).Return(&v1.ListExecutionOK{IsOk: true, Total: 3, Count: 3,
+		Executions: []v1.ListExecutionOKExecutionsItem{finished("e0", "Succeeded"), finished("e1", "Succeeded"), quick}}, nil)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	var mu sync.Mutex
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, req.Header.Get(webhook.EventIDHeader))
+		if len(got) == 2 {
+			cancel()
+		}
+	}))
+	defer srv.Close()
+
+	d, err := webhook.New(&workflows.Client{Executions: executions}, []webhook.Endpoint{{Name: "all", URL: srv.URL}},
+		webhook.WithWorkflows("wf"), webhook.WithClock(func() time.Time { return start }),
+		webhook.WithInformerOptions(informer.WithInterval(time.Millisecond)))
+	require.NoError(t, err)
+	require.ErrorIs(t, d.Run(ctx), context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	slices.Sort(got)
+	assert.Equal(t, []string{"e1/Succeeded", "e2/Failed"}, got)
+}
+
+func TestDispatcher_Run_slowEndpoint(t *testing.T) {
+	start := created.Add(30 * time.Minute)
+	executions := workflowsmock.NewExecutionAPI(t)
+	executions.OnList(mock.Anything, mock.Anything).Return(&v1.ListExecutionOK{IsOk: true}, nil).Once()
+	e1, e2 := finished("e1", "Succeeded"), finished("e2", "Failed")
+	e1.CreatedAt, e2.CreatedAt = start.Add(time.Second), start.Add(time.Second)
+	executions.OnList(mock.Anything, mock.Anything).Return(&v1.ListExecutionOK{IsOk: true, Total: 2, Count: 2,
+		Executions: []v1.ListExecutionOKExecutionsItem{e1, e2}}, nil)
+
+	// the slow endpoint never answers until the test ends
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	var mu sync.Mutex
+	var got []string
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, req.Header.Get(webhook.EventIDHeader))
+		if len(got) == 2 {
+			cancel()
+		}
+	}))
+	defer fast.Close()
+
+	var dead []webhook.DeadLetter
+	d, err := webhook.New(&workflows.Client{Executions: executions},
+		[]webhook.Endpoint{{Name: "slow", URL: slow.URL}, {Name: "fast", URL: fast.URL}},
+		webhook.WithWorkflows("wf"), webhook.WithClock(func() time.Time { return start }), webhook.WithWorkers(1),
+		webhook.WithDeadLetter(deadLetterFunc(func(dl webhook.DeadLetter) { mu.Lock(); defer mu.Unlock(); dead = append(dead, dl) })),
+		webhook.WithInformerOptions(informer.WithInterval(time.Millisecond)))
+	require.NoError(t, err)
+	require.ErrorIs(t, d.Run(ctx), context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	slices.Sort(got)
+	assert.Equal(t, []string{"e1/Succeeded", "e2/Failed"}, got)
+	// the slow endpoint's events are dead-lettered on shutdown, not lost
+	var slowIDs []string
+	for _, dl := range dead {
+		if dl.Endpoint == "slow" {
+			slowIDs = append(slowIDs, dl.Event.ID)
+		}
+	}
+	slices.Sort(slowIDs)
+	assert.Equal(t, []string{"e1/Succeeded", "e2/Failed"}, slowIDs)
+}
+
+// deadLetterFunc 関数で受け取るDeadLetterQueue
+type deadLetterFunc func(webhook.DeadLetter)
+
+func (f deadLetterFunc) Put(_ context.Context, dl webhook.DeadLetter) error {
+	f(dl)
+	return nil
+}
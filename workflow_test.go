@@ -17,8 +17,6 @@ package workflows_test
 import (
 	"testing"
 
-	"github.com/sacloud/packages-go/testutil"
-	"github.com/sacloud/saclient-go"
 	"github.com/sacloud/workflows-api-go"
 	v1 "github.com/sacloud/workflows-api-go/apis/v1"
 	"github.com/stretchr/testify/assert"
@@ -26,14 +24,9 @@ import (
 )
 
 func TestWorkflowAPI(t *testing.T) {
-	testutil.PreCheckEnvsFunc("SAKURA_ACCESS_TOKEN", "SAKURA_ACCESS_TOKEN_SECRET")(t)
-
+	client := newAcceptanceClient(t)
 	ctx := t.Context()
 
-	var theClient saclient.Client
-	client, err := workflows.NewClient(&theClient)
-	require.NoError(t, err)
-
 	workflowAPI := workflows.NewWorkflowOp(client)
 
 	// CreateWorkflow
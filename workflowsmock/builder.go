@@ -0,0 +1,225 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflowsmock
+
+import (
+	"encoding/json"
+	"time"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+// Workflow ワークフローの応答を組み立てるビルダー
+type Workflow struct {
+	ID              string
+	Name            string
+	Description     string
+	Publish         bool
+	Logging         bool
+	Tags            []string
+	ConcurrencyMode string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (w Workflow) base() v1.GetWorkflowOKWorkflow {
+	ret := v1.GetWorkflowOKWorkflow{
+		ID:          w.ID,
+		Name:        w.Name,
+		Description: v1.NewOptString(w.Description),
+		Publish:     w.Publish,
+		Logging:     w.Logging,
+		Tags:        []v1.GetWorkflowOKWorkflowTagsItem{},
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+	for _, tag := range w.Tags {
+		ret.Tags = append(ret.Tags, v1.GetWorkflowOKWorkflowTagsItem{Name: tag})
+	}
+	if w.ConcurrencyMode != "" {
+		ret.ConcurrencyMode = v1.NewOptGetWorkflowOKWorkflowConcurrencyMode(v1.GetWorkflowOKWorkflowConcurrencyMode(w.ConcurrencyMode))
+	}
+	return ret
+}
+
+// Get returns the workflow as returned by WorkflowAPI.Read
+func (w Workflow) Get() *v1.GetWorkflowOKWorkflow {
+	ret := w.base()
+	return &ret
+}
+
+// Created returns the workflow as returned by WorkflowAPI.Create
+func (w Workflow) Created() *v1.CreateWorkflowCreatedWorkflow {
+	return convert[v1.CreateWorkflowCreatedWorkflow](w.Get())
+}
+
+// Updated returns the workflow as returned by WorkflowAPI.Update
+func (w Workflow) Updated() *v1.UpdateWorkflowOKWorkflow {
+	return convert[v1.UpdateWorkflowOKWorkflow](w.Get())
+}
+
+// ListItem returns the workflow as an item of WorkflowAPI.List
+func (w Workflow) ListItem() v1.ListWorkflowOKWorkflowsItem {
+	return *convert[v1.ListWorkflowOKWorkflowsItem](w.Get())
+}
+
+// WorkflowList returns the response of WorkflowAPI.List containing the given workflows
+func WorkflowList(workflows ...Workflow) *v1.ListWorkflowOK {
+	ret := &v1.ListWorkflowOK{IsOk: true, Total: len(workflows), Count: len(workflows), Workflows: []v1.ListWorkflowOKWorkflowsItem{}}
+	for _, w := range workflows {
+		ret.Workflows = append(ret.Workflows, w.ListItem())
+	}
+	return ret
+}
+
+// Execution 実行の応答を組み立てるビルダー
+type Execution struct {
+	ID            string
+	Name          string
+	Workflow      Workflow
+	Status        string
+	Revision      int
+	RevisionAlias string
+	Args          string
+	StepCount     int
+	Result        string
+	Error         string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Get returns the execution as returned by ExecutionAPI.Read
+func (e Execution) Get() *v1.GetExecutionOKExecution {
+	status := e.Status
+	if status == "" {
+		status = string(v1.GetExecutionOKExecutionStatusQueued)
+	}
+	return &v1.GetExecutionOKExecution{
+		ExecutionId:   e.ID,
+		Name:          e.Name,
+		Workflow:      *convert[v1.GetExecutionOKExecutionWorkflow](e.Workflow.Get()),
+		Status:        v1.GetExecutionOKExecutionStatus(status),
+		Revision:      e.Revision,
+		RevisionAlias: e.RevisionAlias,
+		Args:          e.Args,
+		StepCount:     e.StepCount,
+		Result:        e.Result,
+		Error:         e.Error,
+		CreatedAt:     e.CreatedAt,
+		UpdatedAt:     e.UpdatedAt,
+	}
+}
+
+// Created returns the execution as returned by ExecutionAPI.Create
+func (e Execution) Created() *v1.CreateExecutionCreatedExecution {
+	return convert[v1.CreateExecutionCreatedExecution](e.Get())
+}
+
+// Canceled returns the execution as returned by ExecutionAPI.Cancel
+func (e Execution) Canceled() *v1.CancelExecutionOKExecution {
+	return convert[v1.CancelExecutionOKExecution](e.Get())
+}
+
+// ListItem returns the execution as an item of ExecutionAPI.List
+func (e Execution) ListItem() v1.ListExecutionOKExecutionsItem {
+	return *convert[v1.ListExecutionOKExecutionsItem](e.Get())
+}
+
+// ExecutionList returns the response of ExecutionAPI.List containing the given executions
+func ExecutionList(executions ...Execution) *v1.ListExecutionOK {
+	ret := &v1.ListExecutionOK{IsOk: true, Total: len(executions), Count: len(executions), Executions: []v1.ListExecutionOKExecutionsItem{}}
+	for _, e := range executions {
+		ret.Executions = append(ret.Executions, e.ListItem())
+	}
+	return ret
+}
+
+// History returns the response of ExecutionAPI.ListHistory containing the given events
+func History(events ...v1.ListExecutionHistoryOKHistoriesItem) *v1.ListExecutionHistoryOK {
+	if events == nil {
+		events = []v1.ListExecutionHistoryOKHistoriesItem{}
+	}
+	return &v1.ListExecutionHistoryOK{IsOk: true, Total: len(events), Count: len(events), Histories: events}
+}
+
+// Revision リビジョンの応答を組み立てるビルダー
+type Revision struct {
+	ID         int
+	WorkflowID string
+	Alias      string
+	Runbook    string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Get returns the revision as returned by RevisionAPI.Read
+func (r Revision) Get() *v1.GetWorkflowRevisionsOKRevision {
+	ret := &v1.GetWorkflowRevisionsOKRevision{
+		RevisionId: r.ID,
+		WorkflowId: r.WorkflowID,
+		Runbook:    r.Runbook,
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  r.UpdatedAt,
+	}
+	if r.Alias != "" {
+		ret.RevisionAlias = v1.NewOptString(r.Alias)
+	}
+	return ret
+}
+
+// Created returns the revision as returned by RevisionAPI.Create
+func (r Revision) Created() *v1.CreateWorkflowRevisionCreatedRevision {
+	return convert[v1.CreateWorkflowRevisionCreatedRevision](r.Get())
+}
+
+// AliasUpdated returns the revision as returned by RevisionAPI.UpdateAlias
+func (r Revision) AliasUpdated() *v1.UpdateWorkflowRevisionAliasOKRevision {
+	return convert[v1.UpdateWorkflowRevisionAliasOKRevision](r.Get())
+}
+
+// ListItem returns the revision as an item of RevisionAPI.List
+func (r Revision) ListItem() v1.ListWorkflowRevisionsOKRevisionsItem {
+	return *convert[v1.ListWorkflowRevisionsOKRevisionsItem](r.Get())
+}
+
+// RevisionList returns the response of RevisionAPI.List containing the given revisions
+func RevisionList(revisions ...Revision) *v1.ListWorkflowRevisionsOK {
+	ret := &v1.ListWorkflowRevisionsOK{IsOk: true, Total: len(revisions), Count: len(revisions), Revisions: []v1.ListWorkflowRevisionsOKRevisionsItem{}}
+	for _, r := range revisions {
+		ret.Revisions = append(ret.Revisions, r.ListItem())
+	}
+	return ret
+}
+
+// Plans returns the response of SubscriptionAPI.ListPlans containing the given plans
+func Plans(taxRate int, plans ...v1.ListPlansOKPlansItem) *v1.ListPlansOK {
+	if plans == nil {
+		plans = []v1.ListPlansOKPlansItem{}
+	}
+	return &v1.ListPlansOK{IsOk: true, Plans: plans, TaxRate: taxRate}
+}
+
+// convert copies a generated type into another generated type of the same shape
+func convert[T any](src json.Marshaler) *T {
+	data, err := src.MarshalJSON()
+	if err != nil {
+		panic(err)
+	}
+	var ret T
+	if err := json.Unmarshal(data, &ret); err != nil {
+		panic(err)
+	}
+	return &ret
+}
@@ -0,0 +1,31 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package workflowsmock provides testify based mocks of WorkflowAPI, ExecutionAPI, RevisionAPI and SubscriptionAPI.
+//
+// The mocks in mock_gen.go are generated from the interfaces by internal/mockgen; run `make mock` after changing them.
+//
+//	api := workflowsmock.NewWorkflowAPI(t)
+//	api.OnRead(mock.Anything, "123456789012").Return(workflowsmock.Workflow{ID: "123456789012", Name: "example"}.Get(), nil)
+package workflowsmock
+
+import "github.com/stretchr/testify/mock"
+
+//go:generate go run ../internal/mockgen -source .. -out mock_gen.go
+
+// TestingT is the subset of testing.T used by the mocks
+type TestingT interface {
+	mock.TestingT
+	Cleanup(func())
+}
@@ -0,0 +1,1080 @@
+// Code generated by internal/mockgen, DO NOT EDIT.
+
+package workflowsmock
+
+import (
+	"context"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/stretchr/testify/mock"
+)
+
+// WorkflowAPI is a mock of workflows.WorkflowAPI
+type WorkflowAPI struct {
+	mock.Mock
+}
+
+var _ workflows.WorkflowAPI = (*WorkflowAPI)(nil)
+
+// NewWorkflowAPI creates a new WorkflowAPI whose expectations are asserted when the test finishes
+func NewWorkflowAPI(t TestingT) *WorkflowAPI {
+	m := &WorkflowAPI{}
+	m.Test(t)
+	t.Cleanup(func() { m.AssertExpectations(t) })
+	return m
+}
+
+// Create implements workflows.WorkflowAPI
+func (m *WorkflowAPI) Create(ctx context.Context, request v1.CreateWorkflowReq) (*v1.CreateWorkflowCreatedWorkflow, error) {
+	ret := m.Called(ctx, request)
+
+	if f, ok := ret.Get(0).(func(context.Context, v1.CreateWorkflowReq) (*v1.CreateWorkflowCreatedWorkflow, error)); ok {
+		return f(ctx, request)
+	}
+
+	var r0 *v1.CreateWorkflowCreatedWorkflow
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.CreateWorkflowCreatedWorkflow)
+	}
+	return r0, ret.Error(1)
+}
+
+// WorkflowAPICreateCall is an expectation of WorkflowAPI.Create
+type WorkflowAPICreateCall struct {
+	*mock.Call
+}
+
+// OnCreate expects a call of Create. Each argument is a value or a matcher such as mock.Anything.
+func (m *WorkflowAPI) OnCreate(ctx any, request any) *WorkflowAPICreateCall {
+	return &WorkflowAPICreateCall{Call: m.On("Create", ctx, request)}
+}
+
+// Return sets the values returned by the call
+func (c *WorkflowAPICreateCall) Return(r0 *v1.CreateWorkflowCreatedWorkflow, err error) *WorkflowAPICreateCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *WorkflowAPICreateCall) RunAndReturn(f func(context.Context, v1.CreateWorkflowReq) (*v1.CreateWorkflowCreatedWorkflow, error)) *WorkflowAPICreateCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *WorkflowAPICreateCall) Once() *WorkflowAPICreateCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *WorkflowAPICreateCall) Times(n int) *WorkflowAPICreateCall {
+	c.Call.Times(n)
+	return c
+}
+
+// List implements workflows.WorkflowAPI
+func (m *WorkflowAPI) List(ctx context.Context, parameter v1.ListWorkflowParams) (*v1.ListWorkflowOK, error) {
+	ret := m.Called(ctx, parameter)
+
+	if f, ok := ret.Get(0).(func(context.Context, v1.ListWorkflowParams) (*v1.ListWorkflowOK, error)); ok {
+		return f(ctx, parameter)
+	}
+
+	var r0 *v1.ListWorkflowOK
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.ListWorkflowOK)
+	}
+	return r0, ret.Error(1)
+}
+
+// WorkflowAPIListCall is an expectation of WorkflowAPI.List
+type WorkflowAPIListCall struct {
+	*mock.Call
+}
+
+// OnList expects a call of List. Each argument is a value or a matcher such as mock.Anything.
+func (m *WorkflowAPI) OnList(ctx any, parameter any) *WorkflowAPIListCall {
+	return &WorkflowAPIListCall{Call: m.On("List", ctx, parameter)}
+}
+
+// Return sets the values returned by the call
+func (c *WorkflowAPIListCall) Return(r0 *v1.ListWorkflowOK, err error) *WorkflowAPIListCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *WorkflowAPIListCall) RunAndReturn(f func(context.Context, v1.ListWorkflowParams) (*v1.ListWorkflowOK, error)) *WorkflowAPIListCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *WorkflowAPIListCall) Once() *WorkflowAPIListCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *WorkflowAPIListCall) Times(n int) *WorkflowAPIListCall {
+	c.Call.Times(n)
+	return c
+}
+
+// ListSuggest implements workflows.WorkflowAPI
+func (m *WorkflowAPI) ListSuggest(ctx context.Context, parameter v1.ListWorkflowSuggestParams) (*v1.ListWorkflowSuggestOK, error) {
+	ret := m.Called(ctx, parameter)
+
+	if f, ok := ret.Get(0).(func(context.Context, v1.ListWorkflowSuggestParams) (*v1.ListWorkflowSuggestOK, error)); ok {
+		return f(ctx, parameter)
+	}
+
+	var r0 *v1.ListWorkflowSuggestOK
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.ListWorkflowSuggestOK)
+	}
+	return r0, ret.Error(1)
+}
+
+// WorkflowAPIListSuggestCall is an expectation of WorkflowAPI.ListSuggest
+type WorkflowAPIListSuggestCall struct {
+	*mock.Call
+}
+
+// OnListSuggest expects a call of ListSuggest. Each argument is a value or a matcher such as mock.Anything.
+func (m *WorkflowAPI) OnListSuggest(ctx any, parameter any) *WorkflowAPIListSuggestCall {
+	return &WorkflowAPIListSuggestCall{Call: m.On("ListSuggest", ctx, parameter)}
+}
+
+// Return sets the values returned by the call
+func (c *WorkflowAPIListSuggestCall) Return(r0 *v1.ListWorkflowSuggestOK, err error) *WorkflowAPIListSuggestCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *WorkflowAPIListSuggestCall) RunAndReturn(f func(context.Context, v1.ListWorkflowSuggestParams) (*v1.ListWorkflowSuggestOK, error)) *WorkflowAPIListSuggestCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *WorkflowAPIListSuggestCall) Once() *WorkflowAPIListSuggestCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *WorkflowAPIListSuggestCall) Times(n int) *WorkflowAPIListSuggestCall {
+	c.Call.Times(n)
+	return c
+}
+
+// Read implements workflows.WorkflowAPI
+func (m *WorkflowAPI) Read(ctx context.Context, id string) (*v1.GetWorkflowOKWorkflow, error) {
+	ret := m.Called(ctx, id)
+
+	if f, ok := ret.Get(0).(func(context.Context, string) (*v1.GetWorkflowOKWorkflow, error)); ok {
+		return f(ctx, id)
+	}
+
+	var r0 *v1.GetWorkflowOKWorkflow
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.GetWorkflowOKWorkflow)
+	}
+	return r0, ret.Error(1)
+}
+
+// WorkflowAPIReadCall is an expectation of WorkflowAPI.Read
+type WorkflowAPIReadCall struct {
+	*mock.Call
+}
+
+// OnRead expects a call of Read. Each argument is a value or a matcher such as mock.Anything.
+func (m *WorkflowAPI) OnRead(ctx any, id any) *WorkflowAPIReadCall {
+	return &WorkflowAPIReadCall{Call: m.On("Read", ctx, id)}
+}
+
+// Return sets the values returned by the call
+func (c *WorkflowAPIReadCall) Return(r0 *v1.GetWorkflowOKWorkflow, err error) *WorkflowAPIReadCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *WorkflowAPIReadCall) RunAndReturn(f func(context.Context, string) (*v1.GetWorkflowOKWorkflow, error)) *WorkflowAPIReadCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *WorkflowAPIReadCall) Once() *WorkflowAPIReadCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *WorkflowAPIReadCall) Times(n int) *WorkflowAPIReadCall {
+	c.Call.Times(n)
+	return c
+}
+
+// Update implements workflows.WorkflowAPI
+func (m *WorkflowAPI) Update(ctx context.Context, id string, request v1.UpdateWorkflowReq) (*v1.UpdateWorkflowOKWorkflow, error) {
+	ret := m.Called(ctx, id, request)
+
+	if f, ok := ret.Get(0).(func(context.Context, string, v1.UpdateWorkflowReq) (*v1.UpdateWorkflowOKWorkflow, error)); ok {
+		return f(ctx, id, request)
+	}
+
+	var r0 *v1.UpdateWorkflowOKWorkflow
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.UpdateWorkflowOKWorkflow)
+	}
+	return r0, ret.Error(1)
+}
+
+// WorkflowAPIUpdateCall is an expectation of WorkflowAPI.Update
+type WorkflowAPIUpdateCall struct {
+	*mock.Call
+}
+
+// OnUpdate expects a call of Update. Each argument is a value or a matcher such as mock.Anything.
+func (m *WorkflowAPI) OnUpdate(ctx any, id any, request any) *WorkflowAPIUpdateCall {
+	return &WorkflowAPIUpdateCall{Call: m.On("Update", ctx, id, request)}
+}
+
+// Return sets the values returned by the call
+func (c *WorkflowAPIUpdateCall) Return(r0 *v1.UpdateWorkflowOKWorkflow, err error) *WorkflowAPIUpdateCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *WorkflowAPIUpdateCall) RunAndReturn(f func(context.Context, string, v1.UpdateWorkflowReq) (*v1.UpdateWorkflowOKWorkflow, error)) *WorkflowAPIUpdateCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *WorkflowAPIUpdateCall) Once() *WorkflowAPIUpdateCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *WorkflowAPIUpdateCall) Times(n int) *WorkflowAPIUpdateCall {
+	c.Call.Times(n)
+	return c
+}
+
+// Delete implements workflows.WorkflowAPI
+func (m *WorkflowAPI) Delete(ctx context.Context, id string) error {
+	ret := m.Called(ctx, id)
+
+	if f, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		return f(ctx, id)
+	}
+
+	return ret.Error(0)
+}
+
+// WorkflowAPIDeleteCall is an expectation of WorkflowAPI.Delete
+type WorkflowAPIDeleteCall struct {
+	*mock.Call
+}
+
+// OnDelete expects a call of Delete. Each argument is a value or a matcher such as mock.Anything.
+func (m *WorkflowAPI) OnDelete(ctx any, id any) *WorkflowAPIDeleteCall {
+	return &WorkflowAPIDeleteCall{Call: m.On("Delete", ctx, id)}
+}
+
+// Return sets the values returned by the call
+func (c *WorkflowAPIDeleteCall) Return(err error) *WorkflowAPIDeleteCall {
+	c.Call.Return(err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *WorkflowAPIDeleteCall) RunAndReturn(f func(context.Context, string) error) *WorkflowAPIDeleteCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *WorkflowAPIDeleteCall) Once() *WorkflowAPIDeleteCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *WorkflowAPIDeleteCall) Times(n int) *WorkflowAPIDeleteCall {
+	c.Call.Times(n)
+	return c
+}
+
+// ExecutionAPI is a mock of workflows.ExecutionAPI
+type ExecutionAPI struct {
+	mock.Mock
+}
+
+var _ workflows.ExecutionAPI = (*ExecutionAPI)(nil)
+
+// NewExecutionAPI creates a new ExecutionAPI whose expectations are asserted when the test finishes
+func NewExecutionAPI(t TestingT) *ExecutionAPI {
+	m := &ExecutionAPI{}
+	m.Test(t)
+	t.Cleanup(func() { m.AssertExpectations(t) })
+	return m
+}
+
+// Create implements workflows.ExecutionAPI
+func (m *ExecutionAPI) Create(ctx context.Context, workflowID string, req v1.OptCreateExecutionReq) (*v1.CreateExecutionCreatedExecution, error) {
+	ret := m.Called(ctx, workflowID, req)
+
+	if f, ok := ret.Get(0).(func(context.Context, string, v1.OptCreateExecutionReq) (*v1.CreateExecutionCreatedExecution, error)); ok {
+		return f(ctx, workflowID, req)
+	}
+
+	var r0 *v1.CreateExecutionCreatedExecution
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.CreateExecutionCreatedExecution)
+	}
+	return r0, ret.Error(1)
+}
+
+// ExecutionAPICreateCall is an expectation of ExecutionAPI.Create
+type ExecutionAPICreateCall struct {
+	*mock.Call
+}
+
+// OnCreate expects a call of Create. Each argument is a value or a matcher such as mock.Anything.
+func (m *ExecutionAPI) OnCreate(ctx any, workflowID any, req any) *ExecutionAPICreateCall {
+	return &ExecutionAPICreateCall{Call: m.On("Create", ctx, workflowID, req)}
+}
+
+// Return sets the values returned by the call
+func (c *ExecutionAPICreateCall) Return(r0 *v1.CreateExecutionCreatedExecution, err error) *ExecutionAPICreateCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *ExecutionAPICreateCall) RunAndReturn(f func(context.Context, string, v1.OptCreateExecutionReq) (*v1.CreateExecutionCreatedExecution, error)) *ExecutionAPICreateCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *ExecutionAPICreateCall) Once() *ExecutionAPICreateCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *ExecutionAPICreateCall) Times(n int) *ExecutionAPICreateCall {
+	c.Call.Times(n)
+	return c
+}
+
+// List implements workflows.ExecutionAPI
+func (m *ExecutionAPI) List(ctx context.Context, params v1.ListExecutionParams) (*v1.ListExecutionOK, error) {
+	ret := m.Called(ctx, params)
+
+	if f, ok := ret.Get(0).(func(context.Context, v1.ListExecutionParams) (*v1.ListExecutionOK, error)); ok {
+		return f(ctx, params)
+	}
+
+	var r0 *v1.ListExecutionOK
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.ListExecutionOK)
+	}
+	return r0, ret.Error(1)
+}
+
+// ExecutionAPIListCall is an expectation of ExecutionAPI.List
+type ExecutionAPIListCall struct {
+	*mock.Call
+}
+
+// OnList expects a call of List. Each argument is a value or a matcher such as mock.Anything.
+func (m *ExecutionAPI) OnList(ctx any, params any) *ExecutionAPIListCall {
+	return &ExecutionAPIListCall{Call: m.On("List", ctx, params)}
+}
+
+// Return sets the values returned by the call
+func (c *ExecutionAPIListCall) Return(r0 *v1.ListExecutionOK, err error) *ExecutionAPIListCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *ExecutionAPIListCall) RunAndReturn(f func(context.Context, v1.ListExecutionParams) (*v1.ListExecutionOK, error)) *ExecutionAPIListCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *ExecutionAPIListCall) Once() *ExecutionAPIListCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *ExecutionAPIListCall) Times(n int) *ExecutionAPIListCall {
+	c.Call.Times(n)
+	return c
+}
+
+// Read implements workflows.ExecutionAPI
+func (m *ExecutionAPI) Read(ctx context.Context, workflowID string, executionID string) (*v1.GetExecutionOKExecution, error) {
+	ret := m.Called(ctx, workflowID, executionID)
+
+	if f, ok := ret.Get(0).(func(context.Context, string, string) (*v1.GetExecutionOKExecution, error)); ok {
+		return f(ctx, workflowID, executionID)
+	}
+
+	var r0 *v1.GetExecutionOKExecution
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.GetExecutionOKExecution)
+	}
+	return r0, ret.Error(1)
+}
+
+// ExecutionAPIReadCall is an expectation of ExecutionAPI.Read
+type ExecutionAPIReadCall struct {
+	*mock.Call
+}
+
+// OnRead expects a call of Read. Each argument is a value or a matcher such as mock.Anything.
+func (m *ExecutionAPI) OnRead(ctx any, workflowID any, executionID any) *ExecutionAPIReadCall {
+	return &ExecutionAPIReadCall{Call: m.On("Read", ctx, workflowID, executionID)}
+}
+
+// Return sets the values returned by the call
+func (c *ExecutionAPIReadCall) Return(r0 *v1.GetExecutionOKExecution, err error) *ExecutionAPIReadCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *ExecutionAPIReadCall) RunAndReturn(f func(context.Context, string, string) (*v1.GetExecutionOKExecution, error)) *ExecutionAPIReadCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *ExecutionAPIReadCall) Once() *ExecutionAPIReadCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *ExecutionAPIReadCall) Times(n int) *ExecutionAPIReadCall {
+	c.Call.Times(n)
+	return c
+}
+
+// Cancel implements workflows.ExecutionAPI
+func (m *ExecutionAPI) Cancel(ctx context.Context, workflowID string, executionID string) (*v1.CancelExecutionOKExecution, error) {
+	ret := m.Called(ctx, workflowID, executionID)
+
+	if f, ok := ret.Get(0).(func(context.Context, string, string) (*v1.CancelExecutionOKExecution, error)); ok {
+		return f(ctx, workflowID, executionID)
+	}
+
+	var r0 *v1.CancelExecutionOKExecution
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.CancelExecutionOKExecution)
+	}
+	return r0, ret.Error(1)
+}
+
+// ExecutionAPICancelCall is an expectation of ExecutionAPI.Cancel
+type ExecutionAPICancelCall struct {
+	*mock.Call
+}
+
+// OnCancel expects a call of Cancel. Each argument is a value or a matcher such as mock.Anything.
+func (m *ExecutionAPI) OnCancel(ctx any, workflowID any, executionID any) *ExecutionAPICancelCall {
+	return &ExecutionAPICancelCall{Call: m.On("Cancel", ctx, workflowID, executionID)}
+}
+
+// Return sets the values returned by the call
+func (c *ExecutionAPICancelCall) Return(r0 *v1.CancelExecutionOKExecution, err error) *ExecutionAPICancelCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *ExecutionAPICancelCall) RunAndReturn(f func(context.Context, string, string) (*v1.CancelExecutionOKExecution, error)) *ExecutionAPICancelCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *ExecutionAPICancelCall) Once() *ExecutionAPICancelCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *ExecutionAPICancelCall) Times(n int) *ExecutionAPICancelCall {
+	c.Call.Times(n)
+	return c
+}
+
+// Delete implements workflows.ExecutionAPI
+func (m *ExecutionAPI) Delete(ctx context.Context, workflowID string, executionID string) error {
+	ret := m.Called(ctx, workflowID, executionID)
+
+	if f, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		return f(ctx, workflowID, executionID)
+	}
+
+	return ret.Error(0)
+}
+
+// ExecutionAPIDeleteCall is an expectation of ExecutionAPI.Delete
+type ExecutionAPIDeleteCall struct {
+	*mock.Call
+}
+
+// OnDelete expects a call of Delete. Each argument is a value or a matcher such as mock.Anything.
+func (m *ExecutionAPI) OnDelete(ctx any, workflowID any, executionID any) *ExecutionAPIDeleteCall {
+	return &ExecutionAPIDeleteCall{Call: m.On("Delete", ctx, workflowID, executionID)}
+}
+
+// Return sets the values returned by the call
+func (c *ExecutionAPIDeleteCall) Return(err error) *ExecutionAPIDeleteCall {
+	c.Call.Return(err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *ExecutionAPIDeleteCall) RunAndReturn(f func(context.Context, string, string) error) *ExecutionAPIDeleteCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *ExecutionAPIDeleteCall) Once() *ExecutionAPIDeleteCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *ExecutionAPIDeleteCall) Times(n int) *ExecutionAPIDeleteCall {
+	c.Call.Times(n)
+	return c
+}
+
+// ListHistory implements workflows.ExecutionAPI
+func (m *ExecutionAPI) ListHistory(ctx context.Context, params v1.ListExecutionHistoryParams) (*v1.ListExecutionHistoryOK, error) {
+	ret := m.Called(ctx, params)
+
+	if f, ok := ret.Get(0).(func(context.Context, v1.ListExecutionHistoryParams) (*v1.ListExecutionHistoryOK, error)); ok {
+		return f(ctx, params)
+	}
+
+	var r0 *v1.ListExecutionHistoryOK
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.ListExecutionHistoryOK)
+	}
+	return r0, ret.Error(1)
+}
+
+// ExecutionAPIListHistoryCall is an expectation of ExecutionAPI.ListHistory
+type ExecutionAPIListHistoryCall struct {
+	*mock.Call
+}
+
+// OnListHistory expects a call of ListHistory. Each argument is a value or a matcher such as mock.Anything.
+func (m *ExecutionAPI) OnListHistory(ctx any, params any) *ExecutionAPIListHistoryCall {
+	return &ExecutionAPIListHistoryCall{Call: m.On("ListHistory", ctx, params)}
+}
+
+// Return sets the values returned by the call
+func (c *ExecutionAPIListHistoryCall) Return(r0 *v1.ListExecutionHistoryOK, err error) *ExecutionAPIListHistoryCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *ExecutionAPIListHistoryCall) RunAndReturn(f func(context.Context, v1.ListExecutionHistoryParams) (*v1.ListExecutionHistoryOK, error)) *ExecutionAPIListHistoryCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *ExecutionAPIListHistoryCall) Once() *ExecutionAPIListHistoryCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *ExecutionAPIListHistoryCall) Times(n int) *ExecutionAPIListHistoryCall {
+	c.Call.Times(n)
+	return c
+}
+
+// RevisionAPI is a mock of workflows.RevisionAPI
+type RevisionAPI struct {
+	mock.Mock
+}
+
+var _ workflows.RevisionAPI = (*RevisionAPI)(nil)
+
+// NewRevisionAPI creates a new RevisionAPI whose expectations are asserted when the test finishes
+func NewRevisionAPI(t TestingT) *RevisionAPI {
+	m := &RevisionAPI{}
+	m.Test(t)
+	t.Cleanup(func() { m.AssertExpectations(t) })
+	return m
+}
+
+// Create implements workflows.RevisionAPI
+func (m *RevisionAPI) Create(ctx context.Context, workflowID string, req v1.CreateWorkflowRevisionReq) (*v1.CreateWorkflowRevisionCreatedRevision, error) {
+	ret := m.Called(ctx, workflowID, req)
+
+	if f, ok := ret.Get(0).(func(context.Context, string, v1.CreateWorkflowRevisionReq) (*v1.CreateWorkflowRevisionCreatedRevision, error)); ok {
+		return f(ctx, workflowID, req)
+	}
+
+	var r0 *v1.CreateWorkflowRevisionCreatedRevision
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.CreateWorkflowRevisionCreatedRevision)
+	}
+	return r0, ret.Error(1)
+}
+
+// RevisionAPICreateCall is an expectation of RevisionAPI.Create
+type RevisionAPICreateCall struct {
+	*mock.Call
+}
+
+// OnCreate expects a call of Create. Each argument is a value or a matcher such as mock.Anything.
+func (m *RevisionAPI) OnCreate(ctx any, workflowID any, req any) *RevisionAPICreateCall {
+	return &RevisionAPICreateCall{Call: m.On("Create", ctx, workflowID, req)}
+}
+
+// Return sets the values returned by the call
+func (c *RevisionAPICreateCall) Return(r0 *v1.CreateWorkflowRevisionCreatedRevision, err error) *RevisionAPICreateCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *RevisionAPICreateCall) RunAndReturn(f func(context.Context, string, v1.CreateWorkflowRevisionReq) (*v1.CreateWorkflowRevisionCreatedRevision, error)) *RevisionAPICreateCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *RevisionAPICreateCall) Once() *RevisionAPICreateCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *RevisionAPICreateCall) Times(n int) *RevisionAPICreateCall {
+	c.Call.Times(n)
+	return c
+}
+
+// List implements workflows.RevisionAPI
+func (m *RevisionAPI) List(ctx context.Context, params v1.ListWorkflowRevisionsParams) (*v1.ListWorkflowRevisionsOK, error) {
+	ret := m.Called(ctx, params)
+
+	if f, ok := ret.Get(0).(func(context.Context, v1.ListWorkflowRevisionsParams) (*v1.ListWorkflowRevisionsOK, error)); ok {
+		return f(ctx, params)
+	}
+
+	var r0 *v1.ListWorkflowRevisionsOK
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.ListWorkflowRevisionsOK)
+	}
+	return r0, ret.Error(1)
+}
+
+// RevisionAPIListCall is an expectation of RevisionAPI.List
+type RevisionAPIListCall struct {
+	*mock.Call
+}
+
+// OnList expects a call of List. Each argument is a value or a matcher such as mock.Anything.
+func (m *RevisionAPI) OnList(ctx any, params any) *RevisionAPIListCall {
+	return &RevisionAPIListCall{Call: m.On("List", ctx, params)}
+}
+
+// Return sets the values returned by the call
+func (c *RevisionAPIListCall) Return(r0 *v1.ListWorkflowRevisionsOK, err error) *RevisionAPIListCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *RevisionAPIListCall) RunAndReturn(f func(context.Context, v1.ListWorkflowRevisionsParams) (*v1.ListWorkflowRevisionsOK, error)) *RevisionAPIListCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *RevisionAPIListCall) Once() *RevisionAPIListCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *RevisionAPIListCall) Times(n int) *RevisionAPIListCall {
+	c.Call.Times(n)
+	return c
+}
+
+// Read implements workflows.RevisionAPI
+func (m *RevisionAPI) Read(ctx context.Context, workflowID string, revisionNumber int) (*v1.GetWorkflowRevisionsOKRevision, error) {
+	ret := m.Called(ctx, workflowID, revisionNumber)
+
+	if f, ok := ret.Get(0).(func(context.Context, string, int) (*v1.GetWorkflowRevisionsOKRevision, error)); ok {
+		return f(ctx, workflowID, revisionNumber)
+	}
+
+	var r0 *v1.GetWorkflowRevisionsOKRevision
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.GetWorkflowRevisionsOKRevision)
+	}
+	return r0, ret.Error(1)
+}
+
+// RevisionAPIReadCall is an expectation of RevisionAPI.Read
+type RevisionAPIReadCall struct {
+	*mock.Call
+}
+
+// OnRead expects a call of Read. Each argument is a value or a matcher such as mock.Anything.
+func (m *RevisionAPI) OnRead(ctx any, workflowID any, revisionNumber any) *RevisionAPIReadCall {
+	return &RevisionAPIReadCall{Call: m.On("Read", ctx, workflowID, revisionNumber)}
+}
+
+// Return sets the values returned by the call
+func (c *RevisionAPIReadCall) Return(r0 *v1.GetWorkflowRevisionsOKRevision, err error) *RevisionAPIReadCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *RevisionAPIReadCall) RunAndReturn(f func(context.Context, string, int) (*v1.GetWorkflowRevisionsOKRevision, error)) *RevisionAPIReadCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *RevisionAPIReadCall) Once() *RevisionAPIReadCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *RevisionAPIReadCall) Times(n int) *RevisionAPIReadCall {
+	c.Call.Times(n)
+	return c
+}
+
+// UpdateAlias implements workflows.RevisionAPI
+func (m *RevisionAPI) UpdateAlias(ctx context.Context, workflowID string, revisionNumber int, req v1.UpdateWorkflowRevisionAliasReq) (*v1.UpdateWorkflowRevisionAliasOKRevision, error) {
+	ret := m.Called(ctx, workflowID, revisionNumber, req)
+
+	if f, ok := ret.Get(0).(func(context.Context, string, int, v1.UpdateWorkflowRevisionAliasReq) (*v1.UpdateWorkflowRevisionAliasOKRevision, error)); ok {
+		return f(ctx, workflowID, revisionNumber, req)
+	}
+
+	var r0 *v1.UpdateWorkflowRevisionAliasOKRevision
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.UpdateWorkflowRevisionAliasOKRevision)
+	}
+	return r0, ret.Error(1)
+}
+
+// RevisionAPIUpdateAliasCall is an expectation of RevisionAPI.UpdateAlias
+type RevisionAPIUpdateAliasCall struct {
+	*mock.Call
+}
+
+// OnUpdateAlias expects a call of UpdateAlias. Each argument is a value or a matcher such as mock.Anything.
+func (m *RevisionAPI) OnUpdateAlias(ctx any, workflowID any, revisionNumber any, req any) *RevisionAPIUpdateAliasCall {
+	return &RevisionAPIUpdateAliasCall{Call: m.On("UpdateAlias", ctx, workflowID, revisionNumber, req)}
+}
+
+// Return sets the values returned by the call
+func (c *RevisionAPIUpdateAliasCall) Return(r0 *v1.UpdateWorkflowRevisionAliasOKRevision, err error) *RevisionAPIUpdateAliasCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *RevisionAPIUpdateAliasCall) RunAndReturn(f func(context.Context, string, int, v1.UpdateWorkflowRevisionAliasReq) (*v1.UpdateWorkflowRevisionAliasOKRevision, error)) *RevisionAPIUpdateAliasCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *RevisionAPIUpdateAliasCall) Once() *RevisionAPIUpdateAliasCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *RevisionAPIUpdateAliasCall) Times(n int) *RevisionAPIUpdateAliasCall {
+	c.Call.Times(n)
+	return c
+}
+
+// DeleteAlias implements workflows.RevisionAPI
+func (m *RevisionAPI) DeleteAlias(ctx context.Context, workflowID string, revisionNumber int) error {
+	ret := m.Called(ctx, workflowID, revisionNumber)
+
+	if f, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		return f(ctx, workflowID, revisionNumber)
+	}
+
+	return ret.Error(0)
+}
+
+// RevisionAPIDeleteAliasCall is an expectation of RevisionAPI.DeleteAlias
+type RevisionAPIDeleteAliasCall struct {
+	*mock.Call
+}
+
+// OnDeleteAlias expects a call of DeleteAlias. Each argument is a value or a matcher such as mock.Anything.
+func (m *RevisionAPI) OnDeleteAlias(ctx any, workflowID any, revisionNumber any) *RevisionAPIDeleteAliasCall {
+	return &RevisionAPIDeleteAliasCall{Call: m.On("DeleteAlias", ctx, workflowID, revisionNumber)}
+}
+
+// Return sets the values returned by the call
+func (c *RevisionAPIDeleteAliasCall) Return(err error) *RevisionAPIDeleteAliasCall {
+	c.Call.Return(err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *RevisionAPIDeleteAliasCall) RunAndReturn(f func(context.Context, string, int) error) *RevisionAPIDeleteAliasCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *RevisionAPIDeleteAliasCall) Once() *RevisionAPIDeleteAliasCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *RevisionAPIDeleteAliasCall) Times(n int) *RevisionAPIDeleteAliasCall {
+	c.Call.Times(n)
+	return c
+}
+
+// SubscriptionAPI is a mock of workflows.SubscriptionAPI
+type SubscriptionAPI struct {
+	mock.Mock
+}
+
+var _ workflows.SubscriptionAPI = (*SubscriptionAPI)(nil)
+
+// NewSubscriptionAPI creates a new SubscriptionAPI whose expectations are asserted when the test finishes
+func NewSubscriptionAPI(t TestingT) *SubscriptionAPI {
+	m := &SubscriptionAPI{}
+	m.Test(t)
+	t.Cleanup(func() { m.AssertExpectations(t) })
+	return m
+}
+
+// ListPlans implements workflows.SubscriptionAPI
+func (m *SubscriptionAPI) ListPlans(ctx context.Context) (*v1.ListPlansOK, error) {
+	ret := m.Called(ctx)
+
+	if f, ok := ret.Get(0).(func(context.Context) (*v1.ListPlansOK, error)); ok {
+		return f(ctx)
+	}
+
+	var r0 *v1.ListPlansOK
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.ListPlansOK)
+	}
+	return r0, ret.Error(1)
+}
+
+// SubscriptionAPIListPlansCall is an expectation of SubscriptionAPI.ListPlans
+type SubscriptionAPIListPlansCall struct {
+	*mock.Call
+}
+
+// OnListPlans expects a call of ListPlans. Each argument is a value or a matcher such as mock.Anything.
+func (m *SubscriptionAPI) OnListPlans(ctx any) *SubscriptionAPIListPlansCall {
+	return &SubscriptionAPIListPlansCall{Call: m.On("ListPlans", ctx)}
+}
+
+// Return sets the values returned by the call
+func (c *SubscriptionAPIListPlansCall) Return(r0 *v1.ListPlansOK, err error) *SubscriptionAPIListPlansCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *SubscriptionAPIListPlansCall) RunAndReturn(f func(context.Context) (*v1.ListPlansOK, error)) *SubscriptionAPIListPlansCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *SubscriptionAPIListPlansCall) Once() *SubscriptionAPIListPlansCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *SubscriptionAPIListPlansCall) Times(n int) *SubscriptionAPIListPlansCall {
+	c.Call.Times(n)
+	return c
+}
+
+// Read implements workflows.SubscriptionAPI
+func (m *SubscriptionAPI) Read(ctx context.Context) (*v1.GetSubscriptionOK, error) {
+	ret := m.Called(ctx)
+
+	if f, ok := ret.Get(0).(func(context.Context) (*v1.GetSubscriptionOK, error)); ok {
+		return f(ctx)
+	}
+
+	var r0 *v1.GetSubscriptionOK
+	if v := ret.Get(0); v != nil {
+		r0 = v.(*v1.GetSubscriptionOK)
+	}
+	return r0, ret.Error(1)
+}
+
+// SubscriptionAPIReadCall is an expectation of SubscriptionAPI.Read
+type SubscriptionAPIReadCall struct {
+	*mock.Call
+}
+
+// OnRead expects a call of Read. Each argument is a value or a matcher such as mock.Anything.
+func (m *SubscriptionAPI) OnRead(ctx any) *SubscriptionAPIReadCall {
+	return &SubscriptionAPIReadCall{Call: m.On("Read", ctx)}
+}
+
+// Return sets the values returned by the call
+func (c *SubscriptionAPIReadCall) Return(r0 *v1.GetSubscriptionOK, err error) *SubscriptionAPIReadCall {
+	c.Call.Return(r0, err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *SubscriptionAPIReadCall) RunAndReturn(f func(context.Context) (*v1.GetSubscriptionOK, error)) *SubscriptionAPIReadCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *SubscriptionAPIReadCall) Once() *SubscriptionAPIReadCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *SubscriptionAPIReadCall) Times(n int) *SubscriptionAPIReadCall {
+	c.Call.Times(n)
+	return c
+}
+
+// Create implements workflows.SubscriptionAPI
+func (m *SubscriptionAPI) Create(ctx context.Context, request v1.CreateSubscriptionReq) error {
+	ret := m.Called(ctx, request)
+
+	if f, ok := ret.Get(0).(func(context.Context, v1.CreateSubscriptionReq) error); ok {
+		return f(ctx, request)
+	}
+
+	return ret.Error(0)
+}
+
+// SubscriptionAPICreateCall is an expectation of SubscriptionAPI.Create
+type SubscriptionAPICreateCall struct {
+	*mock.Call
+}
+
+// OnCreate expects a call of Create. Each argument is a value or a matcher such as mock.Anything.
+func (m *SubscriptionAPI) OnCreate(ctx any, request any) *SubscriptionAPICreateCall {
+	return &SubscriptionAPICreateCall{Call: m.On("Create", ctx, request)}
+}
+
+// Return sets the values returned by the call
+func (c *SubscriptionAPICreateCall) Return(err error) *SubscriptionAPICreateCall {
+	c.Call.Return(err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *SubscriptionAPICreateCall) RunAndReturn(f func(context.Context, v1.CreateSubscriptionReq) error) *SubscriptionAPICreateCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *SubscriptionAPICreateCall) Once() *SubscriptionAPICreateCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *SubscriptionAPICreateCall) Times(n int) *SubscriptionAPICreateCall {
+	c.Call.Times(n)
+	return c
+}
+
+// Delete implements workflows.SubscriptionAPI
+func (m *SubscriptionAPI) Delete(ctx context.Context) error {
+	ret := m.Called(ctx)
+
+	if f, ok := ret.Get(0).(func(context.Context) error); ok {
+		return f(ctx)
+	}
+
+	return ret.Error(0)
+}
+
+// SubscriptionAPIDeleteCall is an expectation of SubscriptionAPI.Delete
+type SubscriptionAPIDeleteCall struct {
+	*mock.Call
+}
+
+// OnDelete expects a call of Delete. Each argument is a value or a matcher such as mock.Anything.
+func (m *SubscriptionAPI) OnDelete(ctx any) *SubscriptionAPIDeleteCall {
+	return &SubscriptionAPIDeleteCall{Call: m.On("Delete", ctx)}
+}
+
+// Return sets the values returned by the call
+func (c *SubscriptionAPIDeleteCall) Return(err error) *SubscriptionAPIDeleteCall {
+	c.Call.Return(err)
+	return c
+}
+
+// RunAndReturn sets a function computing the values returned by the call
+func (c *SubscriptionAPIDeleteCall) RunAndReturn(f func(context.Context) error) *SubscriptionAPIDeleteCall {
+	c.Call.Return(f)
+	return c
+}
+
+// Once expects the call only once
+func (c *SubscriptionAPIDeleteCall) Once() *SubscriptionAPIDeleteCall {
+	c.Call.Once()
+	return c
+}
+
+// Times expects the call n times
+func (c *SubscriptionAPIDeleteCall) Times(n int) *SubscriptionAPIDeleteCall {
+	c.Call.Times(n)
+	return c
+}
@@ -0,0 +1,87 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflowsmock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/sacloud/workflows-api-go/workflowsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowAPI(t *testing.T) {
+	api := workflowsmock.NewWorkflowAPI(t)
+	client := &workflows.Client{Workflows: api}
+
+	workflow := workflowsmock.Workflow{ID: "123456789012", Name: "example", Tags: []string{"env=prod"}}
+	api.OnRead(mock.Anything, "123456789012").Return(workflow.Get(), nil).Once()
+	api.OnList(mock.Anything, mock.MatchedBy(func(p v1.ListWorkflowParams) bool {
+		return p.Published.Or(false)
+	})).Return(workflowsmock.WorkflowList(workflow), nil)
+	api.OnDelete(mock.Anything, mock.Anything).Return(errors.New("conflict"))
+
+	read, err := client.Workflows.Read(t.Context(), "123456789012")
+	require.NoError(t, err)
+	assert.Equal(t, "example", read.Name)
+	assert.Equal(t, []v1.GetWorkflowOKWorkflowTagsItem{{Name: "env=prod"}}, read.Tags)
+
+	list, err := client.Workflows.List(t.Context(), v1.ListWorkflowParams{Published: v1.NewOptBool(true)})
+	require.NoError(t, err)
+	require.Len(t, list.Workflows, 1)
+	assert.Equal(t, "123456789012", list.Workflows[0].ID)
+
+	require.Error(t, client.Workflows.Delete(t.Context(), "123456789012"))
+	api.AssertCalled(t, "Delete", mock.Anything, "123456789012")
+	assert.Len(t, api.Calls, 3)
+}
+
+func TestExecutionAPI_RunAndReturn(t *testing.T) {
+	api := workflowsmock.NewExecutionAPI(t)
+
+	api.OnCreate(mock.Anything, "123456789012", mock.Anything).RunAndReturn(
+		func(ctx context.Context, workflowID string, req v1.OptCreateExecutionReq) (*v1.CreateExecutionCreatedExecution, error) {
+			return workflowsmock.Execution{
+				ID:       "exec-1",
+				Workflow: workflowsmock.Workflow{ID: workflowID},
+				Args:     req.Value.Args.Value,
+				Status:   "Running",
+			}.Created(), nil
+		}).Times(2)
+
+	for range 2 {
+		created, err := api.Create(t.Context(), "123456789012", v1.NewOptCreateExecutionReq(v1.CreateExecutionReq{Args: v1.NewOptString(`{}`)}))
+		require.NoError(t, err)
+		assert.Equal(t, "123456789012", created.Workflow.ID)
+		assert.Equal(t, v1.CreateExecutionCreatedExecutionStatusRunning, created.Status)
+		assert.Equal(t, `{}`, created.Args)
+	}
+}
+
+func TestSubscriptionAPI(t *testing.T) {
+	api := workflowsmock.NewSubscriptionAPI(t)
+
+	api.OnListPlans(mock.Anything).Return(workflowsmock.Plans(10, v1.ListPlansOKPlansItem{ID: 1, Name: "basic"}), nil)
+	api.OnCreate(mock.Anything, v1.CreateSubscriptionReq{PlanId: 1}).Return(nil)
+
+	plans, err := api.ListPlans(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, api.Create(t.Context(), v1.CreateSubscriptionReq{PlanId: plans.Plans[0].ID}))
+}
@@ -0,0 +1,146 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+)
+
+const (
+	// DefaultZone DefaultAPIRootURLが指すゾーン
+	DefaultZone = "tk1b"
+	// apiRootURLFormat ゾーンごとのAPIルートURLの書式
+	apiRootURLFormat = "https://secure.sakura.ad.jp/cloud/zone/%s/api/workflow/1.0/"
+)
+
+// Zones 指定可能なゾーンの一覧
+var Zones = []string{"is1a", "is1b", "is1c", "tk1a", "tk1b", "tk1v"}
+
+// APIRootURLForZone returns the API root URL of the given zone
+func APIRootURLForZone(zone string) (string, error) {
+	if !slices.Contains(Zones, zone) {
+		return "", NewError(fmt.Sprintf("unknown zone %q (must be one of %s)", zone, strings.Join(Zones, ", ")), nil)
+	}
+	return fmt.Sprintf(apiRootURLFormat, zone), nil
+}
+
+// MultiZoneClient 複数ゾーンのClientをまとめたもの
+type MultiZoneClient struct {
+	zones   []string
+	clients map[string]*Client
+}
+
+// ZonedWorkflow ゾーン情報付きのワークフロー
+type ZonedWorkflow struct {
+	Zone     string
+	Workflow v1.ListWorkflowOKWorkflowsItem
+}
+
+// NewMultiZoneClient creates a Client for each zone with the given options.
+// WithAPIRootURL takes precedence over the zone, so it is rejected when more than one zone is given.
+func NewMultiZoneClient(zones []string, opts ...Option) (*MultiZoneClient, error) {
+	if len(zones) == 0 {
+		return nil, NewError("no zones given", nil)
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if distinct := slices.Compact(slices.Sorted(slices.Values(zones))); o.apiRootURL != "" && len(distinct) > 1 {
+		return nil, NewError("WithAPIRootURL cannot be used with more than one zone", nil)
+	}
+
+	m := &MultiZoneClient{clients: make(map[string]*Client, len(zones))}
+	for _, zone := range zones {
+		if _, ok := m.clients[zone]; ok {
+			continue
+		}
+		client, err := New(append(slices.Clone(opts), WithZone(zone))...)
+		if err != nil {
+			return nil, err
+		}
+		m.zones = append(m.zones, zone)
+		m.clients[zone] = client
+	}
+	return m, nil
+}
+
+// Zones returns the zones of the client in the given order
+func (m *MultiZoneClient) Zones() []string {
+	return slices.Clone(m.zones)
+}
+
+// Zone returns the Client of the given zone, or nil if the zone is not managed by m
+func (m *MultiZoneClient) Zone(zone string) *Client {
+	return m.clients[zone]
+}
+
+// ListWorkflows lists workflows of every zone concurrently, following the pages of each zone.
+// If params.Page is set only that page of each zone is listed.
+// Results of the zones that succeeded are returned even if some zones failed; a zone failing midway
+// contributes the pages listed before the failure.
+func (m *MultiZoneClient) ListWorkflows(ctx context.Context, params v1.ListWorkflowParams) ([]ZonedWorkflow, error) {
+	results := make([][]ZonedWorkflow, len(m.zones))
+	errs := make([]error, len(m.zones))
+
+	var wg sync.WaitGroup
+	for i, zone := range m.zones {
+		wg.Go(func() {
+			results[i], errs[i] = m.listZone(ctx, zone, params)
+		})
+	}
+	wg.Wait()
+
+	return slices.Concat(results...), errors.Join(errs...)
+}
+
+// listZone lists the workflows of a zone page by page
+func (m *MultiZoneClient) listZone(ctx context.Context, zone string, params v1.ListWorkflowParams) ([]ZonedWorkflow, error) {
+	_, single := params.Page.Get()
+	limit := params.PageLimit.Or(bulkPageLimit)
+	params.PageLimit = v1.NewOptInt(limit)
+
+	var ret []ZonedWorkflow
+	for page, seen := params.Page.Or(1), 0; ; page++ {
+		params.Page = v1.NewOptInt(page)
+		res, err := m.clients[zone].Workflows.List(ctx, params)
+		if err != nil {
+			return ret, NewError("zone "+zone, err)
+		}
+		for _, workflow := range res.Workflows {
+			ret = append(ret, ZonedWorkflow{Zone: zone, Workflow: workflow})
+		}
+		seen += len(res.Workflows)
+		if single || len(res.Workflows) < limit || seen >= res.Total {
+			return ret, nil
+		}
+	}
+}
+
+// SearchWorkflows lists workflows whose name partially matches the given name across every zone
+func (m *MultiZoneClient) SearchWorkflows(ctx context.Context, name string) ([]ZonedWorkflow, error) {
+	return m.ListWorkflows(ctx, v1.ListWorkflowParams{
+		Name:          v1.NewOptString(name),
+		NameMatchType: v1.NewOptListWorkflowNameMatchType(v1.ListWorkflowNameMatchTypePartial),
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright 2025- The sacloud/workflows-api-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflows_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sacloud/workflows-api-go"
+	v1 "github.com/sacloud/workflows-api-go/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIRootURLForZone(t *testing.T) {
+	url, err := workflows.APIRootURLForZone("is1b")
+	require.NoError(t, err)
+	assert.Equal(t, "https://secure.sakura.ad.jp/cloud/zone/is1b/api/workflow/1.0/", url)
+
+	url, err = workflows.APIRootURLForZone(workflows.DefaultZone)
+	require.NoError(t, err)
+	assert.Equal(t, workflows.DefaultAPIRootURL, url)
+
+	_, err = workflows.APIRootURLForZone("xx1z")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown zone "xx1z"`)
+
+	_, err = workflows.New(workflows.WithZone("xx1z"))
+	require.Error(t, err)
+}
+
+// doerFunc is an ht.Client serving requests in process
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestMultiZoneClient_ListWorkflows(t *testing.T) {
+	doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		switch {
+		case strings.Contains(req.URL.Path, "/zone/is1b/"):
+			writeJSON(rec, http.StatusOK, `{"is_ok":true,"Total":1,"From":0,"Count":1,"Workflows":[`+testWorkflowJSON+`]}`)
+		case strings.Contains(req.URL.Path, "/zone/tk1b/"):
+			writeJSON(rec, http.StatusInternalServerError, `{"is_ok":false,"Message":"T-0000 Temporary system error"}`)
+		default:
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		return rec.Result(), nil
+	})
+
+	client, err := workflows.NewMultiZoneClient([]string{"is1b", "tk1b", "is1b"}, workflows.WithHTTPClient(doer))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"is1b", "tk1b"}, client.Zones())
+	assert.NotNil(t, client.Zone("is1b"))
+	assert.Nil(t, client.Zone("tk1a"))
+
+	found, err := client.SearchWorkflows(t.Context(), "test")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "zone tk1b")
+	require.Len(t, found, 1)
+	assert.Equal(t, "is1b", found[0].Zone)
+	assert.Equal(t, "test-workflow", found[0].Workflow.Name)
+}
+
+func TestMultiZoneClient_ListWorkflows_pages(t *testing.T) {
+	var pages []string
+	doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		pages = append(pages, req.URL.Query().Get("Page")+"/"+req.URL.Query().Get("PageLimit"))
+		switch req.URL.Query().Get("Page") {
+		case "1":
+			writeJSON(rec, http.StatusOK, `{"is_ok":true,"Total":3,"From":0,"Count":2,"Workflows":[`+testWorkflowJSON+`,`+testWorkflowJSON+`]}`)
+		case "2":
+			writeJSON(rec, http.StatusOK, `{"is_ok":true,"Total":3,"From":2,"Count":1,"Workflows":[`+testWorkflowJSON+`]}`)
+		default:
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		return rec.Result(), nil
+	})
+
+	client, err := workflows.NewMultiZoneClient([]string{"is1b"}, workflows.WithHTTPClient(doer))
+	require.NoError(t, err)
+
+	found, err := client.ListWorkflows(t.Context(), v1.ListWorkflowParams{PageLimit: v1.NewOptInt(2)})
+	require.NoError(t, err)
+	assert.Len(t, found, 3)
+	assert.Equal(t, []string{"1/2", "2/2"}, pages)
+
+	pages = nil
+	found, err = client.ListWorkflows(t.Context(), v1.ListWorkflowParams{Page: v1.NewOptInt(2), PageLimit: v1.NewOptInt(2)})
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, []string{"2/2"}, pages)
+}
+
+func TestNewMultiZoneClient_apiRootURL(t *testing.T) {
+	_, err := workflows.NewMultiZoneClient([]string{"is1b", "tk1b"}, workflows.WithAPIRootURL("http://localhost/"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "more than one zone")
+
+	client, err := workflows.NewMultiZoneClient([]string{"is1b", "is1b"}, workflows.WithAPIRootURL("http://localhost/"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"is1b"}, client.Zones())
+}